@@ -1,11 +1,10 @@
 package main
 
 import (
-	"os"
-	"strconv"
 	"time"
 
 	"github.com/form3tech-oss/f1/v2/pkg/f1"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
 	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
 )
 
@@ -13,7 +12,11 @@ func main() {
 	f1.New().
 		Add("emptyScenario", emptyScenario).
 		Add("failingScenario", failingScenario).
-		Add("sleepScenario", sleepScenario).
+		Add("sleepScenario", sleepScenario, scenarios.Parameter(scenarios.ScenarioParameter{
+			Name:        "ms-sleep",
+			Description: "milliseconds to sleep in each iteration",
+			Default:     "0",
+		})).
 		Add("logScenario", logScenario).
 		Execute()
 }
@@ -27,9 +30,7 @@ func emptyScenario(*testing.T) testing.RunFn {
 }
 
 func sleepScenario(t *testing.T) testing.RunFn {
-	msString := os.Getenv("MS_SLEEP")
-	ms, err := strconv.ParseInt(msString, 10, 64)
-	t.Require().NoError(err)
+	ms := t.ParamInt("ms-sleep")
 
 	runFn := func(*testing.T) {
 		time.Sleep(time.Duration(ms) * time.Millisecond)
@@ -0,0 +1,39 @@
+package analyze
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+func Cmd(output *ui.Output) *cobra.Command {
+	return &cobra.Command{
+		Use:   "analyze <events file>",
+		Short: "Summarises a run from the file written with --events-file",
+		Args:  cobra.ExactArgs(1),
+		RunE:  analyzeCmdExecute(output),
+	}
+}
+
+func analyzeCmdExecute(output *ui.Output) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+
+		file, err := os.Open(args[0])
+		if err != nil {
+			return fmt.Errorf("opening events file: %w", err)
+		}
+		defer file.Close()
+
+		report, err := Analyze(file)
+		if err != nil {
+			return err
+		}
+
+		output.Printer.Println(report.Render())
+		return nil
+	}
+}
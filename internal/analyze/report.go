@@ -0,0 +1,255 @@
+package analyze
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/events"
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+	"github.com/form3tech-oss/f1/v2/internal/progress"
+)
+
+const maxTopFailures = 5
+
+// Report is the summary of a run rebuilt from its events file.
+type Report struct {
+	Start               time.Time
+	SuccessfulDurations Percentiles
+	FailedDurations     Percentiles
+	StartDelays         Percentiles
+	Stages              []StagePercentiles
+	TopFailures         []progress.FailureReason
+	Timeline            []Second
+	Duration            time.Duration
+	Successful          uint64
+	Failed              uint64
+	Dropped             uint64
+}
+
+// Percentiles summarises a set of durations.
+type Percentiles struct {
+	Count   int
+	Average time.Duration
+	Min     time.Duration
+	P50     time.Duration
+	P90     time.Duration
+	P95     time.Duration
+	P99     time.Duration
+	Max     time.Duration
+}
+
+type StagePercentiles struct {
+	Name string
+	Percentiles
+}
+
+// Second is the iterations scheduled within a second of the run.
+type Second struct {
+	Offset     time.Duration
+	Average    time.Duration
+	Max        time.Duration
+	Successful uint64
+	Failed     uint64
+	Dropped    uint64
+}
+
+// sample is the part of an event needed to build the timeline
+type sample struct {
+	result      string
+	scheduledAt int64
+	duration    int64
+}
+
+// Analyze reads the events in r and summarises them.
+func Analyze(r io.Reader) (*Report, error) {
+	var (
+		successful, failed, startDelays []int64
+		stages                          = map[string][]int64{}
+		stageNames                      []string
+		samples                         []sample
+		failures                        progress.Failures
+		firstScheduled                  = int64(math.MaxInt64)
+		lastFinished                    = int64(math.MinInt64)
+		report                          = &Report{}
+	)
+
+	err := events.Read(r, func(event events.Event) error {
+		firstScheduled = min(firstScheduled, event.ScheduledAt)
+		lastFinished = max(lastFinished, event.ScheduledAt, event.StartedAt+event.Duration)
+
+		samples = append(samples, sample{result: event.Result, scheduledAt: event.ScheduledAt, duration: event.Duration})
+
+		switch event.Result {
+		case metrics.DroppedResult.String():
+			report.Dropped++
+			return nil
+		case metrics.FailedResult.String():
+			report.Failed++
+			failed = append(failed, event.Duration)
+			failures.Record(event.Failure, strconv.FormatUint(event.Iteration, 10))
+		default:
+			report.Successful++
+			successful = append(successful, event.Duration)
+		}
+
+		startDelays = append(startDelays, max(0, event.StartedAt-event.ScheduledAt))
+
+		for _, stage := range event.Stages {
+			if _, ok := stages[stage.Name]; !ok {
+				stageNames = append(stageNames, stage.Name)
+			}
+			stages[stage.Name] = append(stages[stage.Name], stage.Duration)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("analyzing events: %w", err)
+	}
+
+	if len(samples) == 0 {
+		return report, nil
+	}
+
+	report.Start = time.Unix(0, firstScheduled)
+	report.Duration = time.Duration(lastFinished - firstScheduled)
+	report.SuccessfulDurations = percentiles(successful)
+	report.FailedDurations = percentiles(failed)
+	report.StartDelays = percentiles(startDelays)
+	report.TopFailures = failures.Top(maxTopFailures)
+
+	for _, name := range stageNames {
+		report.Stages = append(report.Stages, StagePercentiles{Name: name, Percentiles: percentiles(stages[name])})
+	}
+
+	report.Timeline = timeline(samples, firstScheduled)
+
+	return report, nil
+}
+
+// timeline groups the samples by the second of the run they were scheduled in.
+func timeline(samples []sample, start int64) []Second {
+	var (
+		res    []Second
+		totals []int64
+	)
+
+	for _, sample := range samples {
+		idx := int((sample.scheduledAt - start) / int64(time.Second))
+		for len(res) <= idx {
+			res = append(res, Second{Offset: time.Duration(len(res)) * time.Second})
+			totals = append(totals, 0)
+		}
+
+		entry := &res[idx]
+		switch sample.result {
+		case metrics.DroppedResult.String():
+			entry.Dropped++
+			continue
+		case metrics.FailedResult.String():
+			entry.Failed++
+		default:
+			entry.Successful++
+		}
+
+		totals[idx] += sample.duration
+		entry.Max = max(entry.Max, time.Duration(sample.duration))
+	}
+
+	for i := range res {
+		if started := res[i].Successful + res[i].Failed; started > 0 {
+			res[i].Average = time.Duration(totals[i] / int64(started))
+		}
+	}
+
+	return res
+}
+
+func percentiles(durations []int64) Percentiles {
+	if len(durations) == 0 {
+		return Percentiles{}
+	}
+
+	slices.Sort(durations)
+
+	var total int64
+	for _, d := range durations {
+		total += d
+	}
+
+	return Percentiles{
+		Count:   len(durations),
+		Average: time.Duration(total / int64(len(durations))),
+		Min:     time.Duration(durations[0]),
+		P50:     percentile(durations, 0.5),
+		P90:     percentile(durations, 0.9),
+		P95:     percentile(durations, 0.95),
+		P99:     percentile(durations, 0.99),
+		Max:     time.Duration(durations[len(durations)-1]),
+	}
+}
+
+// percentile returns the nearest rank percentile p of the sorted durations.
+func percentile(sorted []int64, p float64) time.Duration {
+	rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+	return time.Duration(sorted[max(0, rank)])
+}
+
+// Render formats the report as text.
+func (r *Report) Render() string {
+	var sb strings.Builder
+
+	iterations := r.Successful + r.Failed + r.Dropped
+	fmt.Fprintf(&sb, "%d iterations in %s, from %s\n", iterations, r.Duration.Round(time.Millisecond),
+		r.Start.Format(time.RFC3339))
+	fmt.Fprintf(&sb, "Successful: %d, Failed: %d, Dropped: %d\n\n", r.Successful, r.Failed, r.Dropped)
+
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "\tcount\tavg\tmin\tp50\tp90\tp95\tp99\tmax\t")
+	writePercentiles(w, "successful", r.SuccessfulDurations)
+	writePercentiles(w, "failed", r.FailedDurations)
+	writePercentiles(w, "start delay", r.StartDelays)
+	for _, stage := range r.Stages {
+		writePercentiles(w, "stage "+stage.Name, stage.Percentiles)
+	}
+	_ = w.Flush()
+
+	if len(r.TopFailures) > 0 {
+		sb.WriteString("\nTop failure causes:\n")
+		for _, failure := range r.TopFailures {
+			fmt.Fprintf(&sb, "  %d× %s (first seen in iteration %s)\n", failure.Count, failure.Reason, failure.FirstIteration)
+		}
+	}
+
+	if len(r.Timeline) > 0 {
+		sb.WriteString("\nTimeline:\n")
+		w = tabwriter.NewWriter(&sb, 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(w, "second\tsuccessful\tfailed\tdropped\tavg\tmax\t")
+		for _, s := range r.Timeline {
+			fmt.Fprintf(w, "%d\t%d\t%d\t%d\t%s\t%s\t\n",
+				int(s.Offset.Seconds()), s.Successful, s.Failed, s.Dropped, round(s.Average), round(s.Max))
+		}
+		_ = w.Flush()
+	}
+
+	return sb.String()
+}
+
+func writePercentiles(w io.Writer, name string, p Percentiles) {
+	if p.Count == 0 {
+		return
+	}
+
+	fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t\n", name, p.Count,
+		round(p.Average), round(p.Min), round(p.P50), round(p.P90), round(p.P95), round(p.P99), round(p.Max))
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(time.Microsecond)
+}
@@ -0,0 +1,67 @@
+package analyze_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/analyze"
+	"github.com/form3tech-oss/f1/v2/internal/progress"
+)
+
+const eventsFile = `{"result":"success","stages":[{"name":"call","duration_ns":1000000}],"iteration":1,"vuid":0,"scheduled_at":1000000000,"started_at":1000000000,"duration_ns":2000000}
+{"result":"fail","failure":"boom","iteration":2,"vuid":1,"scheduled_at":1000000000,"started_at":1001000000,"duration_ns":4000000}
+{"result":"success","stages":[{"name":"call","duration_ns":3000000}],"iteration":3,"vuid":0,"scheduled_at":2000000000,"started_at":2000000000,"duration_ns":6000000}
+{"result":"dropped","vuid":-1,"scheduled_at":2000000000,"duration_ns":0}
+`
+
+func TestAnalyze_RebuildsTheSummary(t *testing.T) {
+	t.Parallel()
+
+	report, err := analyze.Analyze(strings.NewReader(eventsFile))
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(2), report.Successful)
+	assert.Equal(t, uint64(1), report.Failed)
+	assert.Equal(t, uint64(1), report.Dropped)
+	assert.Equal(t, time.Unix(1, 0), report.Start)
+	assert.Equal(t, 1006*time.Millisecond, report.Duration)
+
+	assert.Equal(t, analyze.Percentiles{
+		Count:   2,
+		Average: 4 * time.Millisecond,
+		Min:     2 * time.Millisecond,
+		P50:     2 * time.Millisecond,
+		P90:     6 * time.Millisecond,
+		P95:     6 * time.Millisecond,
+		P99:     6 * time.Millisecond,
+		Max:     6 * time.Millisecond,
+	}, report.SuccessfulDurations)
+	assert.Equal(t, 1, report.FailedDurations.Count)
+	assert.Equal(t, time.Millisecond, report.StartDelays.Max)
+
+	require.Len(t, report.Stages, 1)
+	assert.Equal(t, "call", report.Stages[0].Name)
+	assert.Equal(t, 2*time.Millisecond, report.Stages[0].Average)
+
+	assert.Equal(t, []progress.FailureReason{{Reason: "boom", FirstIteration: "2", Count: 1}}, report.TopFailures)
+
+	assert.Equal(t, []analyze.Second{
+		{Offset: 0, Successful: 1, Failed: 1, Average: 3 * time.Millisecond, Max: 4 * time.Millisecond},
+		{Offset: time.Second, Successful: 1, Dropped: 1, Average: 6 * time.Millisecond, Max: 6 * time.Millisecond},
+	}, report.Timeline)
+
+	rendered := report.Render()
+	assert.Contains(t, rendered, "4 iterations in 1.006s")
+	assert.Contains(t, rendered, "1× boom (first seen in iteration 2)")
+}
+
+func TestAnalyze_ReportsInvalidLines(t *testing.T) {
+	t.Parallel()
+
+	_, err := analyze.Analyze(strings.NewReader("{\"result\":\"success\"}\nnot json\n"))
+	require.ErrorContains(t, err, "line 2")
+}
@@ -0,0 +1,276 @@
+// Package callbacks receives the HTTP callbacks, such as webhooks, awaited by the iterations of a
+// scenario, matching them to iterations by a correlation id.
+package callbacks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/jsonpath"
+)
+
+// maxBodySize caps the size of the callbacks read.
+const maxBodySize = 1024 * 1024
+
+const readHeaderTimeout = 10 * time.Second
+
+// Result is what happened to a callback.
+type Result string
+
+const (
+	// Received callbacks were awaited by an iteration.
+	Received Result = "received"
+	// Missing callbacks weren't received before the iteration awaiting them timed out.
+	Missing Result = "missing"
+	// Unexpected callbacks weren't awaited by any iteration, had no correlation id, or were received
+	// more than once.
+	Unexpected Result = "unexpected"
+)
+
+// ErrTimeout is returned by Await when the callback isn't received in time.
+var ErrTimeout = errors.New("callback not received")
+
+// Config configures a Listener.
+type Config struct {
+	// Address is the address the listener binds, such as :8080.
+	Address string
+	// IDHeader is the header holding the correlation id of callbacks.
+	IDHeader string
+	// IDPath is the json path of the correlation id in the body of callbacks, such as data.id,
+	// when IDHeader isn't set.
+	IDPath string
+}
+
+// Callback is a callback received by the listener.
+type Callback struct {
+	Received time.Time
+	Header   http.Header
+	Body     []byte
+}
+
+// Counts are the number of callbacks with each Result.
+type Counts struct {
+	Received   uint64
+	Missing    uint64
+	Unexpected uint64
+}
+
+// Total returns the number of callbacks counted.
+func (c Counts) Total() uint64 {
+	return c.Received + c.Missing + c.Unexpected
+}
+
+// awaited is a callback awaited by an iteration.
+type awaited struct {
+	registered time.Time
+	done       chan struct{}
+	callback   Callback
+}
+
+// Listener receives callbacks, and hands them to the iterations awaiting them. It is safe for
+// concurrent use.
+type Listener struct {
+	server     *http.Server
+	listener   net.Listener
+	onResult   func(Result)
+	awaiting   map[string]*awaited
+	config     Config
+	served     chan struct{}
+	mu         sync.Mutex
+	received   atomic.Uint64
+	missing    atomic.Uint64
+	unexpected atomic.Uint64
+}
+
+// Listen starts a listener receiving callbacks at config.Address. onResult, if set, is called
+// with the result of each callback.
+func Listen(config Config, onResult func(Result)) (*Listener, error) {
+	if config.IDHeader == "" && config.IDPath == "" {
+		return nil, errors.New("callbacks need an id header or an id path")
+	}
+
+	netListener, err := (&net.ListenConfig{}).Listen(context.Background(), "tcp", config.Address)
+	if err != nil {
+		return nil, fmt.Errorf("listening for callbacks: %w", err)
+	}
+
+	l := &Listener{
+		listener: netListener,
+		onResult: onResult,
+		awaiting: make(map[string]*awaited),
+		config:   config,
+		served:   make(chan struct{}),
+	}
+	l.server = &http.Server{
+		Handler:           http.HandlerFunc(l.handle),
+		ReadHeaderTimeout: readHeaderTimeout,
+	}
+
+	go func() {
+		defer close(l.served)
+		_ = l.server.Serve(netListener)
+	}()
+
+	return l, nil
+}
+
+// Addr returns the address the listener is bound to, such as 127.0.0.1:8080.
+func (l *Listener) Addr() string {
+	return l.listener.Addr().String()
+}
+
+// Close stops the listener.
+func (l *Listener) Close() error {
+	err := l.server.Close()
+	<-l.served
+	if err != nil {
+		return fmt.Errorf("closing callback listener: %w", err)
+	}
+
+	return nil
+}
+
+// Counts returns the number of callbacks with each result.
+func (l *Listener) Counts() Counts {
+	return Counts{
+		Received:   l.received.Load(),
+		Missing:    l.missing.Load(),
+		Unexpected: l.unexpected.Load(),
+	}
+}
+
+// Expect registers id, so that its callback is kept if it is received before Await is called, and
+// the latency returned by Await starts now.
+func (l *Listener) Expect(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.expect(id)
+}
+
+func (l *Listener) expect(id string) *awaited {
+	a, ok := l.awaiting[id]
+	if !ok {
+		a = &awaited{registered: time.Now(), done: make(chan struct{})}
+		l.awaiting[id] = a
+	}
+
+	return a
+}
+
+// Forget unregisters id, if it wasn't awaited.
+func (l *Listener) Forget(id string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.awaiting, id)
+}
+
+// Await waits for the callback with id, and returns it with the time since id was registered
+// with Expect, or since Await was called. It returns ErrTimeout if the callback isn't received
+// within timeout, or the error of ctx if it is done first.
+func (l *Listener) Await(ctx context.Context, id string, timeout time.Duration) (Callback, time.Duration, error) {
+	l.mu.Lock()
+	a := l.expect(id)
+	l.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case <-a.done:
+		l.Forget(id)
+		return a.callback, a.callback.Received.Sub(a.registered), nil
+	case <-timer.C:
+		l.Forget(id)
+		l.record(Missing)
+		return Callback{}, time.Since(a.registered), ErrTimeout
+	case <-ctx.Done():
+		l.Forget(id)
+		return Callback{}, time.Since(a.registered), fmt.Errorf("awaiting callback: %w", ctx.Err())
+	}
+}
+
+func (l *Listener) handle(w http.ResponseWriter, r *http.Request) {
+	received := time.Now()
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		l.record(Unexpected)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	callback := Callback{Received: received, Header: r.Header, Body: body}
+	if !l.deliver(l.id(r, body), callback) {
+		l.record(Unexpected)
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// id returns the correlation id of a callback, or an empty string if it has none.
+func (l *Listener) id(r *http.Request, body []byte) string {
+	if l.config.IDHeader != "" {
+		return r.Header.Get(l.config.IDHeader)
+	}
+
+	var document any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil {
+		return ""
+	}
+
+	id, err := jsonpath.Lookup(document, l.config.IDPath)
+	if err != nil {
+		return ""
+	}
+
+	return id
+}
+
+// deliver hands callback to the iteration awaiting id, and returns whether there was one.
+func (l *Listener) deliver(id string, callback Callback) bool {
+	if id == "" {
+		return false
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	a, ok := l.awaiting[id]
+	if !ok || !a.callback.Received.IsZero() {
+		return false
+	}
+
+	a.callback = callback
+	close(a.done)
+	l.record(Received)
+
+	return true
+}
+
+func (l *Listener) record(result Result) {
+	switch result {
+	case Received:
+		l.received.Add(1)
+	case Missing:
+		l.missing.Add(1)
+	case Unexpected:
+		l.unexpected.Add(1)
+	}
+
+	if l.onResult != nil {
+		l.onResult(result)
+	}
+}
@@ -0,0 +1,112 @@
+package callbacks_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/callbacks"
+)
+
+func TestListener_MatchesCallbacksByHeader(t *testing.T) {
+	t.Parallel()
+
+	var results []callbacks.Result
+	listener := listen(t, callbacks.Config{Address: "127.0.0.1:0", IDHeader: "X-Request-Id"}, func(r callbacks.Result) {
+		results = append(results, r)
+	})
+
+	listener.Expect("req-1")
+	post(t, listener, map[string]string{"X-Request-Id": "req-1"}, "settled")
+
+	callback, latency, err := listener.Await(context.Background(), "req-1", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "settled", string(callback.Body))
+	assert.Equal(t, "req-1", callback.Header.Get("X-Request-Id"))
+	assert.Positive(t, latency)
+
+	// callbacks which were already received, or weren't expected, are unexpected
+	post(t, listener, map[string]string{"X-Request-Id": "req-1"}, "settled")
+	post(t, listener, nil, "settled")
+
+	assert.Equal(t, callbacks.Counts{Received: 1, Missing: 0, Unexpected: 2}, listener.Counts())
+	assert.Equal(t, []callbacks.Result{callbacks.Received, callbacks.Unexpected, callbacks.Unexpected}, results)
+}
+
+func TestListener_MatchesCallbacksByJSONPath(t *testing.T) {
+	t.Parallel()
+
+	listener := listen(t, callbacks.Config{Address: "127.0.0.1:0", IDPath: "data.payments.0.id"}, nil)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		post(t, listener, nil, `{"data": {"payments": [{"id": 42}]}}`)
+	}()
+
+	callback, _, err := listener.Await(context.Background(), "42", time.Second)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"data": {"payments": [{"id": 42}]}}`, string(callback.Body))
+
+	post(t, listener, nil, "not json")
+	assert.Equal(t, uint64(1), listener.Counts().Unexpected)
+}
+
+func TestListener_CountsMissingCallbacks(t *testing.T) {
+	t.Parallel()
+
+	listener := listen(t, callbacks.Config{Address: "127.0.0.1:0", IDHeader: "X-Request-Id"}, nil)
+
+	_, _, err := listener.Await(context.Background(), "req-1", 10*time.Millisecond)
+	require.ErrorIs(t, err, callbacks.ErrTimeout)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = listener.Await(ctx, "req-2", time.Second)
+	require.ErrorIs(t, err, context.Canceled)
+
+	// a callback received after its iteration stopped waiting is unexpected
+	post(t, listener, map[string]string{"X-Request-Id": "req-1"}, "")
+
+	assert.Equal(t, callbacks.Counts{Received: 0, Missing: 1, Unexpected: 1}, listener.Counts())
+}
+
+func TestListen_RequiresACorrelationID(t *testing.T) {
+	t.Parallel()
+
+	_, err := callbacks.Listen(callbacks.Config{Address: "127.0.0.1:0"}, nil)
+	require.EqualError(t, err, "callbacks need an id header or an id path")
+}
+
+func listen(t *testing.T, config callbacks.Config, onResult func(callbacks.Result)) *callbacks.Listener {
+	t.Helper()
+
+	listener, err := callbacks.Listen(config, onResult)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, listener.Close())
+	})
+
+	return listener
+}
+
+func post(t *testing.T, listener *callbacks.Listener, headers map[string]string, body string) {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(
+		context.Background(), http.MethodPost, "http://"+listener.Addr(), strings.NewReader(body),
+	)
+	require.NoError(t, err)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusAccepted, res.StatusCode)
+	require.NoError(t, res.Body.Close())
+}
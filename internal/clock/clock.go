@@ -0,0 +1,42 @@
+// Package clock abstracts the passing of time from the triggers and worker pools, so that they run
+// on the Real clock in load tests, and on a Virtual clock in simulations.
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+// Clock is the time of the triggers and worker pools.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// Since returns the time passed since t.
+	Since(t time.Time) time.Duration
+	// NewTicker returns a Ticker which ticks every d.
+	NewTicker(d time.Duration) Ticker
+	// Sleep waits for d to pass, and returns false if ctx is done first.
+	Sleep(ctx context.Context, d time.Duration) bool
+	// Wait waits until ctx is done.
+	Wait(ctx context.Context)
+	// WithCancel returns a copy of ctx which is cancelled by the returned function.
+	WithCancel(ctx context.Context) (context.Context, context.CancelFunc)
+	// WithTimeout returns a copy of ctx which is cancelled once d has passed.
+	WithTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc)
+	// Go runs fn in a new goroutine. The waits of the clock must be called only from goroutines
+	// started with Go.
+	Go(fn func())
+	// Park marks the calling goroutine as waiting for another goroutine started with Go, rather than
+	// for the clock, until that goroutine calls Unpark.
+	Park()
+	// Unpark marks n goroutines which called Park as running again, before they are woken.
+	Unpark(n int)
+}
+
+// Ticker delivers ticks at intervals.
+type Ticker interface {
+	// Wait waits for the next tick and returns its time, or returns false if ctx is done first.
+	Wait(ctx context.Context) (time.Time, bool)
+	// Stop turns off the ticker.
+	Stop()
+}
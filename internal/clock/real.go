@@ -0,0 +1,74 @@
+package clock
+
+import (
+	"context"
+	"time"
+)
+
+// Real is the clock of the system.
+type Real struct{}
+
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+func (Real) Since(t time.Time) time.Duration {
+	return time.Since(t)
+}
+
+func (Real) NewTicker(d time.Duration) Ticker {
+	return realTicker{ticker: time.NewTicker(d)}
+}
+
+func (Real) Sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+
+	return ctx.Err() == nil
+}
+
+func (Real) Wait(ctx context.Context) {
+	<-ctx.Done()
+}
+
+func (Real) WithCancel(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithCancel(ctx)
+}
+
+func (Real) WithTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, d)
+}
+
+func (Real) Go(fn func()) {
+	go fn()
+}
+
+func (Real) Park() {}
+
+func (Real) Unpark(int) {}
+
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+func (t realTicker) Wait(ctx context.Context) (time.Time, bool) {
+	select {
+	case <-ctx.Done():
+		return time.Time{}, false
+	case tick := <-t.ticker.C:
+		return tick, true
+	}
+}
+
+func (t realTicker) Stop() {
+	t.ticker.Stop()
+}
@@ -0,0 +1,305 @@
+package clock
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// Virtual is a clock which only moves forward when all the goroutines started with Go wait on it,
+// or are parked, so that a simulation doesn't depend on how long its goroutines take to run.
+type Virtual struct {
+	now     time.Time
+	idle    *sync.Cond
+	blocked map[*waiter]struct{}
+	timers  timers
+	running int
+	seq     uint64
+	mu      sync.Mutex
+}
+
+// NewVirtual returns a Virtual clock starting at start.
+func NewVirtual(start time.Time) *Virtual {
+	c := &Virtual{
+		now:     start,
+		blocked: make(map[*waiter]struct{}),
+	}
+	c.idle = sync.NewCond(&c.mu)
+
+	return c
+}
+
+// waitKind orders the waiters due at the same time, so that iterations which end at the time of a
+// tick end before it.
+type waitKind int
+
+const (
+	sleeping waitKind = iota
+	timeout
+	ticking
+)
+
+type waiter struct {
+	at  time.Time
+	ctx context.Context //nolint:containedctx // the context the goroutine waits on
+	// cancel is only set for timeouts, which no goroutine waits for
+	cancel context.CancelFunc
+	woken  chan struct{}
+	kind   waitKind
+	seq    uint64
+	// index is the position of the waiter in the timers, or -1
+	index int
+}
+
+func (c *Virtual) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *Virtual) Since(t time.Time) time.Duration {
+	return c.Now().Sub(t)
+}
+
+func (c *Virtual) NewTicker(d time.Duration) Ticker {
+	return &virtualTicker{clock: c, next: c.Now().Add(d), period: d}
+}
+
+func (c *Virtual) Sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	return c.waitUntil(ctx, c.Now().Add(d), sleeping)
+}
+
+func (c *Virtual) Wait(ctx context.Context) {
+	c.waitUntil(ctx, time.Time{}, sleeping)
+}
+
+func (c *Virtual) WithCancel(ctx context.Context) (context.Context, context.CancelFunc) {
+	cancelCtx, cancel := context.WithCancel(ctx)
+
+	return cancelCtx, func() {
+		cancel()
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.wakeCancelled()
+	}
+}
+
+func (c *Virtual) WithTimeout(ctx context.Context, d time.Duration) (context.Context, context.CancelFunc) {
+	timeoutCtx, cancel := context.WithCancel(ctx)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	w := c.newWaiter(timeoutCtx, c.now.Add(d), timeout)
+	w.cancel = cancel
+	heap.Push(&c.timers, w)
+
+	return timeoutCtx, func() {
+		cancel()
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if w.index >= 0 {
+			heap.Remove(&c.timers, w.index)
+		}
+		c.wakeCancelled()
+	}
+}
+
+func (c *Virtual) Go(fn func()) {
+	c.mu.Lock()
+	c.running++
+	c.mu.Unlock()
+
+	go func() {
+		defer c.Park()
+		fn()
+	}()
+}
+
+func (c *Virtual) Park() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.running--
+	if c.running == 0 {
+		c.idle.Broadcast()
+	}
+}
+
+func (c *Virtual) Unpark(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.running += n
+}
+
+// Run moves the clock forward until done is closed, or no goroutine waits for a time. Each time all
+// the goroutines started with Go wait on the clock or are parked, it moves the clock to the time
+// the next of them waits for, and wakes it. Goroutines which sleep are woken before timeouts, and
+// timeouts before tickers, when they are due at the same time.
+func (c *Virtual) Run(done <-chan struct{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		for c.running > 0 {
+			c.idle.Wait()
+		}
+
+		select {
+		case <-done:
+			return
+		default:
+		}
+		if len(c.timers) == 0 {
+			return
+		}
+
+		w, _ := heap.Pop(&c.timers).(*waiter)
+		c.now = w.at
+		if w.cancel != nil {
+			w.cancel()
+			c.wakeCancelled()
+		} else {
+			c.wake(w)
+		}
+	}
+}
+
+// waitUntil blocks the calling goroutine until the clock reaches at, or forever if at is zero,
+// and returns false if ctx is done first.
+func (c *Virtual) waitUntil(ctx context.Context, at time.Time, kind waitKind) bool {
+	c.mu.Lock()
+	if ctx.Err() != nil {
+		c.mu.Unlock()
+		return false
+	}
+	if !at.IsZero() && !at.After(c.now) {
+		c.mu.Unlock()
+		return true
+	}
+
+	w := c.newWaiter(ctx, at, kind)
+	if !at.IsZero() {
+		heap.Push(&c.timers, w)
+	}
+	c.blocked[w] = struct{}{}
+	c.running--
+	if c.running == 0 {
+		c.idle.Broadcast()
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-w.woken:
+	case <-ctx.Done():
+		// the context was cancelled by a goroutine which isn't using the clock
+		c.mu.Lock()
+		if _, ok := c.blocked[w]; ok {
+			c.wake(w)
+		}
+		c.mu.Unlock()
+	}
+
+	return ctx.Err() == nil
+}
+
+func (c *Virtual) newWaiter(ctx context.Context, at time.Time, kind waitKind) *waiter {
+	c.seq++
+
+	return &waiter{
+		at:     at,
+		ctx:    ctx,
+		cancel: nil,
+		woken:  make(chan struct{}),
+		kind:   kind,
+		seq:    c.seq,
+		index:  -1,
+	}
+}
+
+// wake marks the goroutine of w as running, before waking it.
+func (c *Virtual) wake(w *waiter) {
+	if w.index >= 0 {
+		heap.Remove(&c.timers, w.index)
+	}
+	delete(c.blocked, w)
+	c.running++
+	close(w.woken)
+}
+
+// wakeCancelled wakes the goroutines waiting on contexts which are done, so that they are running
+// before the clock moves again.
+func (c *Virtual) wakeCancelled() {
+	for w := range c.blocked {
+		if w.ctx.Err() != nil {
+			c.wake(w)
+		}
+	}
+}
+
+type virtualTicker struct {
+	clock  *Virtual
+	next   time.Time
+	period time.Duration
+}
+
+func (t *virtualTicker) Wait(ctx context.Context) (time.Time, bool) {
+	tick := t.next
+	if !t.clock.waitUntil(ctx, tick, ticking) {
+		return time.Time{}, false
+	}
+	t.next = tick.Add(t.period)
+
+	return tick, true
+}
+
+func (t *virtualTicker) Stop() {}
+
+// timers is a heap of the waiters with a time, ordered by when they are due.
+type timers []*waiter
+
+func (t timers) Len() int {
+	return len(t)
+}
+
+func (t timers) Less(i, j int) bool {
+	if !t[i].at.Equal(t[j].at) {
+		return t[i].at.Before(t[j].at)
+	}
+	if t[i].kind != t[j].kind {
+		return t[i].kind < t[j].kind
+	}
+
+	return t[i].seq < t[j].seq
+}
+
+func (t timers) Swap(i, j int) {
+	t[i], t[j] = t[j], t[i]
+	t[i].index = i
+	t[j].index = j
+}
+
+func (t *timers) Push(x any) {
+	w, _ := x.(*waiter)
+	w.index = len(*t)
+	*t = append(*t, w)
+}
+
+func (t *timers) Pop() any {
+	old := *t
+	w := old[len(old)-1]
+	old[len(old)-1] = nil
+	w.index = -1
+	*t = old[:len(old)-1]
+
+	return w
+}
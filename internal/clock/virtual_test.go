@@ -0,0 +1,144 @@
+package clock_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/form3tech-oss/f1/v2/internal/clock"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type timeline struct {
+	clock  *clock.Virtual
+	events []string
+	mu     sync.Mutex
+}
+
+func (l *timeline) record(event string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.events = append(l.events, l.clock.Since(start).String()+" "+event)
+}
+
+func TestVirtualWakesSleepersInTheOrderOfTheirTimes(t *testing.T) {
+	t.Parallel()
+
+	c := clock.NewVirtual(start)
+	l := &timeline{clock: c}
+	for _, d := range []time.Duration{3 * time.Second, time.Second, 2 * time.Second} {
+		c.Go(func() {
+			c.Sleep(context.Background(), d)
+			l.record("woken")
+		})
+	}
+
+	c.Run(nil)
+
+	assert.Equal(t, []string{"1s woken", "2s woken", "3s woken"}, l.events)
+	assert.Equal(t, start.Add(3*time.Second), c.Now())
+}
+
+func TestVirtualWakesSleepersBeforeTickersDueAtTheSameTime(t *testing.T) {
+	t.Parallel()
+
+	c := clock.NewVirtual(start)
+	l := &timeline{clock: c}
+	c.Go(func() {
+		ticker := c.NewTicker(time.Second)
+		defer ticker.Stop()
+		for range 2 {
+			ticker.Wait(context.Background())
+			l.record("tick")
+		}
+	})
+	c.Go(func() {
+		c.Sleep(context.Background(), time.Second)
+		l.record("woken")
+	})
+
+	c.Run(nil)
+
+	assert.Equal(t, []string{"1s woken", "1s tick", "2s tick"}, l.events)
+}
+
+func TestVirtualWakesWaitersOnceTheirContextTimesOut(t *testing.T) {
+	t.Parallel()
+
+	c := clock.NewVirtual(start)
+	l := &timeline{clock: c}
+	ctx, cancel := c.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c.Go(func() {
+		c.Wait(ctx)
+		l.record("done")
+	})
+	c.Go(func() {
+		if !c.Sleep(ctx, time.Minute) {
+			l.record("interrupted")
+		}
+	})
+
+	c.Run(nil)
+
+	assert.ElementsMatch(t, []string{"5s done", "5s interrupted"}, l.events)
+}
+
+func TestVirtualMovesOnlyOnceAllGoroutinesWait(t *testing.T) {
+	t.Parallel()
+
+	c := clock.NewVirtual(start)
+	l := &timeline{clock: c}
+	ready := make(chan struct{})
+	c.Go(func() {
+		// running for a while doesn't let the clock move
+		time.Sleep(50 * time.Millisecond)
+		c.Sleep(context.Background(), 2*time.Second)
+		l.record("slow woken")
+	})
+	c.Go(func() {
+		// parked until the other goroutine unparks it
+		c.Park()
+		<-ready
+		c.Sleep(context.Background(), time.Second)
+		l.record("parked woken")
+	})
+	c.Go(func() {
+		c.Sleep(context.Background(), time.Second)
+		c.Unpark(1)
+		close(ready)
+	})
+
+	c.Run(nil)
+
+	assert.Equal(t, []string{"2s slow woken", "2s parked woken"}, l.events)
+}
+
+func TestVirtualRunStopsOnceDone(t *testing.T) {
+	t.Parallel()
+
+	c := clock.NewVirtual(start)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	c.Go(func() {
+		ticker := c.NewTicker(time.Second)
+		defer ticker.Stop()
+		for range 3 {
+			ticker.Wait(ctx)
+		}
+		close(done)
+	})
+	c.Go(func() {
+		c.Sleep(ctx, time.Hour)
+	})
+
+	c.Run(done)
+	cancel()
+
+	assert.Equal(t, start.Add(3*time.Second), c.Now())
+}
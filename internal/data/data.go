@@ -0,0 +1,226 @@
+// Package data feeds the rows of a CSV or JSONL file to the iterations of a scenario.
+package data
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+)
+
+// Mode is how rows are handed to iterations.
+type Mode string
+
+const (
+	// Sequential hands rows in order, starting again from the first row once all have been used.
+	Sequential Mode = "sequential"
+	// Random hands a random row to each iteration.
+	Random Mode = "random"
+	// Unique hands each row to a single iteration.
+	Unique Mode = "unique"
+	// PerVU partitions the rows between virtual users, which each use their rows in order. The rows
+	// are partitioned between as many virtual users as the concurrency, and other virtual users fail.
+	PerVU Mode = "per-vu"
+)
+
+// ErrExhausted is returned by Row once all rows of a Unique feeder have been used.
+var ErrExhausted = errors.New("all rows have been used")
+
+// Feeder hands the rows of a file to iterations. It is safe for concurrent use.
+type Feeder struct {
+	path              string
+	mode              Mode
+	rows              []map[string]string
+	used              []atomic.Bool
+	vuIterations      []atomic.Uint64
+	usedCount         atomic.Uint64
+	seed              uint64
+	stopWhenExhausted bool
+}
+
+// Usage is how much of the rows of a feeder were used during a run.
+type Usage struct {
+	Path string
+	Mode Mode
+	Rows uint64
+	Used uint64
+}
+
+// Load reads the rows of a .csv file, whose first line is a header, or of a .jsonl file with a
+// JSON object on each line. vus is the number of virtual users the rows are partitioned between
+// in PerVU mode, and seed is the seed of the run, from which the rows of Random mode are derived.
+func Load(path string, mode Mode, stopWhenExhausted bool, vus int, seed uint64) (*Feeder, error) {
+	switch mode {
+	case Sequential, Random, Unique, PerVU:
+	default:
+		return nil, fmt.Errorf("unknown data mode '%s', expected one of %s, %s, %s or %s",
+			mode, Sequential, Random, Unique, PerVU)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening data file: %w", err)
+	}
+	defer file.Close()
+
+	var rows []map[string]string
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".csv":
+		rows, err = readCSV(file)
+	case ".jsonl", ".ndjson":
+		rows, err = readJSONL(file)
+	default:
+		return nil, fmt.Errorf("unsupported data file extension '%s', expected .csv or .jsonl", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading data file %s: %w", path, err)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("data file %s has no rows", path)
+	}
+	if mode == PerVU && vus > len(rows) {
+		return nil, fmt.Errorf("data file %s has %d rows, fewer than the %d virtual users in %s mode",
+			path, len(rows), vus, PerVU)
+	}
+
+	return &Feeder{
+		path:              path,
+		mode:              mode,
+		rows:              rows,
+		used:              make([]atomic.Bool, len(rows)),
+		vuIterations:      make([]atomic.Uint64, max(1, vus)),
+		seed:              seed,
+		stopWhenExhausted: stopWhenExhausted,
+	}, nil
+}
+
+func readCSV(r io.Reader) ([]map[string]string, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parsing csv: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	header := records[0]
+	rows := make([]map[string]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(header))
+		for i, column := range header {
+			row[column] = record[i]
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// readJSONL reads an object on each line. String values are used as they are, other values are
+// kept as JSON.
+func readJSONL(r io.Reader) ([]map[string]string, error) {
+	var rows []map[string]string
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		content := bytes.TrimSpace(scanner.Bytes())
+		if len(content) == 0 {
+			continue
+		}
+
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(content, &object); err != nil {
+			return nil, fmt.Errorf("parsing line %d: %w", line, err)
+		}
+
+		row := make(map[string]string, len(object))
+		for key, value := range object {
+			var s string
+			if json.Unmarshal(value, &s) == nil {
+				row[key] = s
+			} else {
+				row[key] = string(value)
+			}
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scanning lines: %w", err)
+	}
+
+	return rows, nil
+}
+
+// Row returns the row for iteration, starting at 1, run by the virtual user vuid.
+func (f *Feeder) Row(vuid int, iteration uint64) (map[string]string, error) {
+	if iteration == 0 {
+		return nil, errors.New("data is only available in iterations")
+	}
+
+	n := uint64(len(f.rows))
+
+	var index uint64
+	switch f.mode {
+	case Sequential:
+		index = (iteration - 1) % n
+	case Random:
+		// derived from the iteration, so that a run repeated with the same seed picks the same rows
+		//nolint:gosec // G404: Use of weak random number generator - doesn't need to be secure
+		index = rand.New(rand.NewPCG(f.seed, iteration)).Uint64N(n)
+	case Unique:
+		if iteration > n {
+			return nil, fmt.Errorf("%w: %d rows of %s", ErrExhausted, n, f.path)
+		}
+		index = iteration - 1
+	case PerVU:
+		vus := uint64(len(f.vuIterations))
+		if vuid < 0 || uint64(vuid) >= vus {
+			return nil, fmt.Errorf("the rows of %s are partitioned between %d virtual users, "+
+				"so virtual user %d has none: increase --concurrency to the number of virtual users", f.path, vus, vuid)
+		}
+		vu := uint64(vuid)
+		// rows vu, vu+vus, vu+2*vus... belong to the virtual user
+		size := (n - vu + vus - 1) / vus
+		index = vu + (f.vuIterations[vu].Add(1)-1)%size*vus
+	}
+
+	if !f.used[index].Swap(true) {
+		f.usedCount.Add(1)
+	}
+
+	return f.rows[index], nil
+}
+
+// Limit returns the number of iterations the feeder can be used by, or 0 if it isn't limited.
+func (f *Feeder) Limit() uint64 {
+	if f.mode == Unique && f.stopWhenExhausted {
+		return uint64(len(f.rows))
+	}
+
+	return 0
+}
+
+// Path returns the path of the data file.
+func (f *Feeder) Path() string {
+	return f.path
+}
+
+// Usage returns how many of the rows have been used.
+func (f *Feeder) Usage() Usage {
+	return Usage{
+		Path: f.path,
+		Mode: f.mode,
+		Rows: uint64(len(f.rows)),
+		Used: f.usedCount.Load(),
+	}
+}
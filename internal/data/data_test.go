@@ -0,0 +1,162 @@
+package data_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/data"
+)
+
+const (
+	csvFile   = "../testdata/accounts.csv"
+	jsonlFile = "../testdata/accounts.jsonl"
+)
+
+func accountIDs(t *testing.T, feeder *data.Feeder, vuid int, iterations ...uint64) []string {
+	t.Helper()
+
+	ids := make([]string, len(iterations))
+	for i, iteration := range iterations {
+		row, err := feeder.Row(vuid, iteration)
+		require.NoError(t, err)
+		ids[i] = row["account_id"]
+	}
+
+	return ids
+}
+
+func TestLoad_ReadsCSVWithAHeader(t *testing.T) {
+	t.Parallel()
+
+	feeder, err := data.Load(csvFile, data.Sequential, false, 1, 0)
+	require.NoError(t, err)
+
+	row, err := feeder.Row(0, 2)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"account_id": "acc-2", "name": "bob"}, row)
+}
+
+func TestLoad_ReadsJSONLines(t *testing.T) {
+	t.Parallel()
+
+	feeder, err := data.Load(jsonlFile, data.Sequential, false, 1, 0)
+	require.NoError(t, err)
+
+	row, err := feeder.Row(0, 2)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"account_id": "acc-2", "balance": "20", "tags": `["a"]`}, row)
+	assert.Equal(t, uint64(3), feeder.Usage().Rows)
+}
+
+func TestLoad_FailsForInvalidFiles(t *testing.T) {
+	t.Parallel()
+
+	_, err := data.Load("../testdata/config-file.yaml", data.Sequential, false, 1, 0)
+	require.EqualError(t, err, "unsupported data file extension '.yaml', expected .csv or .jsonl")
+
+	_, err = data.Load(csvFile, "shuffled", false, 1, 0)
+	require.EqualError(t, err, "unknown data mode 'shuffled', expected one of sequential, random, unique or per-vu")
+
+	_, err = data.Load(csvFile, data.PerVU, false, 5, 0)
+	require.EqualError(t, err,
+		"data file ../testdata/accounts.csv has 4 rows, fewer than the 5 virtual users in per-vu mode")
+
+	_, err = data.Load("missing.csv", data.Sequential, false, 1, 0)
+	require.ErrorContains(t, err, "opening data file")
+}
+
+func TestRow_SequentialStartsAgainOnceAllRowsAreUsed(t *testing.T) {
+	t.Parallel()
+
+	feeder, err := data.Load(csvFile, data.Sequential, false, 1, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"acc-1", "acc-2", "acc-3", "acc-4", "acc-1"}, accountIDs(t, feeder, 0, 1, 2, 3, 4, 5))
+	assert.Equal(t, data.Usage{Path: csvFile, Mode: data.Sequential, Rows: 4, Used: 4}, feeder.Usage())
+	assert.Zero(t, feeder.Limit())
+}
+
+func TestRow_UniqueFailsOnceAllRowsAreUsed(t *testing.T) {
+	t.Parallel()
+
+	feeder, err := data.Load(csvFile, data.Unique, false, 1, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"acc-1", "acc-4"}, accountIDs(t, feeder, 0, 1, 4))
+
+	_, err = feeder.Row(0, 5)
+	require.ErrorIs(t, err, data.ErrExhausted)
+	assert.Equal(t, uint64(2), feeder.Usage().Used)
+}
+
+func TestLimit_IsTheNumberOfRowsWhenUniqueStopsWhenExhausted(t *testing.T) {
+	t.Parallel()
+
+	feeder, err := data.Load(csvFile, data.Unique, true, 1, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(4), feeder.Limit())
+}
+
+func TestRow_PerVUPartitionsRowsBetweenVirtualUsers(t *testing.T) {
+	t.Parallel()
+
+	feeder, err := data.Load(csvFile, data.PerVU, false, 3, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"acc-1", "acc-4", "acc-1"}, accountIDs(t, feeder, 0, 1, 2, 3))
+	assert.Equal(t, []string{"acc-2", "acc-2"}, accountIDs(t, feeder, 1, 4, 5))
+	assert.Equal(t, []string{"acc-3"}, accountIDs(t, feeder, 2, 6))
+}
+
+func TestRow_RandomReturnsRowsOfTheFile(t *testing.T) {
+	t.Parallel()
+
+	feeder, err := data.Load(csvFile, data.Random, false, 1, 0)
+	require.NoError(t, err)
+
+	for _, id := range accountIDs(t, feeder, 0, 1, 2, 3, 4, 5, 6) {
+		assert.Contains(t, []string{"acc-1", "acc-2", "acc-3", "acc-4"}, id)
+	}
+}
+
+func TestRow_RandomIsDerivedFromTheSeedAndIteration(t *testing.T) {
+	t.Parallel()
+
+	rows := func(seed uint64, iterations ...uint64) []string {
+		feeder, err := data.Load(csvFile, data.Random, false, 1, seed)
+		require.NoError(t, err)
+		return accountIDs(t, feeder, 0, iterations...)
+	}
+
+	// the rows don't depend on the order in which iterations ask for them
+	first := rows(42, 1, 2, 3, 4, 5, 6, 7, 8)
+	assert.Equal(t, first, rows(42, 1, 2, 3, 4, 5, 6, 7, 8))
+	assert.Equal(t, []string{first[7], first[0]}, rows(42, 8, 1))
+	assert.NotEqual(t, first, rows(43, 1, 2, 3, 4, 5, 6, 7, 8))
+}
+
+func TestRow_FailsOutsideOfIterations(t *testing.T) {
+	t.Parallel()
+
+	feeder, err := data.Load(csvFile, data.Sequential, false, 1, 0)
+	require.NoError(t, err)
+
+	_, err = feeder.Row(-1, 0)
+	require.EqualError(t, err, "data is only available in iterations")
+}
+
+func TestRow_PerVUFailsForVirtualUsersBeyondTheConcurrency(t *testing.T) {
+	t.Parallel()
+
+	feeder, err := data.Load(csvFile, data.PerVU, false, 2, 0)
+	require.NoError(t, err)
+
+	_, err = feeder.Row(2, 1)
+	require.ErrorContains(t, err, "partitioned between 2 virtual users, so virtual user 2 has none")
+
+	_, err = feeder.Row(1, 1)
+	require.NoError(t, err)
+}
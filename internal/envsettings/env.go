@@ -10,6 +10,7 @@ const (
 	EnvPrometheusLabelID     = "PROMETHEUS_LABEL_ID"
 	EnvPrometheusNamespace   = "PROMETHEUS_NAMESPACE"
 	EnvPrometheusPushGateway = "PROMETHEUS_PUSH_GATEWAY"
+	EnvPrometheusErrorLabel  = "PROMETHEUS_ERROR_LABEL"
 
 	EnvLogFilePath = "LOG_FILE_PATH"
 	EnvLogFormat   = "F1_LOG_FORMAT"
@@ -23,6 +24,8 @@ type Prometheus struct {
 	LabelID     string
 	Namespace   string
 	PushGateway string
+	// ErrorLabel adds the failure reason of failed iterations as a metric label
+	ErrorLabel bool
 }
 
 type Fluentd struct {
@@ -83,6 +86,7 @@ func Get() Settings {
 			LabelID:     os.Getenv(EnvPrometheusLabelID),
 			Namespace:   os.Getenv(EnvPrometheusNamespace),
 			PushGateway: os.Getenv(EnvPrometheusPushGateway),
+			ErrorLabel:  strings.EqualFold(os.Getenv(EnvPrometheusErrorLabel), "true"),
 		},
 	}
 }
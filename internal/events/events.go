@@ -0,0 +1,73 @@
+// Package events writes and reads the per-iteration event stream written with --events-file.
+//
+// The events file is newline delimited JSON, with one Event per line.
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+// maxLineSize is the longest events file line that can be read
+const maxLineSize = 1024 * 1024
+
+// Event is a single iteration, or a dropped iteration which was never started.
+type Event struct {
+	Result  string  `json:"result"`
+	Failure string  `json:"failure,omitempty"`
+	Stages  []Stage `json:"stages,omitempty"`
+	// Tags are the tags set with T.Tag
+	Tags map[string]string `json:"tags,omitempty"`
+	// Iteration is 0 for dropped iterations
+	Iteration uint64 `json:"iteration,omitempty"`
+	// VUID is -1 for dropped iterations
+	VUID int `json:"vuid"`
+	// ScheduledAt is when the iteration was triggered, in unix nanoseconds
+	ScheduledAt int64 `json:"scheduled_at"`
+	// StartedAt is when the iteration started, in unix nanoseconds, and 0 for dropped iterations
+	StartedAt int64 `json:"started_at,omitempty"`
+	// Duration is the iteration duration in nanoseconds
+	Duration int64 `json:"duration_ns"`
+}
+
+// Stage is the duration of a stage of an iteration, recorded with T.Time.
+type Stage struct {
+	Name     string `json:"name"`
+	Duration int64  `json:"duration_ns"`
+}
+
+func (e Event) Scheduled() time.Time {
+	return time.Unix(0, e.ScheduledAt)
+}
+
+// Read calls fn for each event read from r.
+func Read(r io.Reader, fn func(Event) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var event Event
+		if err := json.Unmarshal(scanner.Bytes(), &event); err != nil {
+			return fmt.Errorf("parsing event on line %d: %w", line, err)
+		}
+
+		if err := fn(event); err != nil {
+			return err
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("reading events: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	// bufferSize is the number of events which can be queued before events are dropped
+	bufferSize    = 10_000
+	batchSize     = 1_000
+	flushInterval = time.Second
+)
+
+// Writer writes events asynchronously and in batches, so that iterations are not slowed
+// down by writing to disk. Events are dropped if the writer can't keep up.
+type Writer struct {
+	out     io.WriteCloser
+	events  chan Event
+	done    chan struct{}
+	err     error
+	dropped atomic.Uint64
+	mu      sync.RWMutex
+	closed  bool
+}
+
+// Create creates the events file at path, truncating it if it exists.
+func Create(path string) (*Writer, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("creating events file: %w", err)
+	}
+
+	return NewWriter(file), nil
+}
+
+func NewWriter(out io.WriteCloser) *Writer {
+	w := &Writer{
+		out:    out,
+		events: make(chan Event, bufferSize),
+		done:   make(chan struct{}),
+	}
+
+	go w.write()
+
+	return w
+}
+
+// Record queues an event to be written. Events recorded after Close are ignored.
+func (w *Writer) Record(event Event) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return
+	}
+
+	select {
+	case w.events <- event:
+	default:
+		w.dropped.Add(1)
+	}
+}
+
+// Dropped returns the number of events which were not written because the queue was full.
+func (w *Writer) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// Close writes the queued events and closes the events file.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.events)
+	w.mu.Unlock()
+
+	<-w.done
+
+	if err := w.out.Close(); err != nil {
+		w.err = errors.Join(w.err, fmt.Errorf("closing events file: %w", err))
+	}
+
+	return w.err
+}
+
+func (w *Writer) write() {
+	defer close(w.done)
+
+	buf := bufio.NewWriter(w.out)
+	encoder := json.NewEncoder(buf)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	pending := 0
+	flush := func() {
+		if pending == 0 {
+			return
+		}
+		pending = 0
+		if err := buf.Flush(); err != nil && w.err == nil {
+			w.err = fmt.Errorf("writing events: %w", err)
+		}
+	}
+
+	for {
+		select {
+		case event, ok := <-w.events:
+			if !ok {
+				flush()
+				return
+			}
+
+			if err := encoder.Encode(event); err != nil && w.err == nil {
+				w.err = fmt.Errorf("encoding event: %w", err)
+			}
+			pending++
+			if pending >= batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package events_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/events"
+)
+
+func TestWriter_WrittenEventsCanBeRead(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	writer, err := events.Create(path)
+	require.NoError(t, err)
+
+	written := []events.Event{
+		{
+			Result:      "success",
+			Stages:      []events.Stage{{Name: "call", Duration: 10}},
+			Iteration:   1,
+			VUID:        0,
+			ScheduledAt: 1000,
+			StartedAt:   1001,
+			Duration:    20,
+		},
+		{Result: "fail", Failure: "boom", Iteration: 2, VUID: 1, ScheduledAt: 1000, StartedAt: 1002, Duration: 30},
+		{Result: "dropped", VUID: -1, ScheduledAt: 2000},
+	}
+	for _, event := range written {
+		writer.Record(event)
+	}
+	require.NoError(t, writer.Close())
+	require.Zero(t, writer.Dropped())
+
+	// events recorded after close are ignored
+	writer.Record(events.Event{Result: "success"})
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var read []events.Event
+	require.NoError(t, events.Read(file, func(event events.Event) error {
+		read = append(read, event)
+		return nil
+	}))
+
+	require.Equal(t, written, read)
+}
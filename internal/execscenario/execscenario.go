@@ -0,0 +1,205 @@
+// Package execscenario runs a command in each iteration, for scenarios which are written as scripts
+// rather than in Go.
+//
+// The command is a text/template, such as "./probe.sh {{.Iteration}}", run with sh -c. The
+// iteration, virtual user and parameters are also set as the environment variables F1_ITERATION,
+// F1_VUID and F1_PARAM_<NAME>. The iteration fails if the command exits with a non-zero code, and
+// its stderr is written to the scenario log. The command is killed when the run is interrupted. A
+// line of stdout can report the durations of stages in milliseconds, as T.Time does:
+//
+//	{"stages_ms": {"login": 120.5, "payment": 310}}
+package execscenario
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+// Name is the name of the scenario running the command.
+const Name = "exec"
+
+// maxOutputSize caps the output of a command kept in memory.
+const maxOutputSize = 64 * 1024
+
+// waitDelay is how long an interrupted command, and the processes it started, can hold its output
+// open after being killed.
+const waitDelay = time.Second
+
+// stagesLine is the line of stdout which reports the durations of stages.
+type stagesLine struct {
+	StagesMs map[string]float64 `json:"stages_ms"`
+}
+
+// commandData is the data of the command template.
+type commandData struct {
+	t *testing.T
+}
+
+// Iteration returns the iteration number.
+func (d commandData) Iteration() string {
+	return d.t.Iteration
+}
+
+// VUID returns the virtual user running the iteration.
+func (d commandData) VUID() int {
+	return d.t.VUID
+}
+
+// Param returns the value of the scenario parameter name.
+func (d commandData) Param(name string) string {
+	return d.t.Param(name)
+}
+
+// New returns a scenario which runs command in each iteration. params are the names of the
+// parameters the command can use, which are set with --param.
+func New(command string, params []string) (*scenarios.Scenario, error) {
+	tmpl, err := template.New(Name).Parse(command)
+	if err != nil {
+		return nil, fmt.Errorf("parsing command: %w", err)
+	}
+
+	parameters := make([]scenarios.ScenarioParameter, len(params))
+	for i, name := range params {
+		parameters[i] = scenarios.ScenarioParameter{Name: name, Description: "set with --param", Default: ""}
+	}
+
+	return &scenarios.Scenario{
+		Name:        Name,
+		Description: "runs " + command,
+		Parameters:  parameters,
+		ScenarioFn: func(*testing.T) testing.RunFn {
+			return func(t *testing.T) {
+				run(t, tmpl, params)
+			}
+		},
+	}, nil
+}
+
+func run(t *testing.T, tmpl *template.Template, params []string) {
+	var command strings.Builder
+	if err := tmpl.Execute(&command, commandData{t: t}); err != nil {
+		t.Fatalf("rendering command: %v", err)
+	}
+
+	stdout := &cappedBuffer{}
+	stderr := &cappedBuffer{}
+
+	// the command is killed once the run is interrupted
+	//nolint:gosec // G204: running the command given with --exec is the purpose of the scenario
+	cmd := exec.CommandContext(t.Context(), "/bin/sh", "-c", command.String())
+	cmd.WaitDelay = waitDelay
+	cmd.Env = append(os.Environ(), environment(t, params)...)
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+
+	lastLine := logStderr(t, stderr)
+	recordStages(t, stdout)
+
+	var exitErr *exec.ExitError
+	switch {
+	case err != nil && t.Context().Err() != nil:
+		t.Errorf("command interrupted: %v", t.Context().Err())
+	case errors.As(err, &exitErr) && lastLine != "":
+		t.Errorf("command exited with code %d: %s", exitErr.ExitCode(), lastLine)
+	case errors.As(err, &exitErr):
+		t.Errorf("command exited with code %d", exitErr.ExitCode())
+	case err != nil:
+		t.Errorf("running command: %v", err)
+	}
+}
+
+func environment(t *testing.T, params []string) []string {
+	env := []string{
+		"F1_ITERATION=" + t.Iteration,
+		"F1_VUID=" + strconv.Itoa(t.VUID),
+	}
+	for _, name := range params {
+		env = append(env, "F1_PARAM_"+envName(name)+"="+t.Param(name))
+	}
+
+	return env
+}
+
+// envName upper cases name, and replaces the characters which aren't allowed in environment
+// variable names with underscores.
+func envName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+// logStderr writes each line of stderr to the scenario log, and returns the last one.
+func logStderr(t *testing.T, stderr *cappedBuffer) string {
+	var last string
+	scanner := bufio.NewScanner(bytes.NewReader(stderr.Bytes()))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		t.Logf("stderr: %s", line)
+		last = line
+	}
+	if stderr.truncated {
+		t.Logf("stderr: truncated after %d bytes", maxOutputSize)
+	}
+
+	return last
+}
+
+// recordStages records the durations of the stages reported on stdout.
+func recordStages(t *testing.T, stdout *cappedBuffer) {
+	scanner := bufio.NewScanner(bytes.NewReader(stdout.Bytes()))
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if !bytes.HasPrefix(line, []byte("{")) {
+			continue
+		}
+
+		var stages stagesLine
+		if err := json.Unmarshal(line, &stages); err != nil || stages.StagesMs == nil {
+			continue
+		}
+		for name, ms := range stages.StagesMs {
+			t.RecordStage(name, time.Duration(ms*float64(time.Millisecond)))
+		}
+	}
+}
+
+// cappedBuffer keeps the first maxOutputSize bytes written to it.
+type cappedBuffer struct {
+	bytes.Buffer
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if remaining := maxOutputSize - b.Len(); len(p) > remaining {
+		b.truncated = true
+		b.Buffer.Write(p[:max(0, remaining)])
+		return len(p), nil
+	}
+
+	b.Buffer.Write(p)
+	return len(p), nil
+}
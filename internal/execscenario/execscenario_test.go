@@ -0,0 +1,79 @@
+package execscenario_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/execscenario"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/f1test"
+)
+
+func TestExec_PassesTheIterationAsTemplateAndEnvironment(t *testing.T) {
+	t.Parallel()
+
+	out := filepath.Join(t.TempDir(), "out")
+	scenario, err := execscenario.New(
+		`echo "{{.Iteration}} {{.VUID}} {{.Param "region"}} $F1_ITERATION $F1_PARAM_REGION" >> `+out,
+		[]string{"region"},
+	)
+	require.NoError(t, err)
+
+	result := f1test.RunScenario(t, scenario.ScenarioFn, f1test.Iterations(2), f1test.Param("region", "eu"))
+
+	require.Len(t, result.Iterations, 2)
+	written, err := os.ReadFile(out)
+	require.NoError(t, err)
+	assert.Equal(t, "1 0 eu 1 eu\n2 0 eu 2 eu\n", string(written))
+}
+
+func TestExec_FailsOnNonZeroExitCodes(t *testing.T) {
+	t.Parallel()
+
+	scenario, err := execscenario.New(`echo "connecting"; echo "connection refused" >&2; exit 3`, nil)
+	require.NoError(t, err)
+
+	result := f1test.RunScenario(t, scenario.ScenarioFn, f1test.ExpectFailures())
+
+	require.Len(t, result.Failed(), 1)
+	assert.Equal(t, "command exited with code 3: connection refused", result.Iterations[0].FailureReason)
+	assert.Contains(t, result.Iterations[0].Logs, "stderr: connection refused")
+	assert.NotContains(t, result.Iterations[0].Logs, "connecting")
+}
+
+func TestExec_RecordsStagesReportedOnStdout(t *testing.T) {
+	t.Parallel()
+
+	scenario, err := execscenario.New(`echo "logging in"; echo '{"stages_ms": {"login": 120.5, "payment": 3}}'`, nil)
+	require.NoError(t, err)
+
+	result := f1test.RunScenario(t, scenario.ScenarioFn)
+
+	assert.Equal(t, []time.Duration{120500 * time.Microsecond}, result.StageDurations("login"))
+	assert.Equal(t, []time.Duration{3 * time.Millisecond}, result.StageDurations("payment"))
+}
+
+func TestExec_KillsInterruptedCommands(t *testing.T) {
+	t.Parallel()
+
+	scenario, err := execscenario.New("sleep 60", nil)
+	require.NoError(t, err)
+
+	start := time.Now()
+	result := f1test.RunScenario(t, scenario.ScenarioFn, f1test.Timeout(100*time.Millisecond), f1test.ExpectFailures())
+
+	assert.Less(t, time.Since(start), 10*time.Second)
+	require.Len(t, result.Failed(), 1)
+	assert.Equal(t, "command interrupted: context canceled", result.Iterations[0].FailureReason)
+}
+
+func TestExec_RejectsInvalidTemplates(t *testing.T) {
+	t.Parallel()
+
+	_, err := execscenario.New("./probe.sh {{.Iteration", nil)
+	require.ErrorContains(t, err, "parsing command")
+}
@@ -0,0 +1,44 @@
+package harscenario
+
+import "time"
+
+// The fields of a HAR file which are replayed. The format is described in
+// https://w3c.github.io/web-performance/specs/HAR/Overview.html
+
+type harFile struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Entries []harEntry `json:"entries"`
+}
+
+type harEntry struct {
+	//nolint:tagliatelle // the field names of the HAR format
+	StartedDateTime time.Time   `json:"startedDateTime"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	// Time is the duration of the request in milliseconds.
+	Time float64 `json:"time"`
+}
+
+type harRequest struct {
+	Method  string      `json:"method"`
+	URL     string      `json:"url"`
+	Headers []harHeader `json:"headers"`
+	//nolint:tagliatelle // the field names of the HAR format
+	PostData harPostData `json:"postData"`
+}
+
+type harHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	Text string `json:"text"`
+}
+
+type harResponse struct {
+	Status int `json:"status"`
+}
@@ -0,0 +1,255 @@
+// Package harscenario replays the requests of a HAR file, such as a browser session exported from
+// its developer tools, as a scenario.
+//
+// Each iteration sends the requests in the order they were recorded, one at a time, pausing for
+// the time between the end of a request and the start of the next one. Each request is timed as a
+// stage named after its method, host and path, and fails the iteration if its status isn't the
+// recorded one. Redirects aren't followed, as they are recorded as requests of their own.
+package harscenario
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+const requestTimeout = 30 * time.Second
+
+// Options configure the replay of a HAR file.
+type Options struct {
+	// Hosts maps recorded hosts to the hosts requests are sent to, such as staging.example.com, or
+	// to a scheme and host, such as http://localhost:8080.
+	Hosts map[string]string
+	// Include replays only the requests matching one of the filters, if any are set. A filter
+	// starting with / matches the paths it prefixes, and others match a domain and its subdomains.
+	Include []string
+	// Exclude doesn't replay the requests matching one of the filters.
+	Exclude []string
+	// ThinkTimeScale scales the recorded pauses between requests, where 0 replays them without
+	// pauses.
+	ThinkTimeScale float64
+}
+
+// ParseHosts parses pairs of hosts, such as www.example.com=staging.example.com, into Options.Hosts.
+func ParseHosts(pairs []string) (map[string]string, error) {
+	hosts := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		from, to, found := strings.Cut(pair, "=")
+		if !found || from == "" || to == "" {
+			return nil, fmt.Errorf("invalid host '%s', expected recorded=replayed", pair)
+		}
+		hosts[from] = to
+	}
+
+	return hosts, nil
+}
+
+// entry is a request of the HAR file to replay.
+type entry struct {
+	stage   string
+	method  string
+	url     *url.URL
+	headers [][2]string
+	body    string
+	status  int
+	// pause is the time to wait before sending the request.
+	pause time.Duration
+}
+
+// Load returns a scenario, named after the file, which replays the HAR file at path.
+func Load(path string, options Options) (*scenarios.Scenario, error) {
+	if options.ThinkTimeScale < 0 {
+		return nil, fmt.Errorf("think time scale %v can't be negative", options.ThinkTimeScale)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading har file: %w", err)
+	}
+
+	var file harFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("parsing har file: %w", err)
+	}
+
+	entries, err := replayedEntries(file.Log.Entries, options)
+	if err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{
+		Timeout: requestTimeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	return &scenarios.Scenario{
+		Name:        strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)),
+		Description: fmt.Sprintf("replays the %d requests of %s", len(entries), path),
+		ScenarioFn: func(*testing.T) testing.RunFn {
+			return func(t *testing.T) {
+				for _, e := range entries {
+					if e.pause > 0 && !pause(t, e.pause) {
+						return
+					}
+					replay(t, client, e)
+				}
+			}
+		},
+	}, nil
+}
+
+func replayedEntries(harEntries []harEntry, options Options) ([]entry, error) {
+	var entries []entry
+	var previousEnd time.Time
+	for i, h := range harEntries {
+		recorded, err := url.Parse(h.Request.URL)
+		if err != nil {
+			return nil, fmt.Errorf("entry %d: parsing url: %w", i+1, err)
+		}
+		if !included(recorded, options) {
+			continue
+		}
+
+		e := entry{
+			stage:  h.Request.Method + " " + recorded.Host + recorded.Path,
+			method: h.Request.Method,
+			url:    rewrite(recorded, options.Hosts),
+			body:   h.Request.PostData.Text,
+			status: h.Response.Status,
+		}
+		for _, header := range h.Request.Headers {
+			if replayedHeader(header.Name) {
+				e.headers = append(e.headers, [2]string{header.Name, header.Value})
+			}
+		}
+		if !previousEnd.IsZero() && h.StartedDateTime.After(previousEnd) {
+			e.pause = time.Duration(float64(h.StartedDateTime.Sub(previousEnd)) * options.ThinkTimeScale)
+		}
+		previousEnd = h.StartedDateTime.Add(time.Duration(h.Time * float64(time.Millisecond)))
+
+		entries = append(entries, e)
+	}
+
+	if len(entries) == 0 {
+		return nil, errors.New("har file has no requests to replay")
+	}
+
+	return entries, nil
+}
+
+func included(u *url.URL, options Options) bool {
+	for _, filter := range options.Exclude {
+		if matches(u, filter) {
+			return false
+		}
+	}
+	if len(options.Include) == 0 {
+		return true
+	}
+	for _, filter := range options.Include {
+		if matches(u, filter) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func matches(u *url.URL, filter string) bool {
+	if strings.HasPrefix(filter, "/") {
+		return strings.HasPrefix(u.Path, filter)
+	}
+
+	host := u.Hostname()
+	return host == filter || strings.HasSuffix(host, "."+filter)
+}
+
+// rewrite returns u, sent to the host hosts maps its host to, if any.
+func rewrite(u *url.URL, hosts map[string]string) *url.URL {
+	to, ok := hosts[u.Host]
+	if !ok {
+		to, ok = hosts[u.Hostname()]
+	}
+	if !ok {
+		return u
+	}
+
+	rewritten := *u
+	if scheme, host, found := strings.Cut(to, "://"); found {
+		rewritten.Scheme = scheme
+		rewritten.Host = host
+	} else {
+		rewritten.Host = to
+	}
+
+	return &rewritten
+}
+
+// replayedHeader returns whether a recorded header is sent, rather than set by the client.
+func replayedHeader(name string) bool {
+	if strings.HasPrefix(name, ":") {
+		return false
+	}
+
+	switch http.CanonicalHeaderKey(name) {
+	case "Host", "Content-Length", "Connection":
+		return false
+	default:
+		return true
+	}
+}
+
+// pause waits for d, and returns false if the run is interrupted first.
+func pause(t *testing.T, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-t.Context().Done():
+		return false
+	}
+}
+
+func replay(t *testing.T, client *http.Client, e entry) {
+	req, err := http.NewRequestWithContext(t.Context(), e.method, e.url.String(), strings.NewReader(e.body))
+	if err != nil {
+		t.Errorf("%s: creating request: %v", e.stage, err)
+		return
+	}
+	for _, header := range e.headers {
+		req.Header.Add(header[0], header[1])
+	}
+
+	var res *http.Response
+	t.Time(e.stage, func() {
+		res, err = client.Do(req)
+		if err != nil {
+			return
+		}
+		_, err = io.Copy(io.Discard, res.Body)
+		_ = res.Body.Close()
+	})
+	if err != nil {
+		t.Errorf("%s: %v", e.stage, err)
+		return
+	}
+
+	// requests which weren't completed when recorded have no status
+	if e.status != 0 && res.StatusCode != e.status {
+		t.Errorf("%s: recorded status %d, got %s", e.stage, e.status, res.Status)
+	}
+}
@@ -0,0 +1,199 @@
+package harscenario_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/harscenario"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/f1test"
+)
+
+// session is a recorded session, where each request starts 200ms after the previous one ended.
+const session = `{"log": {"entries": [
+  {
+    "startedDateTime": "2026-01-02T10:00:00.000Z", "time": 100,
+    "request": {"method": "GET", "url": "https://www.example.com/app?version=2",
+      "headers": [{"name": ":authority", "value": "www.example.com"}, {"name": "Accept", "value": "text/html"}]},
+    "response": {"status": 200}
+  },
+  {
+    "startedDateTime": "2026-01-02T10:00:00.300Z", "time": 50,
+    "request": {"method": "GET", "url": "https://cdn.example.net/app.js", "headers": []},
+    "response": {"status": 200}
+  },
+  {
+    "startedDateTime": "2026-01-02T10:00:00.550Z", "time": 50,
+    "request": {"method": "POST", "url": "https://api.www.example.com/analytics", "headers": []},
+    "response": {"status": 204}
+  },
+  {
+    "startedDateTime": "2026-01-02T10:00:00.800Z", "time": 100,
+    "request": {"method": "POST", "url": "https://api.www.example.com/orders",
+      "headers": [{"name": "Content-Type", "value": "application/json"}],
+      "postData": {"mimeType": "application/json", "text": "{\"item\": 1}"}},
+    "response": {"status": 201}
+  }
+]}}`
+
+func TestHAR_ReplaysTheIncludedRequestsInOrder(t *testing.T) {
+	t.Parallel()
+
+	server := newRecordingServer(t)
+	scenario, err := harscenario.Load(writeFile(t, "checkout.har", session), harscenario.Options{
+		Hosts:   map[string]string{"www.example.com": server.URL, "api.www.example.com": server.URL},
+		Include: []string{"www.example.com"},
+		Exclude: []string{"/analytics"},
+	})
+	require.NoError(t, err)
+
+	result := f1test.RunScenario(t, scenario.ScenarioFn, f1test.Iterations(2))
+
+	assert.Equal(t, "checkout", scenario.Name)
+	assert.Equal(t, []string{
+		"GET /app?version=2 text/html", `POST /orders application/json {"item": 1}`,
+		"GET /app?version=2 text/html", `POST /orders application/json {"item": 1}`,
+	}, server.requests())
+	assert.Len(t, result.StageDurations("GET www.example.com/app"), 2)
+	assert.Len(t, result.StageDurations("POST api.www.example.com/orders"), 2)
+}
+
+func TestHAR_ScalesThePausesBetweenRequests(t *testing.T) {
+	t.Parallel()
+
+	server := newRecordingServer(t)
+	options := harscenario.Options{
+		Hosts:          map[string]string{"www.example.com": server.URL, "api.www.example.com": server.URL},
+		Exclude:        []string{"cdn.example.net", "/analytics"},
+		ThinkTimeScale: 0.5,
+	}
+	scenario, err := harscenario.Load(writeFile(t, "checkout.har", session), options)
+	require.NoError(t, err)
+
+	result := f1test.RunScenario(t, scenario.ScenarioFn)
+
+	// 700ms between the end of /app and the start of /orders
+	assert.GreaterOrEqual(t, result.Iterations[0].Duration, 350*time.Millisecond)
+}
+
+func TestHAR_StopsPausingOnceInterrupted(t *testing.T) {
+	t.Parallel()
+
+	server := newRecordingServer(t)
+	options := harscenario.Options{
+		Hosts:          map[string]string{"www.example.com": server.URL, "api.www.example.com": server.URL},
+		Exclude:        []string{"cdn.example.net", "/analytics"},
+		ThinkTimeScale: 100,
+	}
+	scenario, err := harscenario.Load(writeFile(t, "checkout.har", session), options)
+	require.NoError(t, err)
+
+	result := f1test.RunScenario(t, scenario.ScenarioFn, f1test.Timeout(200*time.Millisecond))
+
+	// the 70s pause before /orders is cut short
+	assert.Less(t, result.Iterations[0].Duration, 10*time.Second)
+	assert.Equal(t, []string{"GET /app?version=2 text/html"}, server.requests())
+}
+
+func TestHAR_FailsOnStatusMismatches(t *testing.T) {
+	t.Parallel()
+
+	server := newRecordingServer(t)
+	server.status = http.StatusServiceUnavailable
+	scenario, err := harscenario.Load(writeFile(t, "checkout.har", session), harscenario.Options{
+		Hosts:   map[string]string{"www.example.com": server.URL, "api.www.example.com": server.URL},
+		Exclude: []string{"cdn.example.net"},
+	})
+	require.NoError(t, err)
+
+	result := f1test.RunScenario(t, scenario.ScenarioFn, f1test.ExpectFailures())
+
+	require.Len(t, result.Failed(), 1)
+	assert.Equal(t, "GET www.example.com/app: recorded status 200, got 503 Service Unavailable",
+		result.Iterations[0].FailureReason)
+	// the replay continued after the mismatch
+	assert.Len(t, server.requests(), 3)
+}
+
+func TestHAR_RejectsInvalidOptions(t *testing.T) {
+	t.Parallel()
+
+	path := writeFile(t, "checkout.har", session)
+
+	_, err := harscenario.Load(path, harscenario.Options{Include: []string{"other.example.com"}})
+	require.EqualError(t, err, "har file has no requests to replay")
+
+	_, err = harscenario.Load(path, harscenario.Options{ThinkTimeScale: -1})
+	require.EqualError(t, err, "think time scale -1 can't be negative")
+
+	_, err = harscenario.Load(writeFile(t, "invalid.har", "{"), harscenario.Options{})
+	require.ErrorContains(t, err, "parsing har file")
+
+	_, err = harscenario.ParseHosts([]string{"www.example.com"})
+	require.EqualError(t, err, "invalid host 'www.example.com', expected recorded=replayed")
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+type recordingServer struct {
+	*httptest.Server
+	mu     sync.Mutex
+	sent   []string
+	status int
+}
+
+func (s *recordingServer) requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.sent)
+}
+
+// newRecordingServer returns a server which records the requests it receives, and responds with
+// the recorded status unless status is set.
+func newRecordingServer(t *testing.T) *recordingServer {
+	t.Helper()
+
+	s := &recordingServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		request := r.Method + " " + r.URL.RequestURI() + " "
+		if r.Method == http.MethodGet {
+			request += r.Header.Get("Accept")
+		} else {
+			request += r.Header.Get("Content-Type") + " " + string(body)
+		}
+
+		s.mu.Lock()
+		s.sent = append(s.sent, request)
+		s.mu.Unlock()
+
+		switch {
+		case s.status != 0:
+			w.WriteHeader(s.status)
+		case r.Method == http.MethodPost && r.URL.Path == "/orders":
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodPost:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
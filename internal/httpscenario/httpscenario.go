@@ -0,0 +1,373 @@
+// Package httpscenario runs scenarios which are described in YAML as a list of HTTP requests,
+// rather than written in Go.
+//
+//	name: accounts
+//	base-url: '{{.Param "base-url"}}'
+//	parameters:
+//	  - name: base-url
+//	    default: http://localhost:8080
+//	setup:
+//	  - name: login
+//	    method: POST
+//	    url: /login
+//	    body: '{"user": "load-test"}'
+//	    extract:
+//	      token: access_token
+//	steps:
+//	  - name: create account
+//	    method: POST
+//	    url: /accounts
+//	    headers:
+//	      Authorization: 'Bearer {{.Vars.token}}'
+//	    body: '{"reference": "{{.Iteration}}"}'
+//	    expect:
+//	      status: 201
+//	      json:
+//	        data.status: pending
+//	    extract:
+//	      account_id: data.id
+//	  - name: get account
+//	    url: '/accounts/{{.Vars.account_id}}'
+//
+// The setup requests are sent once, before the iterations, and the teardown requests once they
+// complete, as ScenarioFn and T.Cleanup do. Each iteration sends the steps in order, and each
+// request is timed as a stage named after it. The url, headers, body and expected json values are
+// text/templates with the fields .Iteration, .VUID, .Param "name" and .Vars, the values extracted
+// from the responses of previous requests. Values extracted in the setup are seen by every
+// iteration and the teardown.
+package httpscenario
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/form3tech-oss/f1/v2/internal/jsonpath"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+const (
+	defaultTimeout = 30 * time.Second
+	// maxBodySize caps the size of the responses read.
+	maxBodySize = 1024 * 1024
+)
+
+// File is the YAML description of a scenario.
+type File struct {
+	Name        string            `yaml:"name"`
+	Description string            `yaml:"description"`
+	BaseURL     string            `yaml:"base-url"`
+	Timeout     *time.Duration    `yaml:"timeout"`
+	Headers     map[string]string `yaml:"headers"`
+	Parameters  []Parameter       `yaml:"parameters"`
+	Setup       []Request         `yaml:"setup"`
+	Steps       []Request         `yaml:"steps"`
+	Teardown    []Request         `yaml:"teardown"`
+}
+
+// Parameter is a scenario parameter, set with --param.
+type Parameter struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Default     string `yaml:"default"`
+}
+
+// Request is a request sent by the scenario. Its url is relative to the base-url, unless it is
+// absolute.
+type Request struct {
+	Name    string            `yaml:"name"`
+	Method  string            `yaml:"method"`
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+	Body    string            `yaml:"body"`
+	Expect  Expect            `yaml:"expect"`
+	// Extract maps the names of variables to the json paths of their values in the response.
+	Extract map[string]string `yaml:"extract"`
+}
+
+// Expect are the assertions on a response. Any 2xx status is expected when Status isn't set.
+type Expect struct {
+	Status int `yaml:"status"`
+	// JSON maps json paths, such as data.items.0.id, to their expected values.
+	JSON map[string]string `yaml:"json"`
+}
+
+// Load reads the scenario described in the file at path. The scenario is named after the file,
+// unless the file sets a name.
+func Load(path string) (*scenarios.Scenario, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading scenario file: %w", err)
+	}
+
+	var file File
+	if err := yaml.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("parsing scenario file as yaml: %w", err)
+	}
+	if file.Name == "" {
+		file.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	if file.Description == "" {
+		file.Description = "sends the requests of " + path
+	}
+
+	return New(file)
+}
+
+// New returns the scenario described by file.
+func New(file File) (*scenarios.Scenario, error) {
+	if len(file.Steps) == 0 {
+		return nil, errors.New("scenario has no steps")
+	}
+
+	baseURL, err := parseTemplate("base-url", file.BaseURL)
+	if err != nil {
+		return nil, err
+	}
+	setup, err := parseRequests("setup", file.Setup, file.Headers)
+	if err != nil {
+		return nil, err
+	}
+	steps, err := parseRequests("steps", file.Steps, file.Headers)
+	if err != nil {
+		return nil, err
+	}
+	teardown, err := parseRequests("teardown", file.Teardown, file.Headers)
+	if err != nil {
+		return nil, err
+	}
+
+	timeout := defaultTimeout
+	if file.Timeout != nil {
+		timeout = *file.Timeout
+	}
+	client := &http.Client{Timeout: timeout}
+
+	parameters := make([]scenarios.ScenarioParameter, len(file.Parameters))
+	for i, p := range file.Parameters {
+		parameters[i] = scenarios.ScenarioParameter(p)
+	}
+
+	return &scenarios.Scenario{
+		Name:        file.Name,
+		Description: file.Description,
+		Parameters:  parameters,
+		ScenarioFn: func(t *testing.T) testing.RunFn {
+			base := render(t, baseURL, newData(t, nil))
+
+			vars := map[string]string{}
+			sendAll(t, client, base, setup, vars)
+			if len(teardown) > 0 {
+				t.Cleanup(func() {
+					sendAll(t, client, base, teardown, maps.Clone(vars))
+				})
+			}
+
+			return func(t *testing.T) {
+				sendAll(t, client, base, steps, maps.Clone(vars))
+			}
+		},
+	}, nil
+}
+
+// request is a Request whose templates have been parsed.
+type request struct {
+	name    string
+	method  string
+	url     *template.Template
+	headers map[string]*template.Template
+	body    *template.Template
+	status  int
+	json    map[string]*template.Template
+	extract map[string]string
+}
+
+func parseRequests(block string, requests []Request, headers map[string]string) ([]*request, error) {
+	parsed := make([]*request, len(requests))
+	for i, r := range requests {
+		req, err := parseRequest(r, headers)
+		if err != nil {
+			return nil, fmt.Errorf("request %d of %s: %w", i+1, block, err)
+		}
+		parsed[i] = req
+	}
+
+	return parsed, nil
+}
+
+func parseRequest(r Request, headers map[string]string) (*request, error) {
+	if r.URL == "" {
+		return nil, errors.New("url is required")
+	}
+
+	req := &request{
+		name:    r.Name,
+		method:  strings.ToUpper(r.Method),
+		status:  r.Expect.Status,
+		headers: map[string]*template.Template{},
+		json:    map[string]*template.Template{},
+		extract: r.Extract,
+	}
+	if req.method == "" {
+		req.method = http.MethodGet
+	}
+	if req.name == "" {
+		req.name = req.method + " " + r.URL
+	}
+
+	var err error
+	if req.url, err = parseTemplate("url", r.URL); err != nil {
+		return nil, err
+	}
+	if req.body, err = parseTemplate("body", r.Body); err != nil {
+		return nil, err
+	}
+	for name, value := range headers {
+		name = http.CanonicalHeaderKey(name)
+		if req.headers[name], err = parseTemplate("header "+name, value); err != nil {
+			return nil, err
+		}
+	}
+	for name, value := range r.Headers {
+		name = http.CanonicalHeaderKey(name)
+		if req.headers[name], err = parseTemplate("header "+name, value); err != nil {
+			return nil, err
+		}
+	}
+	for path, value := range r.Expect.JSON {
+		if req.json[path], err = parseTemplate("expected "+path, value); err != nil {
+			return nil, err
+		}
+	}
+
+	return req, nil
+}
+
+func parseTemplate(name, text string) (*template.Template, error) {
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", name, err)
+	}
+
+	return tmpl, nil
+}
+
+// templateData is the data of the templates of a request.
+type templateData struct {
+	t *testing.T
+	// Vars are the values extracted from previous responses.
+	Vars map[string]string
+}
+
+func newData(t *testing.T, vars map[string]string) templateData {
+	return templateData{t: t, Vars: vars}
+}
+
+// Iteration returns the iteration number, or "setup" and "teardown" outside iterations.
+func (d templateData) Iteration() string {
+	return d.t.Iteration
+}
+
+// VUID returns the virtual user running the iteration.
+func (d templateData) VUID() int {
+	return d.t.VUID
+}
+
+// Param returns the value of the scenario parameter name.
+func (d templateData) Param(name string) string {
+	return d.t.Param(name)
+}
+
+func render(t *testing.T, tmpl *template.Template, data templateData) string {
+	var text strings.Builder
+	if err := tmpl.Execute(&text, data); err != nil {
+		t.Fatalf("rendering %s: %v", tmpl.Name(), err)
+	}
+
+	return text.String()
+}
+
+// sendAll sends the requests in order, adding the values they extract to vars.
+func sendAll(t *testing.T, client *http.Client, base string, requests []*request, vars map[string]string) {
+	for _, req := range requests {
+		send(t, client, base, req, vars)
+	}
+}
+
+func send(t *testing.T, client *http.Client, base string, req *request, vars map[string]string) {
+	data := newData(t, vars)
+
+	url := render(t, req.url, data)
+	if !strings.Contains(url, "://") {
+		url = base + url
+	}
+	httpReq, err := http.NewRequestWithContext(t.Context(), req.method, url,
+		strings.NewReader(render(t, req.body, data)))
+	if err != nil {
+		t.Fatalf("%s: creating request: %v", req.name, err)
+	}
+	for name, value := range req.headers {
+		httpReq.Header.Set(name, render(t, value, data))
+	}
+
+	var res *http.Response
+	var body []byte
+	t.Time(req.name, func() {
+		res, err = client.Do(httpReq)
+		if err != nil {
+			return
+		}
+		body, err = io.ReadAll(io.LimitReader(res.Body, maxBodySize))
+		_ = res.Body.Close()
+	})
+	if err != nil {
+		t.Fatalf("%s: %v", req.name, err)
+	}
+
+	switch {
+	case req.status != 0 && res.StatusCode != req.status:
+		t.Fatalf("%s: expected status %d, got %s", req.name, req.status, res.Status)
+	case req.status == 0 && (res.StatusCode < 200 || res.StatusCode > 299):
+		t.Fatalf("%s: expected a 2xx status, got %s", req.name, res.Status)
+	}
+
+	if len(req.json) == 0 && len(req.extract) == 0 {
+		return
+	}
+
+	var document any
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err := decoder.Decode(&document); err != nil {
+		t.Fatalf("%s: parsing response as json: %v", req.name, err)
+	}
+
+	for path, tmpl := range req.json {
+		value, err := jsonpath.Lookup(document, path)
+		if err != nil {
+			t.Fatalf("%s: %v", req.name, err)
+		}
+		if expected := render(t, tmpl, data); value != expected {
+			t.Fatalf("%s: expected %s to be %q, got %q", req.name, path, expected, value)
+		}
+	}
+	for name, path := range req.extract {
+		value, err := jsonpath.Lookup(document, path)
+		if err != nil {
+			t.Fatalf("%s: extracting %s: %v", req.name, name, err)
+		}
+		vars[name] = value
+	}
+}
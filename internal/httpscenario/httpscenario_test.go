@@ -0,0 +1,212 @@
+package httpscenario_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/httpscenario"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/f1test"
+)
+
+const flow = `
+base-url: '{{.Param "base-url"}}'
+parameters:
+  - name: base-url
+headers:
+  content-type: application/json
+setup:
+  - name: login
+    method: POST
+    url: /login
+    extract:
+      token: access_token
+steps:
+  - name: create account
+    method: POST
+    url: /accounts
+    headers:
+      Authorization: 'Bearer {{.Vars.token}}'
+    body: '{"reference": "ref-{{.Iteration}}"}'
+    expect:
+      status: 201
+      json:
+        data.reference: 'ref-{{.Iteration}}'
+        data.versions.0: "1"
+    extract:
+      account_id: data.id
+  - url: '/accounts/{{.Vars.account_id}}'
+teardown:
+  - name: logout
+    method: DELETE
+    url: /login
+`
+
+func TestHTTPScenario_SendsTheRequestsOfEachBlock(t *testing.T) {
+	t.Parallel()
+
+	server := newAccountsServer(t)
+	scenario, err := httpscenario.Load(writeFile(t, "accounts.yaml", flow))
+	require.NoError(t, err)
+
+	result := f1test.RunScenario(t, scenario.ScenarioFn,
+		f1test.Iterations(2), f1test.Param("base-url", server.URL))
+
+	assert.Equal(t, "accounts", scenario.Name)
+	require.Len(t, result.Setup.Stages, 1)
+	assert.Equal(t, "login", result.Setup.Stages[0].Name)
+	assert.Len(t, result.StageDurations("create account"), 2)
+	assert.Len(t, result.StageDurations("GET /accounts/{{.Vars.account_id}}"), 2)
+	assert.Equal(t, []string{
+		"POST /login",
+		"POST /accounts", "GET /accounts/account-ref-1",
+		"POST /accounts", "GET /accounts/account-ref-2",
+		"DELETE /login",
+	}, server.requests())
+}
+
+func TestHTTPScenario_FailsOnUnexpectedStatus(t *testing.T) {
+	t.Parallel()
+
+	server := newAccountsServer(t)
+	scenario, err := httpscenario.New(httpscenario.File{
+		BaseURL: server.URL,
+		Steps: []httpscenario.Request{
+			{URL: "/accounts/missing"},
+			{URL: "/accounts/never-sent"},
+		},
+	})
+	require.NoError(t, err)
+
+	result := f1test.RunScenario(t, scenario.ScenarioFn, f1test.ExpectFailures())
+
+	require.Len(t, result.Failed(), 1)
+	assert.Equal(t, "GET /accounts/missing: expected a 2xx status, got 404 Not Found",
+		result.Iterations[0].FailureReason)
+	assert.Equal(t, []string{"GET /accounts/missing"}, server.requests())
+}
+
+func TestHTTPScenario_FailsOnUnexpectedJSONValues(t *testing.T) {
+	t.Parallel()
+
+	server := newAccountsServer(t)
+	scenario, err := httpscenario.New(httpscenario.File{
+		BaseURL: server.URL,
+		Steps: []httpscenario.Request{{
+			Name:   "create account",
+			Method: "post",
+			URL:    "/accounts",
+			Body:   `{"reference": "abc"}`,
+			Expect: httpscenario.Expect{
+				Status: http.StatusCreated,
+				JSON:   map[string]string{"data.reference": "def"},
+			},
+		}},
+	})
+	require.NoError(t, err)
+
+	result := f1test.RunScenario(t, scenario.ScenarioFn, f1test.ExpectFailures())
+
+	require.Len(t, result.Failed(), 1)
+	assert.Equal(t, `create account: expected data.reference to be "def", got "abc"`,
+		result.Iterations[0].FailureReason)
+}
+
+func TestHTTPScenario_RejectsInvalidFiles(t *testing.T) {
+	t.Parallel()
+
+	for name, test := range map[string]struct {
+		content string
+		err     string
+	}{
+		"no steps":         {content: "name: empty", err: "scenario has no steps"},
+		"no url":           {content: "steps: [{name: a}]", err: "request 1 of steps: url is required"},
+		"invalid template": {content: "steps: [{url: '/{{.Vars'}]", err: "request 1 of steps: parsing url"},
+		"invalid yaml":     {content: "steps: {", err: "parsing scenario file as yaml"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := httpscenario.Load(writeFile(t, "flow.yaml", test.content))
+			require.ErrorContains(t, err, test.err)
+		})
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+type accountsServer struct {
+	*httptest.Server
+	mu   sync.Mutex
+	sent []string
+}
+
+func (s *accountsServer) requests() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.sent)
+}
+
+// newAccountsServer returns a server which creates accounts for logged in clients.
+func newAccountsServer(t *testing.T) *accountsServer {
+	t.Helper()
+
+	s := &accountsServer{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /login", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"access_token": "secret"}`))
+	})
+	mux.HandleFunc("DELETE /login", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /accounts", func(w http.ResponseWriter, r *http.Request) {
+		var account struct {
+			Reference string `json:"reference"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&account); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.Header.Get("Authorization") != "" && r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+			"id":        "account-" + account.Reference,
+			"reference": account.Reference,
+			"versions":  []int{1},
+		}})
+	})
+	mux.HandleFunc("GET /accounts/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") == "missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.sent = append(s.sent, r.Method+" "+r.URL.Path)
+		s.mu.Unlock()
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.Close)
+
+	return s
+}
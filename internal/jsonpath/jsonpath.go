@@ -0,0 +1,45 @@
+// Package jsonpath looks up values in JSON documents by simple paths, such as data.items.0.id.
+package jsonpath
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Lookup returns the value at path in document, decoded by encoding/json, where path is a list of
+// object keys and array indexes separated by dots, such as data.items.0.id. Strings are returned
+// as they are, and other values as json.
+func Lookup(document any, path string) (string, error) {
+	value := document
+	for segment := range strings.SplitSeq(path, ".") {
+		switch v := value.(type) {
+		case map[string]any:
+			field, ok := v[segment]
+			if !ok {
+				return "", fmt.Errorf("%s: no field %q", path, segment)
+			}
+			value = field
+		case []any:
+			index, err := strconv.Atoi(segment)
+			if err != nil || index < 0 || index >= len(v) {
+				return "", fmt.Errorf("%s: no index %q in an array of %d items", path, segment, len(v))
+			}
+			value = v[index]
+		default:
+			return "", fmt.Errorf("%s: %q isn't in an object or array", path, segment)
+		}
+	}
+
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", path, err)
+	}
+
+	return string(encoded), nil
+}
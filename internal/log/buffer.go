@@ -0,0 +1,164 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// maxBufferedRecords caps the number of records kept for a single buffer, the oldest
+// records are dropped first.
+const maxBufferedRecords = 1000
+
+var _ slog.Handler = (*BufferHandler)(nil)
+
+// BufferHandler keeps log records in memory until they are either flushed to the next
+// handler or discarded.
+type BufferHandler struct {
+	next   slog.Handler
+	buffer *recordBuffer
+}
+
+type bufferedRecord struct {
+	handler slog.Handler
+	record  slog.Record
+}
+
+type recordBuffer struct {
+	records []bufferedRecord
+	dropped int
+	mu      sync.Mutex
+}
+
+func NewBufferHandler(next slog.Handler) *BufferHandler {
+	return &BufferHandler{
+		next:   next,
+		buffer: &recordBuffer{},
+	}
+}
+
+func (h *BufferHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *BufferHandler) Handle(_ context.Context, record slog.Record) error {
+	h.buffer.mu.Lock()
+	defer h.buffer.mu.Unlock()
+
+	if len(h.buffer.records) == maxBufferedRecords {
+		copy(h.buffer.records, h.buffer.records[1:])
+		h.buffer.records = h.buffer.records[:maxBufferedRecords-1]
+		h.buffer.dropped++
+	}
+	h.buffer.records = append(h.buffer.records, bufferedRecord{handler: h.next, record: record.Clone()})
+
+	return nil
+}
+
+func (h *BufferHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &BufferHandler{next: h.next.WithAttrs(attrs), buffer: h.buffer}
+}
+
+func (h *BufferHandler) WithGroup(name string) slog.Handler {
+	return &BufferHandler{next: h.next.WithGroup(name), buffer: h.buffer}
+}
+
+// Flush writes the buffered records to the next handler and empties the buffer. The attrs are
+// added to records which don't already have an attribute with the same key.
+func (h *BufferHandler) Flush(ctx context.Context, attrs ...slog.Attr) error {
+	h.buffer.mu.Lock()
+	records := h.buffer.records
+	dropped := h.buffer.dropped
+	h.buffer.records = nil
+	h.buffer.dropped = 0
+	h.buffer.mu.Unlock()
+
+	if dropped > 0 {
+		record := slog.NewRecord(records[0].record.Time, slog.LevelWarn,
+			fmt.Sprintf("%d earlier log records were dropped", dropped), 0)
+		record.AddAttrs(attrs...)
+		if err := h.next.Handle(ctx, record); err != nil {
+			return fmt.Errorf("flushing log records: %w", err)
+		}
+	}
+
+	for _, buffered := range records {
+		if !buffered.handler.Enabled(ctx, buffered.record.Level) {
+			continue
+		}
+
+		record := buffered.record.Clone()
+		record.AddAttrs(missingAttrs(record, attrs)...)
+		if err := buffered.handler.Handle(ctx, record); err != nil {
+			return fmt.Errorf("flushing log records: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Next returns the handler the records are flushed to.
+func (h *BufferHandler) Next() slog.Handler {
+	return h.next
+}
+
+// Discard empties the buffer without writing the records.
+func (h *BufferHandler) Discard() {
+	h.buffer.mu.Lock()
+	defer h.buffer.mu.Unlock()
+
+	h.buffer.records = nil
+	h.buffer.dropped = 0
+}
+
+func missingAttrs(record slog.Record, attrs []slog.Attr) []slog.Attr {
+	missing := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		found := false
+		record.Attrs(func(a slog.Attr) bool {
+			found = a.Key == attr.Key
+			return !found
+		})
+		if !found {
+			missing = append(missing, attr)
+		}
+	}
+
+	return missing
+}
+
+// FlushLimit limits the number of buffers flushed across a run.
+type FlushLimit struct {
+	max     uint64
+	flushed atomic.Uint64
+	// passed is set when the buffers of iterations which passed are flushed too
+	passed bool
+}
+
+// NewFlushLimit returns a limit of maxFlushes, 0 means no limit.
+func NewFlushLimit(maxFlushes uint64) *FlushLimit {
+	return &FlushLimit{max: maxFlushes}
+}
+
+// NewFlushAll returns a limit flushing the buffers of every iteration, including the ones which
+// passed, with no limit.
+func NewFlushAll() *FlushLimit {
+	return &FlushLimit{passed: true}
+}
+
+// Passed reports whether the buffers of iterations which passed are flushed.
+func (l *FlushLimit) Passed() bool {
+	return l.passed
+}
+
+// Allow reports whether a buffer can be flushed, and whether this is the last one allowed.
+func (l *FlushLimit) Allow() (bool, bool) {
+	if l.max == 0 {
+		return true, false
+	}
+
+	flushed := l.flushed.Add(1)
+	return flushed <= l.max, flushed == l.max
+}
@@ -1,11 +1,16 @@
 package metrics
 
 import (
-	"errors"
+	"fmt"
+	"regexp"
+	"slices"
 	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 )
 
 const (
@@ -17,30 +22,104 @@ const (
 	TestNameLabel = "test"
 	StageLabel    = "stage"
 	ResultLabel   = "result"
+	ErrorLabel    = "error"
+	// QueueLabel and OperationLabel are labels of the queue metrics.
+	QueueLabel     = "queue"
+	OperationLabel = "operation"
 )
 
 const IterationStage = "iteration"
 
+const (
+	// maxErrorLabelValues caps the number of distinct values of the error label
+	maxErrorLabelValues = 20
+	// OtherErrorLabelValue is used for the error label once maxErrorLabelValues is reached
+	OtherErrorLabelValue = "other"
+	// maxTagLabelValues caps the number of distinct values of each tag label
+	maxTagLabelValues = 50
+	// OtherTagLabelValue is used for a tag label once maxTagLabelValues is reached
+	OtherTagLabelValue = "other"
+)
+
 type Metrics struct {
 	Setup                   *prometheus.SummaryVec
 	Iteration               *prometheus.SummaryVec
+	Callbacks               *prometheus.CounterVec
+	Retries                 *prometheus.SummaryVec
+	QueueDepth              *prometheus.GaugeVec
+	QueueWait               *prometheus.SummaryVec
 	Registry                *prometheus.Registry
-	IterationMetricsEnabled bool
+	errorLabelValues        map[string]struct{}
+	tagLabelValues          map[string]map[string]struct{}
+	staticMetricLabelKeys   []string
 	staticMetricLabelValues []string
+	tagLabelKeys            []string
+	errorLabelMu            sync.Mutex
+	tagLabelMu              sync.Mutex
+	IterationMetricsEnabled bool
+	errorLabelEnabled       bool
 }
 
-//nolint:gochecknoglobals // removing the global Instance is a breaking change
-var (
-	m    *Metrics
-	once sync.Once
-)
+type Option func(*Metrics)
+
+// Factory creates the metrics of a run, with the options required by its scenario.
+type Factory func(options ...Option) *Metrics
+
+// labelNamePattern matches valid prometheus label names
+var labelNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// WithErrorLabel adds an "error" label to the iteration metric, with the failure reason of
+// failed iterations. The number of distinct label values is bounded, any further failure
+// reasons are recorded as "other".
+func WithErrorLabel(enabled bool) Option {
+	return func(m *Metrics) {
+		m.errorLabelEnabled = enabled
+	}
+}
+
+// WithTagLabels adds a label to the iteration metric for each of keys, with the value the
+// iteration tagged the key with, see testing.T.Tag. The number of distinct values of each label
+// is bounded, any further values are recorded as "other".
+func WithTagLabels(keys ...string) Option {
+	return func(m *Metrics) {
+		m.tagLabelKeys = keys
+	}
+}
 
-func buildMetrics(staticMetrics map[string]string) *Metrics {
-	percentileObjectives := map[float64]float64{
-		0.5: 0.05, 0.75: 0.05, 0.9: 0.01, 0.95: 0.001, 0.99: 0.001, 0.9999: 0.00001, 1.0: 0.00001,
+// ValidateTagLabels returns an error if any of keys isn't a valid label name, or is already a
+// label of the iteration metric.
+func ValidateTagLabels(keys []string) error {
+	seen := make(map[string]struct{}, len(keys))
+	for _, key := range keys {
+		if !labelNamePattern.MatchString(key) || strings.HasPrefix(key, "__") {
+			return fmt.Errorf("tag label '%s' is not a valid label name", key)
+		}
+		if _, ok := seen[key]; ok {
+			return fmt.Errorf("tag label '%s' is declared more than once", key)
+		}
+		if slices.Contains([]string{TestNameLabel, StageLabel, ResultLabel, ErrorLabel}, key) {
+			return fmt.Errorf("tag label '%s' is already a label of the iteration metric", key)
+		}
+		seen[key] = struct{}{}
 	}
+
+	return nil
+}
+
+// latest is the most recently created instance, only returned by the deprecated Latest
+//
+//nolint:gochecknoglobals // kept for backwards compatibility of the deprecated metrics.GetMetrics
+var latest atomic.Pointer[Metrics]
+
+//nolint:gochecknoglobals // shared by the setup and iteration metrics
+var percentileObjectives = map[float64]float64{
+	0.5: 0.05, 0.75: 0.05, 0.9: 0.01, 0.95: 0.001, 0.99: 0.001, 0.9999: 0.00001, 1.0: 0.00001,
+}
+
+func buildMetrics(staticMetrics map[string]string, errorLabelEnabled bool, tagLabelKeys []string) *Metrics {
 	labelKeys := getStaticMetricLabelKeys(staticMetrics)
-	return &Metrics{
+
+	m := &Metrics{
 		Setup: prometheus.NewSummaryVec(prometheus.SummaryOpts{
 			Namespace:  metricNamespace,
 			Subsystem:  metricSubsystem,
@@ -48,53 +127,144 @@ func buildMetrics(staticMetrics map[string]string) *Metrics {
 			Help:       "Duration of setup functions.",
 			Objectives: percentileObjectives,
 		}, append([]string{TestNameLabel, ResultLabel}, labelKeys...)),
-		Iteration: prometheus.NewSummaryVec(prometheus.SummaryOpts{
+		Callbacks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricNamespace,
+			Subsystem: metricSubsystem,
+			Name:      "callbacks_total",
+			Help:      "Number of callbacks received, missing or unexpected.",
+		}, append([]string{TestNameLabel, ResultLabel}, labelKeys...)),
+		Retries: prometheus.NewSummaryVec(prometheus.SummaryOpts{
 			Namespace:  metricNamespace,
 			Subsystem:  metricSubsystem,
-			Name:       "iteration",
-			Help:       "Duration of iteration functions.",
+			Name:       "retry_attempts",
+			Help:       "Number of attempts of retried stages and polled conditions.",
 			Objectives: percentileObjectives,
 		}, append([]string{TestNameLabel, StageLabel, ResultLabel}, labelKeys...)),
+		QueueDepth: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: metricNamespace,
+			Subsystem: metricSubsystem,
+			Name:      "queue_depth",
+			Help:      "Number of items in shared queues.",
+		}, append([]string{TestNameLabel, QueueLabel}, labelKeys...)),
+		QueueWait: prometheus.NewSummaryVec(prometheus.SummaryOpts{
+			Namespace:  metricNamespace,
+			Subsystem:  metricSubsystem,
+			Name:       "queue_wait",
+			Help:       "Duration of waits to put items in, or take items from, shared queues.",
+			Objectives: percentileObjectives,
+		}, append([]string{TestNameLabel, QueueLabel, OperationLabel, ResultLabel}, labelKeys...)),
+		staticMetricLabelKeys: labelKeys,
+		tagLabelKeys:          slices.Clone(tagLabelKeys),
+		errorLabelEnabled:     errorLabelEnabled,
+		errorLabelValues:      make(map[string]struct{}),
+		tagLabelValues:        make(map[string]map[string]struct{}, len(tagLabelKeys)),
+	}
+	m.Iteration = m.buildIterationMetric()
+
+	return m
+}
+
+func (metrics *Metrics) buildIterationMetric() *prometheus.SummaryVec {
+	labelKeys := []string{TestNameLabel, StageLabel, ResultLabel}
+	if metrics.errorLabelEnabled {
+		labelKeys = append(labelKeys, ErrorLabel)
 	}
+	labelKeys = append(labelKeys, metrics.tagLabelKeys...)
+
+	return prometheus.NewSummaryVec(prometheus.SummaryOpts{
+		Namespace:  metricNamespace,
+		Subsystem:  metricSubsystem,
+		Name:       "iteration",
+		Help:       "Duration of iteration functions.",
+		Objectives: percentileObjectives,
+	}, append(labelKeys, metrics.staticMetricLabelKeys...))
 }
 
 func NewInstance(registry *prometheus.Registry,
 	iterationMetricsEnabled bool,
 	staticMetrics map[string]string,
+	options ...Option,
 ) *Metrics {
-	i := buildMetrics(staticMetrics)
+	opts := &Metrics{}
+	for _, opt := range options {
+		opt(opts)
+	}
+
+	i := buildMetrics(staticMetrics, opts.errorLabelEnabled, opts.tagLabelKeys)
 	i.Registry = registry
 
-	i.Registry.MustRegister(
-		i.Setup,
-		i.Iteration,
-	)
+	i.Registry.MustRegister(i.collectors()...)
 	i.IterationMetricsEnabled = iterationMetricsEnabled
 	i.staticMetricLabelValues = getStaticMetricLabelValues(staticMetrics)
 	return i
 }
 
-func Init(iterationMetricsEnabled bool) {
-	InitWithStaticMetrics(iterationMetricsEnabled, nil)
+// New returns metrics registered on a new registry, which also collects the Go runtime and
+// process metrics. Each run has its own metrics, so that runs in the same process don't share them.
+func New(iterationMetricsEnabled bool, staticMetrics map[string]string, options ...Option) *Metrics {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	i := NewInstance(registry, iterationMetricsEnabled, staticMetrics, options...)
+	latest.Store(i)
+
+	return i
 }
 
-func InitWithStaticMetrics(iterationMetricsEnabled bool, staticMetrics map[string]string) {
-	once.Do(func() {
-		defaultRegistry, ok := prometheus.DefaultRegisterer.(*prometheus.Registry)
-		if !ok {
-			panic(errors.New("casting prometheus.DefaultRegisterer to Registry"))
-		}
-		m = NewInstance(defaultRegistry, iterationMetricsEnabled, staticMetrics)
-	})
+func (metrics *Metrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		metrics.Setup,
+		metrics.Iteration,
+		metrics.Callbacks,
+		metrics.Retries,
+		metrics.QueueDepth,
+		metrics.QueueWait,
+	}
 }
 
-func Instance() *Metrics {
-	return m
+// Forwarder collects the metrics of the most recent run it was given, so that they can be
+// registered on a registry which outlives the runs, such as prometheus.DefaultRegisterer.
+type Forwarder struct {
+	current atomic.Pointer[Metrics]
+}
+
+// Forward makes the forwarder collect metrics, instead of those of a previous run.
+func (f *Forwarder) Forward(metrics *Metrics) {
+	f.current.Store(metrics)
+}
+
+// Describe describes nothing, which makes the forwarder an unchecked collector, as the labels of
+// the metrics depend on the run.
+func (f *Forwarder) Describe(chan<- *prometheus.Desc) {}
+
+func (f *Forwarder) Collect(ch chan<- prometheus.Metric) {
+	metrics := f.current.Load()
+	if metrics == nil {
+		return
+	}
+
+	for _, collector := range metrics.collectors() {
+		collector.Collect(ch)
+	}
+}
+
+// Latest returns the most recently created metrics, or nil if none were created.
+//
+// Deprecated: metrics are created for each run, and should be passed to where they are used.
+func Latest() *Metrics {
+	return latest.Load()
 }
 
 func (metrics *Metrics) Reset() {
 	metrics.Iteration.Reset()
 	metrics.Setup.Reset()
+	metrics.Callbacks.Reset()
+	metrics.Retries.Reset()
+	metrics.QueueDepth.Reset()
+	metrics.QueueWait.Reset()
 }
 
 func (metrics *Metrics) RecordSetupResult(name string, result ResultType, nanoseconds int64) {
@@ -102,22 +272,146 @@ func (metrics *Metrics) RecordSetupResult(name string, result ResultType, nanose
 	metrics.Setup.WithLabelValues(labels...).Observe(float64(nanoseconds))
 }
 
+// RecordCallback counts a callback with result, such as received, missing or unexpected.
+func (metrics *Metrics) RecordCallback(name string, result string) {
+	labels := append([]string{name, result}, metrics.staticMetricLabelValues...)
+	metrics.Callbacks.WithLabelValues(labels...).Inc()
+}
+
+// RecordRetry records the number of attempts of a retried stage or polled condition, with the
+// result of the last one.
+func (metrics *Metrics) RecordRetry(name string, stage string, result ResultType, attempts int) {
+	labels := append([]string{name, stage, result.String()}, metrics.staticMetricLabelValues...)
+	metrics.Retries.WithLabelValues(labels...).Observe(float64(attempts))
+}
+
+// RecordQueueDepth records the number of items in a queue.
+func (metrics *Metrics) RecordQueueDepth(name string, queue string, depth int) {
+	labels := append([]string{name, queue}, metrics.staticMetricLabelValues...)
+	metrics.QueueDepth.WithLabelValues(labels...).Set(float64(depth))
+}
+
+// RecordQueueWait records the wait of an operation, put or take, on a queue.
+func (metrics *Metrics) RecordQueueWait(
+	name string,
+	queue string,
+	operation string,
+	result ResultType,
+	nanoseconds int64,
+) {
+	labels := append([]string{name, queue, operation, result.String()}, metrics.staticMetricLabelValues...)
+	metrics.QueueWait.WithLabelValues(labels...).Observe(float64(nanoseconds))
+}
+
 func (metrics *Metrics) RecordIterationResult(name string, result ResultType, nanoseconds int64) {
+	metrics.RecordTaggedIteration(name, result, "", nil, nanoseconds)
+}
+
+// RecordFailedIteration records a failed iteration, using reason as the error label value
+// if the error label is enabled.
+func (metrics *Metrics) RecordFailedIteration(name string, reason string, nanoseconds int64) {
+	metrics.RecordTaggedIteration(name, FailedResult, reason, nil, nanoseconds)
+}
+
+// RecordTaggedIteration records an iteration with the values of its tag labels. reason is the
+// error label value of failed iterations, if the error label is enabled.
+func (metrics *Metrics) RecordTaggedIteration(
+	name string,
+	result ResultType,
+	reason string,
+	tags map[string]string,
+	nanoseconds int64,
+) {
 	if !metrics.IterationMetricsEnabled {
 		return
 	}
-	labels := append([]string{name, IterationStage, result.String()}, metrics.staticMetricLabelValues...)
+	labels := metrics.iterationLabels(name, IterationStage, result, reason, tags)
 	metrics.Iteration.WithLabelValues(labels...).Observe(float64(nanoseconds))
 }
 
 func (metrics *Metrics) RecordIterationStage(name string, stage string, result ResultType, nanoseconds int64) {
+	metrics.RecordTaggedIterationStage(name, stage, result, nil, nanoseconds)
+}
+
+// RecordTaggedIterationStage records a stage of an iteration with the values of its tag labels.
+func (metrics *Metrics) RecordTaggedIterationStage(
+	name string,
+	stage string,
+	result ResultType,
+	tags map[string]string,
+	nanoseconds int64,
+) {
 	if !metrics.IterationMetricsEnabled {
 		return
 	}
-	labels := append([]string{name, stage, result.String()}, metrics.staticMetricLabelValues...)
+	labels := metrics.iterationLabels(name, stage, result, "", tags)
 	metrics.Iteration.WithLabelValues(labels...).Observe(float64(nanoseconds))
 }
 
+func (metrics *Metrics) iterationLabels(
+	name string,
+	stage string,
+	result ResultType,
+	reason string,
+	tags map[string]string,
+) []string {
+	labels := []string{name, stage, result.String()}
+	if metrics.errorLabelEnabled {
+		labels = append(labels, metrics.errorLabelValue(reason))
+	}
+	for _, key := range metrics.tagLabelKeys {
+		labels = append(labels, metrics.tagLabelValue(key, tags[key]))
+	}
+
+	return append(labels, metrics.staticMetricLabelValues...)
+}
+
+func (metrics *Metrics) tagLabelValue(key string, value string) string {
+	if value == "" {
+		return ""
+	}
+
+	metrics.tagLabelMu.Lock()
+	defer metrics.tagLabelMu.Unlock()
+
+	values, ok := metrics.tagLabelValues[key]
+	if !ok {
+		values = make(map[string]struct{})
+		metrics.tagLabelValues[key] = values
+	}
+
+	if _, ok := values[value]; ok {
+		return value
+	}
+
+	if len(values) >= maxTagLabelValues {
+		return OtherTagLabelValue
+	}
+
+	values[value] = struct{}{}
+	return value
+}
+
+func (metrics *Metrics) errorLabelValue(reason string) string {
+	if reason == "" {
+		return ""
+	}
+
+	metrics.errorLabelMu.Lock()
+	defer metrics.errorLabelMu.Unlock()
+
+	if _, ok := metrics.errorLabelValues[reason]; ok {
+		return reason
+	}
+
+	if len(metrics.errorLabelValues) >= maxErrorLabelValues {
+		return OtherErrorLabelValue
+	}
+
+	metrics.errorLabelValues[reason] = struct{}{}
+	return reason
+}
+
 func getStaticMetricLabelKeys(staticMetrics map[string]string) []string {
 	return sortedKeys(staticMetrics)
 }
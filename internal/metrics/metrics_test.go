@@ -6,6 +6,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -13,7 +14,7 @@ import (
 	"github.com/form3tech-oss/f1/v2/internal/metrics"
 )
 
-func TestMetrics_Init_IsSafe(t *testing.T) {
+func TestMetrics_New_AddsStaticLabels(t *testing.T) {
 	t.Parallel()
 	labels := map[string]string{
 		"product":  "fps",
@@ -21,15 +22,10 @@ func TestMetrics_Init_IsSafe(t *testing.T) {
 		"f1_id":    "myid",
 		"labelx":   "vx",
 	}
-	metrics.InitWithStaticMetrics(true, labels) // race detector assertion
-	for range 10 {
-		go func() {
-			metrics.InitWithStaticMetrics(true, labels)
-		}()
-	}
-	assert.True(t, metrics.Instance().IterationMetricsEnabled)
-	metrics.Instance().RecordIterationResult("test1", metrics.SuccessResult, 1)
-	assert.Equal(t, 1, testutil.CollectAndCount(metrics.Instance().Iteration, "form3_loadtest_iteration"))
+	m := metrics.New(true, labels)
+	assert.True(t, m.IterationMetricsEnabled)
+	m.RecordIterationResult("test1", metrics.SuccessResult, 1)
+	assert.Equal(t, 1, testutil.CollectAndCount(m.Iteration, "form3_loadtest_iteration"))
 
 	var expected strings.Builder
 	expected.WriteString(`
@@ -48,5 +44,106 @@ func TestMetrics_Init_IsSafe(t *testing.T) {
         	      form3_loadtest_iteration_count{customer="fake-customer",f1_id="myid",labelx="vx",product="fps",result="success",stage="iteration",test="test1"} 1
 				`)
 	r := bytes.NewReader([]byte(expected.String()))
-	require.NoError(t, testutil.CollectAndCompare(metrics.Instance().Iteration, r))
+	require.NoError(t, testutil.CollectAndCompare(m.Iteration, r))
+}
+
+func TestMetrics_New_InstancesAreIndependent(t *testing.T) {
+	t.Parallel()
+
+	first := metrics.New(true, map[string]string{"run": "first"})
+	second := metrics.New(true, map[string]string{"run": "second"})
+
+	first.RecordIterationResult("test1", metrics.SuccessResult, 1)
+	first.RecordIterationResult("test1", metrics.SuccessResult, 1)
+	second.RecordIterationResult("test1", metrics.FailedResult, 1)
+
+	assert.Equal(t, 1, testutil.CollectAndCount(first.Iteration, "form3_loadtest_iteration"))
+	assert.Equal(t, 1, testutil.CollectAndCount(second.Iteration, "form3_loadtest_iteration"))
+	assert.NotSame(t, first.Registry, second.Registry)
+
+	families, err := second.Registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() == "form3_loadtest_iteration" {
+			require.Len(t, family.GetMetric(), 1)
+			assert.Equal(t, uint64(1), family.GetMetric()[0].GetSummary().GetSampleCount())
+		}
+	}
+}
+
+func TestMetrics_ErrorLabel_IsBounded(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.NewInstance(prometheus.NewRegistry(), true, nil, metrics.WithErrorLabel(true))
+
+	m.RecordIterationResult("test1", metrics.SuccessResult, 1)
+	for i := range 25 {
+		m.RecordFailedIteration("test1", fmt.Sprintf("reason %d", i), 1)
+	}
+	m.RecordFailedIteration("test1", "reason 0", 1)
+
+	// success, 20 distinct reasons and "other"
+	assert.Equal(t, 22, testutil.CollectAndCount(m.Iteration, "form3_loadtest_iteration"))
+	assert.Equal(t, uint64(2), summaryCount(t, m, metrics.ErrorLabel, "reason 0"))
+	assert.Equal(t, uint64(5), summaryCount(t, m, metrics.ErrorLabel, metrics.OtherErrorLabelValue))
+}
+
+func TestMetrics_TagLabels_AreRecorded(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.NewInstance(prometheus.NewRegistry(), true, map[string]string{"run": "first"},
+		metrics.WithTagLabels("tenant"))
+
+	m.RecordTaggedIteration("test1", metrics.SuccessResult, "", map[string]string{"tenant": "acme", "scheme": "sepa"}, 1)
+	m.RecordTaggedIteration("test1", metrics.SuccessResult, "", map[string]string{"tenant": "acme"}, 1)
+	m.RecordTaggedIterationStage("test1", "login", metrics.SuccessResult, map[string]string{"tenant": "globex"}, 1)
+	m.RecordIterationResult("test1", metrics.SuccessResult, 1)
+
+	assert.Equal(t, 3, testutil.CollectAndCount(m.Iteration, "form3_loadtest_iteration"))
+	assert.Equal(t, uint64(2), summaryCount(t, m, "tenant", "acme"))
+	assert.Equal(t, uint64(1), summaryCount(t, m, "tenant", "globex"))
+	assert.Equal(t, uint64(1), summaryCount(t, m, "tenant", ""))
+	assert.Zero(t, summaryCount(t, m, "scheme", "sepa"))
+}
+
+func TestMetrics_TagLabels_AreBounded(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.NewInstance(prometheus.NewRegistry(), true, nil, metrics.WithTagLabels("tenant"))
+
+	for i := range 60 {
+		m.RecordTaggedIteration("test1", metrics.SuccessResult, "", map[string]string{"tenant": fmt.Sprintf("t%d", i)}, 1)
+	}
+
+	// 50 distinct tenants and "other"
+	assert.Equal(t, 51, testutil.CollectAndCount(m.Iteration, "form3_loadtest_iteration"))
+	assert.Equal(t, uint64(10), summaryCount(t, m, "tenant", metrics.OtherTagLabelValue))
+}
+
+func TestMetrics_ValidateTagLabels(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, metrics.ValidateTagLabels([]string{"tenant", "payment_scheme"}))
+	require.ErrorContains(t, metrics.ValidateTagLabels([]string{"payment-scheme"}), "not a valid label name")
+	require.ErrorContains(t, metrics.ValidateTagLabels([]string{"tenant", "tenant"}), "more than once")
+	require.ErrorContains(t, metrics.ValidateTagLabels([]string{metrics.ResultLabel}), "already a label")
+}
+
+func summaryCount(t *testing.T, m *metrics.Metrics, labelName string, labelValue string) uint64 {
+	t.Helper()
+
+	families, err := m.Registry.Gather()
+	require.NoError(t, err)
+
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == labelName && label.GetValue() == labelValue {
+					return metric.GetSummary().GetSampleCount()
+				}
+			}
+		}
+	}
+
+	return 0
 }
@@ -7,6 +7,14 @@ const (
 	FailedResult  ResultType = "fail"
 	DroppedResult ResultType = "dropped"
 	UnknownResult ResultType = "unknown"
+	// ExhaustedResult is the result of retried stages which failed on every attempt.
+	ExhaustedResult ResultType = "exhausted"
+	// SkippedResult is the result of takes from empty queues which skip the work of the iteration.
+	SkippedResult ResultType = "skipped"
+	// MetResult and NotMetResult are the results of the attempts of conditions polled with
+	// T.Eventually, recorded with the attempts of retried stages.
+	MetResult    ResultType = "met"
+	NotMetResult ResultType = "not_met"
 )
 
 func (r ResultType) String() string {
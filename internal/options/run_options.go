@@ -2,18 +2,37 @@ package options
 
 import (
 	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/queues"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/reporter"
 )
 
 type RunOptions struct {
-	Scenario                 string
-	MaxDuration              time.Duration
-	Concurrency              int
-	MaxIterations            uint64
-	MaxFailures              uint64
-	MaxFailuresRate          int
-	Verbose                  bool
+	Scenario        string
+	MaxDuration     time.Duration
+	Concurrency     int
+	MaxIterations   uint64
+	MaxFailures     uint64
+	MaxFailuresRate int
+	Verbose         bool
+	// VerboseFail buffers the logs of each iteration, and only writes the logs of failed iterations
+	VerboseFail              bool
+	TUI                      bool
 	IgnoreDropped            bool
 	WaitForCompletionTimeout time.Duration
+	// MaxFailedIterationLogs limits the number of failed iterations logged with VerboseFail, 0 means no limit
+	MaxFailedIterationLogs uint64
+	// Params are the scenario parameter values set with --param
+	Params map[string]string
+	// EventsFile is the path of the file where an event is written for each iteration
+	EventsFile string
+	// Reporter receives the events of the run, if set
+	Reporter reporter.Reporter
+	// Seed seeds the random choices of the trigger and of T.Rand
+	Seed uint64
+	// Queues holds the queues shared with the other runs of the process, or nil for queues of this
+	// run only
+	Queues *queues.Registry
 }
 
 func (o *RunOptions) LogToFile() bool {
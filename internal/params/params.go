@@ -0,0 +1,104 @@
+// Package params holds the values of scenario parameters during a run.
+package params
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync/atomic"
+)
+
+// Params are the values of scenario parameters. Values set for the current stage of a file
+// trigger take precedence over the values set at the start of the run.
+type Params struct {
+	values atomic.Pointer[map[string]string]
+	stage  atomic.Pointer[map[string]string]
+}
+
+// Declared is a scenario parameter declared with a default value.
+type Declared struct {
+	Name    string
+	Default string
+}
+
+// New returns the parameters with the declared defaults overridden by values. Names in values
+// must have been declared.
+func New(declared []Declared, values map[string]string) (*Params, error) {
+	merged := make(map[string]string, len(declared))
+	for _, d := range declared {
+		merged[d.Name] = d.Default
+	}
+
+	if err := Check(declared, values); err != nil {
+		return nil, err
+	}
+	maps.Copy(merged, values)
+
+	p := &Params{}
+	p.values.Store(&merged)
+
+	return p, nil
+}
+
+// Check returns an error if any name in values hasn't been declared.
+func Check(declared []Declared, values map[string]string) error {
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		if !slices.ContainsFunc(declared, func(d Declared) bool { return d.Name == name }) {
+			return unknownParameterError(name, declared)
+		}
+	}
+
+	return nil
+}
+
+func unknownParameterError(name string, declared []Declared) error {
+	if len(declared) == 0 {
+		return fmt.Errorf("unknown parameter '%s', the scenario does not declare any parameters", name)
+	}
+
+	names := make([]string, len(declared))
+	for i, d := range declared {
+		names[i] = d.Name
+	}
+	slices.Sort(names)
+
+	return fmt.Errorf("unknown parameter '%s', the scenario declares: %s", name, strings.Join(names, ", "))
+}
+
+// Get returns the value of the parameter name, and whether it has a value.
+func (p *Params) Get(name string) (string, bool) {
+	if stage := p.stage.Load(); stage != nil {
+		if value, ok := (*stage)[name]; ok {
+			return value, true
+		}
+	}
+
+	value, ok := (*p.values.Load())[name]
+	return value, ok
+}
+
+// SetStage sets values for the current stage, replacing the values of any previous stage.
+func (p *Params) SetStage(values map[string]string) {
+	if len(values) == 0 {
+		p.stage.Store(nil)
+		return
+	}
+
+	stage := maps.Clone(values)
+	p.stage.Store(&stage)
+}
+
+// Parse parses "name=value" pairs, as passed to the --param flag.
+func Parse(pairs []string) (map[string]string, error) {
+	values := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		name, value, found := strings.Cut(pair, "=")
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid parameter '%s', expected name=value", pair)
+		}
+		values[name] = value
+	}
+
+	return values, nil
+}
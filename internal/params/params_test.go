@@ -0,0 +1,77 @@
+package params_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/params"
+)
+
+func TestNew_ValuesOverrideDefaults(t *testing.T) {
+	t.Parallel()
+
+	p, err := params.New(
+		[]params.Declared{{Name: "region", Default: "eu"}, {Name: "size", Default: "10"}},
+		map[string]string{"size": "20"},
+	)
+	require.NoError(t, err)
+
+	region, ok := p.Get("region")
+	assert.True(t, ok)
+	assert.Equal(t, "eu", region)
+
+	size, ok := p.Get("size")
+	assert.True(t, ok)
+	assert.Equal(t, "20", size)
+
+	_, ok = p.Get("missing")
+	assert.False(t, ok)
+}
+
+func TestNew_FailsForUndeclaredParameters(t *testing.T) {
+	t.Parallel()
+
+	_, err := params.New([]params.Declared{{Name: "size"}, {Name: "region"}}, map[string]string{"colour": "red"})
+	require.EqualError(t, err, "unknown parameter 'colour', the scenario declares: region, size")
+
+	_, err = params.New(nil, map[string]string{"colour": "red"})
+	require.EqualError(t, err, "unknown parameter 'colour', the scenario does not declare any parameters")
+}
+
+func TestSetStage_OverridesValuesUntilCleared(t *testing.T) {
+	t.Parallel()
+
+	p, err := params.New([]params.Declared{{Name: "size", Default: "10"}}, nil)
+	require.NoError(t, err)
+
+	p.SetStage(map[string]string{"size": "20", "FOO": "bar"})
+
+	size, _ := p.Get("size")
+	assert.Equal(t, "20", size)
+	foo, ok := p.Get("FOO")
+	assert.True(t, ok)
+	assert.Equal(t, "bar", foo)
+
+	p.SetStage(nil)
+
+	size, _ = p.Get("size")
+	assert.Equal(t, "10", size)
+	_, ok = p.Get("FOO")
+	assert.False(t, ok)
+}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	values, err := params.Parse([]string{"a=1", "b=x=y", "c="})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"a": "1", "b": "x=y", "c": ""}, values)
+
+	_, err = params.Parse([]string{"a"})
+	require.EqualError(t, err, "invalid parameter 'a', expected name=value")
+
+	_, err = params.Parse([]string{"=1"})
+	require.EqualError(t, err, "invalid parameter '=1', expected name=value")
+}
@@ -0,0 +1,74 @@
+package progress
+
+import (
+	"sort"
+	"sync"
+)
+
+const (
+	// maxFailureReasons caps the number of distinct failure reasons tracked, so that failure
+	// messages containing unique values (ids, timestamps) can't grow memory unbounded.
+	maxFailureReasons = 100
+
+	// OtherFailureReason groups all failures once maxFailureReasons is reached.
+	OtherFailureReason = "(other failure reasons)"
+)
+
+// FailureReason is the number of failed iterations with the same reason.
+type FailureReason struct {
+	Reason string `json:"reason"`
+	// FirstIteration is the first iteration which failed with this reason
+	FirstIteration string `json:"first_iteration"`
+	Count          uint64 `json:"count"`
+}
+
+// Failures groups failed iterations by their failure reason.
+type Failures struct {
+	reasons map[string]*FailureReason
+	mu      sync.Mutex
+}
+
+func (f *Failures) Record(reason string, iteration string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.reasons == nil {
+		f.reasons = make(map[string]*FailureReason)
+	}
+
+	failure, ok := f.reasons[reason]
+	if !ok && len(f.reasons) >= maxFailureReasons {
+		reason = OtherFailureReason
+		failure, ok = f.reasons[reason]
+	}
+
+	if !ok {
+		failure = &FailureReason{Reason: reason, FirstIteration: iteration}
+		f.reasons[reason] = failure
+	}
+	failure.Count++
+}
+
+// Top returns up to n failure reasons, with the most frequent first.
+func (f *Failures) Top(n int) []FailureReason {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	top := make([]FailureReason, 0, len(f.reasons))
+	for _, failure := range f.reasons {
+		top = append(top, *failure)
+	}
+
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count == top[j].Count {
+			return top[i].Reason < top[j].Reason
+		}
+		return top[i].Count > top[j].Count
+	})
+
+	if len(top) > n {
+		top = top[:n]
+	}
+
+	return top
+}
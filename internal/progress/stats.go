@@ -11,6 +11,12 @@ type Stats struct {
 	successfulIterationDurations DurationStats
 	failedIterationDurations     DurationStats
 
+	failures Failures
+
+	tags Tags
+
+	waitDurations IterationDurations
+
 	droppedIterationCount atomic.Uint64
 }
 
@@ -26,6 +32,32 @@ func (s *Stats) Record(result metrics.ResultType, nanoseconds int64) {
 	}
 }
 
+// RecordWait records time virtual users waited between iterations, which is excluded from
+// iteration durations.
+func (s *Stats) RecordWait(nanoseconds int64) {
+	s.waitDurations.Add(nanoseconds)
+}
+
+// RecordFailure groups a failed iteration by its failure reason.
+func (s *Stats) RecordFailure(reason string, iteration string) {
+	s.failures.Record(reason, iteration)
+}
+
+// RecordTags groups an iteration by the values of its tags.
+func (s *Stats) RecordTags(tags map[string]string, result metrics.ResultType, nanoseconds int64) {
+	s.tags.Record(tags, result, nanoseconds)
+}
+
+// TagBreakdown returns the iterations of up to n values of each tag, with the most frequent first.
+func (s *Stats) TagBreakdown(n int) []TagBreakdown {
+	return s.tags.Breakdown(n)
+}
+
+// TopFailures returns up to n of the most frequent failure reasons.
+func (s *Stats) TopFailures(n int) []FailureReason {
+	return s.failures.Top(n)
+}
+
 func (s *Stats) Snapshot(period time.Duration) Snapshot {
 	recentSufessfull, lifetimeSuccessful := s.successfulIterationDurations.CollectLifetime()
 	_, lifetimeFailed := s.failedIterationDurations.CollectLifetime()
@@ -36,6 +68,7 @@ func (s *Stats) Snapshot(period time.Duration) Snapshot {
 		SuccessfulIterationDurationsForPeriod: recentSufessfull,
 		SuccessfulIterationDurations:          lifetimeSuccessful,
 		FailedIterationDurations:              lifetimeFailed,
+		WaitDurations:                         s.waitDurations.Snapshot(),
 	}
 }
 
@@ -47,6 +80,7 @@ func (s *Stats) Total() Snapshot {
 		DroppedIterationCount:        s.droppedIterationCount.Load(),
 		SuccessfulIterationDurations: lifetimeSuccessful,
 		FailedIterationDurations:     lifetimeFailed,
+		WaitDurations:                s.waitDurations.Snapshot(),
 	}
 }
 
@@ -55,6 +89,7 @@ type Snapshot struct {
 	SuccessfulIterationDurationsForPeriod IterationDurationsSnapshot
 	SuccessfulIterationDurations          IterationDurationsSnapshot
 	FailedIterationDurations              IterationDurationsSnapshot
+	WaitDurations                         IterationDurationsSnapshot
 	Period                                time.Duration
 }
 
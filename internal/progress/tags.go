@@ -0,0 +1,123 @@
+package progress
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+)
+
+const (
+	// maxTagValues caps the number of distinct values tracked for each tag, so that tags with
+	// unique values (ids, timestamps) can't grow memory unbounded.
+	maxTagValues = 100
+
+	// OtherTagValue groups all iterations once maxTagValues values of a tag are tracked.
+	OtherTagValue = "(other values)"
+)
+
+// TagBreakdown is the number of iterations tagged with each value of a tag.
+type TagBreakdown struct {
+	Key    string     `json:"key"`
+	Values []TagValue `json:"values"`
+}
+
+// TagValue is the number of iterations tagged with the same value of a tag.
+type TagValue struct {
+	Value      string        `json:"value"`
+	Average    time.Duration `json:"average"`
+	Successful uint64        `json:"successful"`
+	Failed     uint64        `json:"failed"`
+}
+
+func (v TagValue) count() uint64 {
+	return v.Successful + v.Failed
+}
+
+type tagValueStats struct {
+	successful uint64
+	failed     uint64
+	sum        int64
+}
+
+// Tags groups iterations by the values of their tags.
+type Tags struct {
+	keys map[string]map[string]*tagValueStats
+	mu   sync.Mutex
+}
+
+func (t *Tags) Record(tags map[string]string, result metrics.ResultType, nanoseconds int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.keys == nil {
+		t.keys = make(map[string]map[string]*tagValueStats)
+	}
+
+	for key, value := range tags {
+		values, ok := t.keys[key]
+		if !ok {
+			values = make(map[string]*tagValueStats)
+			t.keys[key] = values
+		}
+
+		stats, ok := values[value]
+		if !ok && len(values) >= maxTagValues {
+			value = OtherTagValue
+			stats, ok = values[value]
+		}
+
+		if !ok {
+			stats = &tagValueStats{}
+			values[value] = stats
+		}
+
+		if result == metrics.FailedResult {
+			stats.failed++
+		} else {
+			stats.successful++
+		}
+		stats.sum += nanoseconds
+	}
+}
+
+// Breakdown returns the iterations of up to n values of each tag, with the most frequent first.
+// Tags are sorted by key.
+func (t *Tags) Breakdown(n int) []TagBreakdown {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	breakdown := make([]TagBreakdown, 0, len(t.keys))
+	for key, values := range t.keys {
+		top := make([]TagValue, 0, len(values))
+		for value, stats := range values {
+			count := stats.successful + stats.failed
+			top = append(top, TagValue{
+				Value:      value,
+				Average:    time.Duration(stats.sum / int64(count)),
+				Successful: stats.successful,
+				Failed:     stats.failed,
+			})
+		}
+
+		sort.Slice(top, func(i, j int) bool {
+			if top[i].count() == top[j].count() {
+				return top[i].Value < top[j].Value
+			}
+			return top[i].count() > top[j].count()
+		})
+
+		if len(top) > n {
+			top = top[:n]
+		}
+
+		breakdown = append(breakdown, TagBreakdown{Key: key, Values: top})
+	}
+
+	sort.Slice(breakdown, func(i, j int) bool {
+		return breakdown[i].Key < breakdown[j].Key
+	})
+
+	return breakdown
+}
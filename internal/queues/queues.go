@@ -0,0 +1,162 @@
+// Package queues holds named, bounded, in-process queues, through which the scenarios of a process,
+// and the iterations of a scenario, hand each other items, such as the accounts created by one
+// scenario and used by another.
+package queues
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DefaultCapacity is the capacity of queues declared without one.
+const DefaultCapacity = 1000
+
+// EmptyPolicy is what a consumer does when it finds its queue empty.
+type EmptyPolicy string
+
+const (
+	// Block waits for an item to be put in the queue.
+	Block EmptyPolicy = "block"
+	// Skip returns without an item, so that the iteration can skip its work.
+	Skip EmptyPolicy = "skip"
+	// Fail fails the iteration.
+	Fail EmptyPolicy = "fail"
+)
+
+// Queue is a bounded queue of items. It is safe for concurrent use.
+type Queue struct {
+	items chan any
+	name  string
+}
+
+// Name returns the name of the queue.
+func (q *Queue) Name() string {
+	return q.name
+}
+
+// Capacity returns the number of items the queue holds before Put blocks.
+func (q *Queue) Capacity() int {
+	return cap(q.items)
+}
+
+// Len returns the number of items in the queue.
+func (q *Queue) Len() int {
+	return len(q.items)
+}
+
+// Put adds item to the queue, waiting while it is full until ctx is done.
+func (q *Queue) Put(ctx context.Context, item any) error {
+	select {
+	case q.items <- item:
+		return nil
+	default:
+	}
+
+	select {
+	case q.items <- item:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("queue %s is full: %w", q.name, ctx.Err())
+	}
+}
+
+// Take removes the oldest item from the queue, waiting while it is empty until ctx is done.
+func (q *Queue) Take(ctx context.Context) (any, error) {
+	select {
+	case item := <-q.items:
+		return item, nil
+	default:
+	}
+
+	select {
+	case item := <-q.items:
+		return item, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("queue %s is empty: %w", q.name, ctx.Err())
+	}
+}
+
+// TryTake removes the oldest item from the queue, and returns false if it is empty.
+func (q *Queue) TryTake() (any, bool) {
+	select {
+	case item := <-q.items:
+		return item, true
+	default:
+		return nil, false
+	}
+}
+
+// Binding is a queue declared by a scenario, with what the scenario does when it is empty.
+type Binding struct {
+	Queue     *Queue
+	WhenEmpty EmptyPolicy
+}
+
+// Registry holds the queues bound by the runs of a process, by name. A queue is removed, with the
+// items left in it, once every binding of it has been released. It is safe for concurrent use.
+type Registry struct {
+	queues map[string]*Queue
+	bound  map[string]int
+	mu     sync.Mutex
+}
+
+// NewRegistry returns a registry without queues.
+func NewRegistry() *Registry {
+	return &Registry{queues: make(map[string]*Queue), bound: make(map[string]int)}
+}
+
+// Bind returns the queue name, created with capacity if it doesn't exist yet, and bound with the
+// policy of a consumer. Scenarios declaring the same queue share it, and must declare the same
+// capacity, or 0 to use the capacity of the queue.
+func (r *Registry) Bind(name string, capacity int, whenEmpty EmptyPolicy) (Binding, error) {
+	if name == "" {
+		return Binding{}, errors.New("queue has no name")
+	}
+	if capacity < 0 {
+		return Binding{}, fmt.Errorf("queue %s: capacity %d can't be negative", name, capacity)
+	}
+	switch whenEmpty {
+	case "":
+		whenEmpty = Block
+	case Block, Skip, Fail:
+	default:
+		return Binding{}, fmt.Errorf("queue %s: unknown empty policy '%s', expected block, skip or fail", name, whenEmpty)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	q, ok := r.queues[name]
+	if !ok {
+		q = &Queue{name: name, items: make(chan any, orDefault(capacity))}
+		r.queues[name] = q
+	} else if capacity != 0 && capacity != q.Capacity() {
+		return Binding{}, fmt.Errorf("queue %s: capacity %d doesn't match the capacity %d it was declared with",
+			name, capacity, q.Capacity())
+	}
+	r.bound[name]++
+
+	return Binding{Queue: q, WhenEmpty: whenEmpty}, nil
+}
+
+// Release releases a binding of the queue name, returned by Bind, once the run using it has ended.
+func (r *Registry) Release(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.bound[name]--
+	if r.bound[name] <= 0 {
+		delete(r.bound, name)
+		delete(r.queues, name)
+	}
+}
+
+func orDefault(capacity int) int {
+	if capacity == 0 {
+		return DefaultCapacity
+	}
+
+	return capacity
+}
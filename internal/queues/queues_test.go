@@ -0,0 +1,126 @@
+package queues_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/queues"
+)
+
+func TestRegistry_SharesQueuesByName(t *testing.T) {
+	t.Parallel()
+
+	registry := queues.NewRegistry()
+
+	producer, err := registry.Bind("accounts", 2, "")
+	require.NoError(t, err)
+	consumer, err := registry.Bind("accounts", 0, queues.Skip)
+	require.NoError(t, err)
+
+	assert.Same(t, producer.Queue, consumer.Queue)
+	assert.Equal(t, queues.Block, producer.WhenEmpty)
+	assert.Equal(t, queues.Skip, consumer.WhenEmpty)
+	assert.Equal(t, 2, consumer.Queue.Capacity())
+
+	other, err := registry.Bind("payments", 0, queues.Fail)
+	require.NoError(t, err)
+	assert.Equal(t, queues.DefaultCapacity, other.Queue.Capacity())
+}
+
+func TestRegistry_RemovesQueuesOnceReleased(t *testing.T) {
+	t.Parallel()
+
+	registry := queues.NewRegistry()
+
+	first, err := registry.Bind("accounts", 2, "")
+	require.NoError(t, err)
+	second, err := registry.Bind("accounts", 0, "")
+	require.NoError(t, err)
+	require.NoError(t, first.Queue.Put(context.Background(), "acc-1"))
+
+	registry.Release("accounts")
+	still, err := registry.Bind("accounts", 0, "")
+	require.NoError(t, err)
+	assert.Same(t, second.Queue, still.Queue)
+
+	registry.Release("accounts")
+	registry.Release("accounts")
+	next, err := registry.Bind("accounts", 3, "")
+	require.NoError(t, err)
+	assert.NotSame(t, first.Queue, next.Queue)
+	assert.Zero(t, next.Queue.Len())
+}
+
+func TestRegistry_RejectsInvalidDeclarations(t *testing.T) {
+	t.Parallel()
+
+	registry := queues.NewRegistry()
+	_, err := registry.Bind("accounts", 2, queues.Block)
+	require.NoError(t, err)
+
+	_, err = registry.Bind("accounts", 3, queues.Block)
+	require.EqualError(t, err, "queue accounts: capacity 3 doesn't match the capacity 2 it was declared with")
+
+	_, err = registry.Bind("accounts", 2, "drop")
+	require.EqualError(t, err, "queue accounts: unknown empty policy 'drop', expected block, skip or fail")
+
+	_, err = registry.Bind("accounts", -1, queues.Block)
+	require.EqualError(t, err, "queue accounts: capacity -1 can't be negative")
+
+	_, err = registry.Bind("", 0, queues.Block)
+	require.EqualError(t, err, "queue has no name")
+}
+
+func TestQueue_TakesItemsInOrder(t *testing.T) {
+	t.Parallel()
+
+	binding, err := queues.NewRegistry().Bind("accounts", 2, queues.Block)
+	require.NoError(t, err)
+	queue := binding.Queue
+
+	require.NoError(t, queue.Put(context.Background(), "acc-1"))
+	require.NoError(t, queue.Put(context.Background(), "acc-2"))
+	assert.Equal(t, 2, queue.Len())
+
+	item, err := queue.Take(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "acc-1", item)
+
+	item, ok := queue.TryTake()
+	assert.True(t, ok)
+	assert.Equal(t, "acc-2", item)
+
+	_, ok = queue.TryTake()
+	assert.False(t, ok)
+}
+
+func TestQueue_WaitsUntilTheContextIsDone(t *testing.T) {
+	t.Parallel()
+
+	binding, err := queues.NewRegistry().Bind("accounts", 1, queues.Block)
+	require.NoError(t, err)
+	queue := binding.Queue
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = queue.Take(ctx)
+	require.EqualError(t, err, "queue accounts is empty: context deadline exceeded")
+
+	require.NoError(t, queue.Put(context.Background(), "acc-1"))
+	err = queue.Put(ctx, "acc-2")
+	require.EqualError(t, err, "queue accounts is full: context deadline exceeded")
+
+	// a waiting take receives the next item
+	taken := make(chan any)
+	go func() {
+		_, _ = queue.Take(context.Background())
+		item, _ := queue.Take(context.Background())
+		taken <- item
+	}()
+	require.NoError(t, queue.Put(context.Background(), "acc-3"))
+	assert.Equal(t, "acc-3", <-taken)
+}
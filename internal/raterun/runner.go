@@ -3,7 +3,10 @@ package raterun
 import (
 	"context"
 	"errors"
+	"sync"
 	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/clock"
 )
 
 // RunFunction is a function type that represents the function to be executed by the Runner.
@@ -19,18 +22,30 @@ type Schedule struct {
 	Frequency time.Duration
 }
 
+// ProgressSchedules returns the schedules of the progress of runs and simulations: every second for
+// the first minute, and less often as they go on.
+func ProgressSchedules() []Schedule {
+	return []Schedule{
+		{StartDelay: 0, Frequency: time.Second},
+		{StartDelay: time.Minute, Frequency: 10 * time.Second},
+		{StartDelay: 5 * time.Minute, Frequency: 30 * time.Second},
+		{StartDelay: 10 * time.Minute, Frequency: time.Minute},
+	}
+}
+
 // New creates a new runner that will execute fn as defined by the provided schedules
 //
-// Each Schedule in schedules defines how often fn should be executed at any given point in time.
-func New(fn RunFunction, schedules []Schedule) (*Runner, error) {
+// Each Schedule in schedules defines how often fn should be executed at any given point in time,
+// as told by clk.
+func New(fn RunFunction, schedules []Schedule, clk clock.Clock) (*Runner, error) {
 	if len(schedules) == 0 {
 		return nil, errors.New("empty schedules")
 	}
 
 	rateRunner := &Runner{
-		restart:     make(chan struct{}, 1),
+		clock:       clk,
 		runFunction: fn,
-		schedules:   newSchedules(schedules),
+		schedules:   newSchedules(schedules, clk.Now()),
 		stopped:     make(chan struct{}),
 	}
 
@@ -38,17 +53,28 @@ func New(fn RunFunction, schedules []Schedule) (*Runner, error) {
 }
 
 type Runner struct {
-	restart     chan struct{}
+	clock       clock.Clock
 	runFunction RunFunction
 
 	schedules *schedules
 	cancel    context.CancelFunc
 	stopped   chan struct{}
+
+	// interruptWait cancels the wait for the next execution, once the runner is restarted
+	interruptWait context.CancelFunc
+	restarted     bool
+	mu            sync.Mutex
 }
 
 // Restart will stop the current schedule and start from the first one defined.
 func (r *Runner) Restart() {
-	r.restart <- struct{}{}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.restarted = true
+	if r.interruptWait != nil {
+		r.interruptWait()
+	}
 }
 
 // Start starts the execution of the runner.
@@ -61,21 +87,45 @@ func (r *Runner) Start(ctx context.Context) {
 	schedulesCtx, schedulesCtxCancel := context.WithCancel(ctx)
 	r.cancel = schedulesCtxCancel
 
-	go func() {
+	r.clock.Go(func() {
 		for {
-			select {
-			case <-r.restart:
-				r.schedules.startFirst()
-			case <-r.schedules.timeUntilNextSchedule():
-				r.schedules.startNext()
-			case <-r.schedules.currentScheduleTicker():
-				r.runFunction(r.schedules.currentFrequency())
-			case <-schedulesCtx.Done():
-				r.schedules.stop()
+			waitCtx, interrupt := r.nextWait(schedulesCtx)
+			due := r.clock.Sleep(waitCtx, r.schedules.next().Sub(r.clock.Now()))
+			interrupt()
+
+			if schedulesCtx.Err() != nil {
 				return
 			}
+			if !due {
+				continue
+			}
+
+			now := r.clock.Now()
+			if r.schedules.nextScheduleDue(now) {
+				r.schedules.startNext(now)
+				continue
+			}
+			r.schedules.tick(now)
+			r.runFunction(r.schedules.currentFrequency())
 		}
-	}()
+	})
+}
+
+// nextWait starts from the first schedule if the runner was restarted, and returns the context of
+// the wait for the next execution, which Restart interrupts.
+func (r *Runner) nextWait(ctx context.Context) (context.Context, context.CancelFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.restarted {
+		r.restarted = false
+		r.schedules.startFirst(r.clock.Now())
+	}
+
+	waitCtx, interrupt := r.clock.WithCancel(ctx)
+	r.interruptWait = interrupt
+
+	return waitCtx, interrupt
 }
 
 // Stop stopps the runner and will block until the runner is stopped
@@ -85,60 +135,68 @@ func (r *Runner) Stop() {
 }
 
 type schedules struct {
-	ticker               *time.Ticker
-	nextScheduleTimer    *time.Timer
+	// nextTick is when the function is executed next, or zero before the first schedule starts
+	nextTick time.Time
+	// nextStart is when the next schedule starts, or zero once the last one started
+	nextStart            time.Time
 	list                 []Schedule
 	currentScheduleIndex int
 }
 
-func newSchedules(list []Schedule) *schedules {
+func newSchedules(list []Schedule, now time.Time) *schedules {
 	return &schedules{
 		list:                 list,
 		currentScheduleIndex: -1,
-		ticker:               time.NewTicker(time.Hour),
-		nextScheduleTimer:    time.NewTimer(list[0].StartDelay),
+		nextStart:            now.Add(list[0].StartDelay),
 	}
 }
 
-func (s *schedules) start(index int) {
+func (s *schedules) start(index int, now time.Time) {
 	if index >= len(s.list) {
 		return
 	}
 
-	s.ticker.Stop()
 	s.currentScheduleIndex = index
-	s.ticker = time.NewTicker(s.list[s.currentScheduleIndex].Frequency)
+	s.nextTick = now.Add(s.list[s.currentScheduleIndex].Frequency)
 
 	nextIndex := s.currentScheduleIndex + 1
-	s.nextScheduleTimer.Stop()
+	s.nextStart = time.Time{}
 	if nextIndex >= len(s.list) {
 		return
 	}
 
-	s.nextScheduleTimer = time.NewTimer(s.list[nextIndex].StartDelay)
+	s.nextStart = now.Add(s.list[nextIndex].StartDelay)
 }
 
-func (s *schedules) startFirst() {
-	s.start(0)
+func (s *schedules) startFirst(now time.Time) {
+	s.start(0, now)
 }
 
-func (s *schedules) startNext() {
-	s.start(s.currentScheduleIndex + 1)
+func (s *schedules) startNext(now time.Time) {
+	s.start(s.currentScheduleIndex+1, now)
 }
 
 func (s *schedules) currentFrequency() time.Duration {
 	return s.list[s.currentScheduleIndex].Frequency
 }
 
-func (s *schedules) stop() {
-	s.ticker.Stop()
-	s.nextScheduleTimer.Stop()
+// next returns when the next schedule starts or the function is executed, whichever is first.
+func (s *schedules) next() time.Time {
+	if s.nextTick.IsZero() || (!s.nextStart.IsZero() && !s.nextStart.After(s.nextTick)) {
+		return s.nextStart
+	}
+
+	return s.nextTick
 }
 
-func (s *schedules) timeUntilNextSchedule() <-chan time.Time {
-	return s.nextScheduleTimer.C
+func (s *schedules) nextScheduleDue(now time.Time) bool {
+	return !s.nextStart.IsZero() && !s.nextStart.After(now)
 }
 
-func (s *schedules) currentScheduleTicker() <-chan time.Time {
-	return s.ticker.C
+// tick schedules the next execution of the function, skipping those which are already late, as
+// tickers do.
+func (s *schedules) tick(now time.Time) {
+	for !s.nextTick.After(now) {
+		s.nextTick = s.nextTick.Add(s.currentFrequency())
+	}
 }
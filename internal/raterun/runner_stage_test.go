@@ -10,6 +10,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 
+	"github.com/form3tech-oss/f1/v2/internal/clock"
 	"github.com/form3tech-oss/f1/v2/internal/raterun"
 )
 
@@ -46,7 +47,7 @@ func (s *RatedRunnerStage) a_rate_runner() *RatedRunnerStage {
 		s.m.Lock()
 		defer s.m.Unlock()
 		s.funcRuns[rate]++
-	}, s.rates)
+	}, s.rates, clock.Real{})
 
 	require.NoError(s.t, err)
 
@@ -7,14 +7,26 @@ import (
 	"sync"
 	"time"
 
+	"github.com/form3tech-oss/f1/v2/internal/callbacks"
+	"github.com/form3tech-oss/f1/v2/internal/data"
 	"github.com/form3tech-oss/f1/v2/internal/options"
 	"github.com/form3tech-oss/f1/v2/internal/progress"
 	"github.com/form3tech-oss/f1/v2/internal/run/views"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/reporter"
+)
+
+const (
+	// maxTopFailures is the number of failure reasons shown in the summary
+	maxTopFailures = 5
+	// maxTagValues is the number of values of each tag shown in the summary
+	maxTagValues = 10
 )
 
 type Result struct {
 	startTime     time.Time
 	progressStats *progress.Stats
+	feeder        *data.Feeder
+	callbacks     *callbacks.Listener
 	views         *views.Views
 	LogFilePath   string
 	errors        []error
@@ -96,14 +108,43 @@ func (r *Result) Summary() *views.ViewContext[views.ResultData] {
 		SuccessfulIterationDurations: r.snapshot.SuccessfulIterationDurations,
 		Duration:                     r.duration(),
 		FailedIterationDurations:     r.snapshot.FailedIterationDurations,
+		WaitDurations:                r.snapshot.WaitDurations,
+		TopFailures:                  r.progressStats.TopFailures(maxTopFailures),
+		Tags:                         r.progressStats.TagBreakdown(maxTagValues),
 		Error:                        r.Error(),
 		Failed:                       r.Failed(),
 		LogFilePath:                  r.LogFilePath,
 		Iterations:                   r.snapshot.Iterations(),
 		IterationsStarted:            r.snapshot.IterationsStarted(),
+		DataUsage:                    r.dataUsage(),
+		Callbacks:                    r.callbackCounts(),
+		Seed:                         r.runOptions.Seed,
 	})
 }
 
+// dataUsage returns how much of the data file was used, or a zero Usage without a data file.
+func (r *Result) dataUsage() data.Usage {
+	if r.feeder == nil {
+		return data.Usage{}
+	}
+
+	return r.feeder.Usage()
+}
+
+// callbackCounts returns the callbacks received, or zero Counts without callbacks.
+func (r *Result) callbackCounts() callbacks.Counts {
+	if r.callbacks == nil {
+		return callbacks.Counts{}
+	}
+
+	return r.callbacks.Counts()
+}
+
+// TopFailures returns up to n of the most frequent failure reasons.
+func (r *Result) TopFailures(n int) []progress.FailureReason {
+	return r.progressStats.TopFailures(n)
+}
+
 func (r *Result) Failed() bool {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -131,6 +172,67 @@ func (r *Result) Progress() *views.ViewContext[views.ProgressData] {
 	})
 }
 
+// ProgressEvent returns the latest progress snapshot, for reporters.
+func (r *Result) ProgressEvent() reporter.Progress {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return reporter.Progress{
+		Elapsed:              r.duration(),
+		Period:               r.snapshot.Period,
+		PeriodAverage:        r.snapshot.SuccessfulIterationDurationsForPeriod.Average,
+		PeriodIterations:     r.snapshot.SuccessfulIterationDurationsForPeriod.Count,
+		SuccessfulIterations: r.snapshot.SuccessfulIterationDurations.Count,
+		FailedIterations:     r.snapshot.FailedIterationDurations.Count,
+		DroppedIterations:    r.snapshot.DroppedIterationCount,
+	}
+}
+
+// Report returns the outcome of the run, for reporters and the Go API.
+func (r *Result) Report() reporter.Result {
+	snapshot := r.Snapshot()
+
+	failures := r.TopFailures(maxTopFailures)
+	topFailures := make([]reporter.FailureReason, len(failures))
+	for i, failure := range failures {
+		topFailures[i] = reporter.FailureReason{
+			Reason:         failure.Reason,
+			FirstIteration: failure.FirstIteration,
+			Count:          failure.Count,
+		}
+	}
+
+	r.mu.RLock()
+	testDuration := r.TestDuration
+	r.mu.RUnlock()
+
+	return reporter.Result{
+		Error:       r.Error(),
+		TopFailures: topFailures,
+		SuccessfulDurations: reporter.IterationDurations{
+			Average: snapshot.SuccessfulIterationDurations.Average,
+			Min:     snapshot.SuccessfulIterationDurations.Min,
+			Max:     snapshot.SuccessfulIterationDurations.Max,
+		},
+		FailedDurations: reporter.IterationDurations{
+			Average: snapshot.FailedIterationDurations.Average,
+			Min:     snapshot.FailedIterationDurations.Min,
+			Max:     snapshot.FailedIterationDurations.Max,
+		},
+		WaitDurations: reporter.IterationDurations{
+			Average: snapshot.WaitDurations.Average,
+			Min:     snapshot.WaitDurations.Min,
+			Max:     snapshot.WaitDurations.Max,
+		},
+		Duration:             testDuration,
+		SuccessfulIterations: snapshot.SuccessfulIterationDurations.Count,
+		FailedIterations:     snapshot.FailedIterationDurations.Count,
+		DroppedIterations:    snapshot.DroppedIterationCount,
+		Seed:                 r.runOptions.Seed,
+		Passed:               !r.Failed(),
+	}
+}
+
 func (r *Result) HasDroppedIterations() bool {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -196,6 +298,14 @@ func (r *Result) MaxIterationsReached() *views.ViewContext[views.MaxIterationsRe
 	})
 }
 
+// Elapsed returns the time since the test was started.
+func (r *Result) Elapsed() time.Duration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.duration()
+}
+
 func (r *Result) duration() time.Duration {
 	if r.startTime.IsZero() {
 		return 0
@@ -3,24 +3,35 @@ package run
 import (
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/form3tech-oss/f1/v2/internal/envsettings"
+	"github.com/form3tech-oss/f1/v2/internal/execscenario"
+	"github.com/form3tech-oss/f1/v2/internal/harscenario"
+	"github.com/form3tech-oss/f1/v2/internal/httpscenario"
 	"github.com/form3tech-oss/f1/v2/internal/metrics"
 	"github.com/form3tech-oss/f1/v2/internal/options"
+	"github.com/form3tech-oss/f1/v2/internal/params"
+	"github.com/form3tech-oss/f1/v2/internal/queues"
 	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
 	"github.com/form3tech-oss/f1/v2/internal/triggerflags"
 	"github.com/form3tech-oss/f1/v2/internal/ui"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/reporter"
 	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
 )
 
 func Cmd(
 	s *scenarios.Scenarios,
+	queueRegistry *queues.Registry,
 	builders []api.Builder,
 	settings envsettings.Settings,
-	metricsInstance *metrics.Metrics,
+	newMetrics metrics.Factory,
+	runReporter reporter.Reporter,
 	output *ui.Output,
 ) *cobra.Command {
 	runCmd := &cobra.Command{
@@ -32,16 +43,43 @@ func Cmd(
 		triggerCmd := &cobra.Command{
 			Use:   t.Name,
 			Short: t.Description,
-			RunE:  runCmdExecute(s, t, settings, metricsInstance, output),
-			Args:  cobra.MatchAll(cobra.ExactArgs(1)),
+			Long:  t.Description + paramsHelp(s),
+			RunE:  runCmdExecute(s, queueRegistry, t, settings, newMetrics, runReporter, output),
+			Args:  scenarioArgs,
 		}
 
 		triggerCmd.Flags().BoolP(triggerflags.FlagVerbose, "v", false, "enables log output to stdout")
-		triggerCmd.Flags().Bool(triggerflags.FlagVerboseFail, false, "DEPRECATED: log output to stdout on failure")
+		triggerCmd.Flags().Bool(triggerflags.FlagVerboseFail, false,
+			"log output to stdout only for failed iterations")
+		triggerCmd.Flags().Uint64(triggerflags.FlagMaxFailedIterationLogs, 10,
+			"--max-failed-iteration-logs 10 (with --verbose-fail, log at most 10 failed iterations, 0 is unlimited)")
+		triggerCmd.Flags().Bool(triggerflags.FlagTUI, false,
+			"show a full-screen interactive dashboard, when running in a terminal")
+		triggerCmd.Flags().StringArray(triggerflags.FlagParam, nil,
+			"--param name=value (set a scenario parameter, can be repeated; see 'scenarios ls' for the declared parameters)")
+		// registering only fails if the flag is not defined
+		_ = triggerCmd.RegisterFlagCompletionFunc(triggerflags.FlagParam, completeParams(s))
+		triggerCmd.Flags().String(triggerflags.FlagEventsFile, "",
+			"--events-file events.ndjson (write a JSON record for each iteration, which can be read with the analyze command)")
+		triggerflags.SeedFlag(triggerCmd.Flags())
 
 		if !t.IgnoreCommonFlags {
 			triggerCmd.ValidArgs = s.GetScenarioNames()
 
+			triggerCmd.Flags().String(triggerflags.FlagExec, "",
+				"--exec \"./probe.sh {{.Iteration}}\" (run a command in each iteration, instead of a scenario)")
+			triggerCmd.Flags().String(triggerflags.FlagScenarioFile, "",
+				"--scenario-file flow.yaml (send the HTTP requests described in the file, instead of a scenario)")
+			triggerCmd.Flags().String(triggerflags.FlagHAR, "",
+				"--har session.har (replay the requests of a HAR file in each iteration, instead of a scenario)")
+			triggerCmd.Flags().Float64(triggerflags.FlagHARThinkTimeScale, 1,
+				"--har-think-time-scale 0.5 (halve the pauses recorded between the requests of --har, 0 for none)")
+			triggerCmd.Flags().StringArray(triggerflags.FlagHARHost, nil,
+				"--har-host www.example.com=localhost:8080 (send the requests of --har to another host, can be repeated)")
+			triggerCmd.Flags().StringArray(triggerflags.FlagHARInclude, nil,
+				"--har-include api.example.com (replay only the requests of --har to a domain or path, can be repeated)")
+			triggerCmd.Flags().StringArray(triggerflags.FlagHARExclude, nil,
+				"--har-exclude /analytics (don't replay the requests of --har to a domain or path, can be repeated)")
 			triggerCmd.Flags().Bool(triggerflags.FlagIgnoreDropped, false, "dropped requests will not fail the run")
 			triggerCmd.Flags().DurationP(triggerflags.FlagMaxDuration, "d", time.Second,
 				"--max-duration 1s (stop after 1 second)")
@@ -64,17 +102,97 @@ func Cmd(
 	return runCmd
 }
 
+// scenarioFlags give a scenario to run instead of the scenario argument.
+var scenarioFlags = []string{triggerflags.FlagExec, triggerflags.FlagScenarioFile, triggerflags.FlagHAR}
+
+// setScenarioFlags returns the scenarioFlags which are set.
+func setScenarioFlags(cmd *cobra.Command) []string {
+	var set []string
+	for _, name := range scenarioFlags {
+		if flag := cmd.Flags().Lookup(name); flag != nil && flag.Value.String() != "" {
+			set = append(set, name)
+		}
+	}
+
+	return set
+}
+
+// scenarioArgs requires the scenario argument, unless the scenario is given with one of the
+// scenarioFlags instead.
+func scenarioArgs(cmd *cobra.Command, args []string) error {
+	if len(setScenarioFlags(cmd)) > 0 {
+		return cobra.NoArgs(cmd, args)
+	}
+
+	return cobra.ExactArgs(1)(cmd, args)
+}
+
+// paramsHelp lists the parameters declared by the scenarios, with their defaults, for the help of
+// the trigger commands.
+func paramsHelp(s *scenarios.Scenarios) string {
+	var help strings.Builder
+	names := s.GetScenarioNames()
+	slices.Sort(names)
+	for _, name := range names {
+		parameters := s.GetScenario(name).Parameters
+		if len(parameters) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&help, "\n  %s\n", name)
+		for _, parameter := range parameters {
+			fmt.Fprintf(&help, "    --param %s=%q\t%s\n", parameter.Name, parameter.Default, parameter.Description)
+		}
+	}
+
+	if help.Len() == 0 {
+		return ""
+	}
+
+	return "\n\nScenario parameters, set with --param name=value:\n" + help.String()
+}
+
+// completeParams completes the names of the parameters declared by the scenario.
+func completeParams(s *scenarios.Scenarios) cobra.CompletionFunc {
+	return func(_ *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if len(args) == 0 || strings.Contains(toComplete, "=") {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		scenario := s.GetScenario(args[0])
+		if scenario == nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+
+		completions := make([]cobra.Completion, 0, len(scenario.Parameters))
+		for _, parameter := range scenario.Parameters {
+			completions = append(completions,
+				cobra.CompletionWithDesc(parameter.Name+"=", parameter.Description))
+		}
+
+		return completions, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
 func runCmdExecute(
 	s *scenarios.Scenarios,
+	queueRegistry *queues.Registry,
 	t api.Builder,
 	settings envsettings.Settings,
-	metricsInstance *metrics.Metrics,
+	newMetrics metrics.Factory,
+	runReporter reporter.Reporter,
 	output *ui.Output,
 ) func(cmd *cobra.Command, args []string) error {
 	return func(cmd *cobra.Command, args []string) error {
 		cmd.SilenceUsage = true
 		cmd.SilenceErrors = true
 
+		// the seed is resolved first, so that the trigger mode makes its random choices with it
+		seed, err := triggerflags.ResolveSeed(cmd.Flags())
+		if err != nil {
+			return fmt.Errorf("resolving seed: %w", err)
+		}
+
 		trig, err := t.New(cmd.Flags())
 		if err != nil {
 			return fmt.Errorf("creating trigger command: %w", err)
@@ -98,7 +216,9 @@ func runCmdExecute(
 			ignoreDropped = trig.Options.IgnoreDropped
 			waitForCompletionTimeout = trig.Options.WaitForCompletionTimeout
 		} else {
-			scenarioName = args[0]
+			if len(args) > 0 {
+				scenarioName = args[0]
+			}
 			duration, err = cmd.Flags().GetDuration(triggerflags.FlagMaxDuration)
 			if err != nil {
 				return fmt.Errorf("getting flag: %w", err)
@@ -142,8 +262,50 @@ func runCmdExecute(
 		if err != nil {
 			return fmt.Errorf("getting flag: %w", err)
 		}
-		if verboseFail {
-			output.Display(ui.WarningMessage{Message: "--verbose-fail option has been removed"})
+		maxFailedIterationLogs, err := cmd.Flags().GetUint64(triggerflags.FlagMaxFailedIterationLogs)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+
+		tui, err := cmd.Flags().GetBool(triggerflags.FlagTUI)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		// failed iterations are logged to the log file when the dashboard is displayed
+		if tui && verbose && output.Interactive {
+			output.Display(ui.WarningMessage{Message: "--verbose has no effect when --tui is enabled"})
+			verbose = false
+		}
+		consoleLogging := verbose || (verboseFail && !(tui && output.Interactive))
+
+		paramPairs, err := cmd.Flags().GetStringArray(triggerflags.FlagParam)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		scenarioParams, err := params.Parse(paramPairs)
+		if err != nil {
+			return fmt.Errorf("parsing --%s: %w", triggerflags.FlagParam, err)
+		}
+
+		// the scenario given with a flag is only added to the scenarios of this run
+		runScenarios := s
+		if !t.IgnoreCommonFlags {
+			scenario, ok, err := flagScenario(cmd, scenarioParams)
+			if err != nil {
+				return err
+			}
+			if ok {
+				if s.GetScenario(scenario.Name) != nil {
+					return fmt.Errorf("scenario '%s' can't be added, as a scenario has the same name", scenario.Name)
+				}
+				runScenarios = s.With(scenario)
+				scenarioName = scenario.Name
+			}
+		}
+
+		eventsFile, err := cmd.Flags().GetString(triggerflags.FlagEventsFile)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
 		}
 
 		if settings.Fluentd.Present() {
@@ -160,13 +322,21 @@ func runCmdExecute(
 			Scenario:                 scenarioName,
 			MaxDuration:              duration,
 			Concurrency:              concurrency,
-			Verbose:                  verbose,
+			Verbose:                  consoleLogging,
+			VerboseFail:              verboseFail,
+			MaxFailedIterationLogs:   maxFailedIterationLogs,
+			EventsFile:               eventsFile,
+			Params:                   scenarioParams,
+			Reporter:                 runReporter,
+			TUI:                      tui,
 			MaxIterations:            maxIterations,
 			MaxFailures:              maxFailures,
 			MaxFailuresRate:          maxFailuresRate,
 			IgnoreDropped:            ignoreDropped,
 			WaitForCompletionTimeout: waitForCompletionTimeout,
-		}, s, trig, settings, metricsInstance, output)
+			Seed:                     seed,
+			Queues:                   queueRegistry,
+		}, runScenarios, trig, settings, newMetrics, output)
 		if err != nil {
 			return fmt.Errorf("new run: %w", err)
 		}
@@ -184,3 +354,66 @@ func runCmdExecute(
 		return nil
 	}
 }
+
+// flagScenario returns the scenario given with one of the scenarioFlags, and whether one was given.
+// The command run with --exec can use the parameters set with --param.
+func flagScenario(cmd *cobra.Command, scenarioParams map[string]string) (*scenarios.Scenario, bool, error) {
+	set := setScenarioFlags(cmd)
+	if len(set) == 0 {
+		return nil, false, nil
+	}
+	if len(set) > 1 {
+		return nil, false, fmt.Errorf("--%s and --%s can't be used together", set[0], set[1])
+	}
+
+	value := cmd.Flags().Lookup(set[0]).Value.String()
+
+	var scenario *scenarios.Scenario
+	var err error
+	switch set[0] {
+	case triggerflags.FlagExec:
+		scenario, err = execscenario.New(value, slices.Sorted(maps.Keys(scenarioParams)))
+	case triggerflags.FlagScenarioFile:
+		scenario, err = httpscenario.Load(value)
+	default:
+		scenario, err = harScenario(cmd, value)
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("--%s: %w", set[0], err)
+	}
+
+	return scenario, true, nil
+}
+
+// harScenario returns the scenario replaying the HAR file at path, with the --har-* options.
+func harScenario(cmd *cobra.Command, path string) (*scenarios.Scenario, error) {
+	var options harscenario.Options
+	var err error
+	options.ThinkTimeScale, err = cmd.Flags().GetFloat64(triggerflags.FlagHARThinkTimeScale)
+	if err != nil {
+		return nil, fmt.Errorf("getting flag: %w", err)
+	}
+	hostPairs, err := cmd.Flags().GetStringArray(triggerflags.FlagHARHost)
+	if err != nil {
+		return nil, fmt.Errorf("getting flag: %w", err)
+	}
+	options.Hosts, err = harscenario.ParseHosts(hostPairs)
+	if err != nil {
+		return nil, fmt.Errorf("parsing --%s: %w", triggerflags.FlagHARHost, err)
+	}
+	options.Include, err = cmd.Flags().GetStringArray(triggerflags.FlagHARInclude)
+	if err != nil {
+		return nil, fmt.Errorf("getting flag: %w", err)
+	}
+	options.Exclude, err = cmd.Flags().GetStringArray(triggerflags.FlagHARExclude)
+	if err != nil {
+		return nil, fmt.Errorf("getting flag: %w", err)
+	}
+
+	scenario, err := harscenario.Load(path, options)
+	if err != nil {
+		return nil, fmt.Errorf("loading har scenario: %w", err)
+	}
+
+	return scenario, nil
+}
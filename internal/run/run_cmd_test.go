@@ -3,6 +3,8 @@ package run_test
 import (
 	"testing"
 	"time"
+
+	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
 )
 
 const Any = int64(-1)
@@ -515,7 +517,8 @@ func TestRunScenarioThatPanics(t *testing.T) {
 	then.the_command_should_fail().and().
 		setup_teardown_is_called().and().
 		iteration_teardown_is_called_n_times(1).and().
-		metrics_are_pushed_to_prometheus()
+		metrics_are_pushed_to_prometheus().and().
+		the_top_failure_reason_should_be("panic: test panic in scenario iteration")
 }
 
 func TestRunScenarioThatFailsAnAssertion(t *testing.T) {
@@ -533,7 +536,8 @@ func TestRunScenarioThatFailsAnAssertion(t *testing.T) {
 	then.the_command_should_fail().and().
 		setup_teardown_is_called().and().
 		iteration_teardown_is_called_n_times(1).and().
-		metrics_are_pushed_to_prometheus()
+		metrics_are_pushed_to_prometheus().and().
+		the_top_failure_reason_should_be("fail")
 }
 
 func TestRunScenarioThatFailsOccasionally(t *testing.T) {
@@ -556,6 +560,456 @@ func TestRunScenarioThatFailsOccasionally(t *testing.T) {
 		iteration_teardown_is_called_n_times(100)
 }
 
+func TestRunWithVerboseFailOnlyLogsFailedIterations(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_test_scenario_that_logs_and_fails_intermittently().and().
+		verbose_fail_flag_is(true).and().
+		a_max_failed_iteration_logs_of(3).and().
+		a_rate_of("20/1s").and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_distribution_type("none")
+
+	when.the_run_command_is_executed()
+
+	then.the_results_should_show_n_failures(10).and().
+		the_stdout_output_should_contain_n_times("running iteration", 3).and().
+		the_stdout_output_should_contain_n_times("Error Trace:", 3).and().
+		the_stdout_output_should_contain_n_times("failed iteration log limit reached", 1)
+}
+
+func TestRunWritesIterationEvents(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_test_scenario_that_fails_intermittently().and().
+		an_events_file().and().
+		a_rate_of("20/1s").and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_distribution_type("none")
+
+	when.the_run_command_is_executed()
+
+	then.the_results_should_show_n_failures(10).and().
+		the_events_file_should_contain_n_events_with_result(10, "success").and().
+		the_events_file_should_contain_n_events_with_result(10, "fail")
+}
+
+func TestRunWithScenarioParams(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_scenario_that_records_the_param("greeting",
+			scenarios.ScenarioParameter{Name: "greeting", Description: "a greeting", Default: "hello"},
+			scenarios.ScenarioParameter{Name: "name", Description: "a name", Default: "world"},
+		).and().
+		a_param_of("greeting", "hi").and().
+		a_rate_of("10/1s").and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_distribution_type("none")
+
+	when.the_run_command_is_executed()
+
+	then.the_param_value_should_be_seen_n_times("hi", 10)
+}
+
+func TestTriggerHelpListsTheDeclaredScenarioParams(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_scenario_that_records_the_param("greeting",
+			scenarios.ScenarioParameter{Name: "greeting", Description: "a greeting", Default: "hello"},
+			scenarios.ScenarioParameter{Name: "name", Description: "a name", Default: "world"},
+		)
+
+	when.the_help_of_the_trigger_command_is_shown()
+
+	then.expect_the_stdout_output_to_include([]string{
+		"Scenario parameters, set with --param name=value:",
+		"  scenario_that_records_the_param_greeting",
+		"    --param greeting=\"hello\"\ta greeting",
+		"    --param name=\"world\"\ta name",
+	})
+}
+
+func TestRunWithUndeclaredScenarioParam(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_scenario_that_records_the_param("greeting",
+			scenarios.ScenarioParameter{Name: "greeting", Description: "a greeting", Default: "hello"},
+		).and().
+		a_param_of("unknown", "value").and().
+		a_rate_of("10/1s").and().
+		a_duration_of(500 * time.Millisecond)
+
+	when.the_run_is_created()
+
+	then.creating_the_run_should_fail_with("unknown parameter 'unknown', the scenario declares: greeting")
+}
+
+func TestRunWithFileStageParams(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_scenario_that_records_the_param("FOO",
+			scenarios.ScenarioParameter{Name: "FOO"},
+			scenarios.ScenarioParameter{Name: "BAR"},
+		).and().
+		a_trigger_type_of(File).and().
+		a_config_file_location_of("../testdata/config-file-params.yaml").and().
+		a_duration_of(5 * time.Second).and().
+		a_concurrency_of(50)
+
+	when.the_run_command_is_executed()
+
+	then.the_param_value_should_be_seen_n_times("1", 50).and().
+		the_param_value_should_be_seen("")
+}
+
+func TestRunWithUndeclaredFileStageParam(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_trigger_type_of(File).and().
+		a_config_file_location_of("../testdata/config-file-params.yaml").and().
+		a_scenario_that_records_the_param("FOO", scenarios.ScenarioParameter{Name: "FOO"})
+
+	when.the_run_is_created()
+
+	then.creating_the_run_should_fail_with(
+		"scenario parameters of stage 1: unknown parameter 'BAR', the scenario declares: FOO")
+}
+
+func TestRunWithVirtualUserSetup(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_trigger_type_of(Users).and().
+		a_concurrency_of(3).and().
+		an_iteration_limit_of(30).and().
+		a_duration_of(5 * time.Second).and().
+		a_scenario_with_virtual_user_setup()
+
+	when.the_run_command_is_executed()
+
+	then.
+		n_virtual_users_should_be_set_up_and_torn_down(3).and().
+		iterations_should_see_the_state_of_their_virtual_user()
+}
+
+func TestRunWithFailingVirtualUserSetup(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_trigger_type_of(Users).and().
+		a_concurrency_of(3).and().
+		an_iteration_limit_of(6).and().
+		a_duration_of(5 * time.Second).and().
+		a_scenario_with_failing_virtual_user_setup()
+
+	when.the_run_command_is_executed()
+
+	then.
+		the_command_should_fail().and().
+		the_results_should_show_n_failures(6).and().
+		the_top_failure_reason_should_be("virtual user setup failed: no connection").and().
+		the_scenario_should_not_have_run()
+}
+
+func TestRunWithUniqueDataStopsWhenExhausted(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_trigger_type_of(Users).and().
+		a_concurrency_of(2).and().
+		a_duration_of(5 * time.Second).and().
+		a_scenario_that_records_the_data(scenarios.DataFile{
+			Path:              "../testdata/accounts.csv",
+			Mode:              scenarios.DataUnique,
+			StopWhenExhausted: true,
+		})
+
+	when.the_run_command_is_executed()
+
+	then.
+		the_command_finished_successfully().and().
+		the_results_should_show_n_successful_iterations(4).and().
+		each_data_row_should_be_used_once("acc-1", "acc-2", "acc-3", "acc-4").and().
+		the_stdout_output_should_contain_n_times("Limiting the run to 4 iterations", 1).and().
+		the_stdout_output_should_contain_n_times("data.path=../testdata/accounts.csv data.rows=4 data.used=4", 1)
+}
+
+func TestRunWithUniqueDataFailsWhenExhausted(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_trigger_type_of(Users).and().
+		a_concurrency_of(2).and().
+		an_iteration_limit_of(6).and().
+		a_duration_of(5 * time.Second).and().
+		a_scenario_that_records_the_data(scenarios.DataFile{
+			Path: "../testdata/accounts.jsonl",
+			Mode: scenarios.DataUnique,
+		})
+
+	when.the_run_command_is_executed()
+
+	then.
+		the_command_should_fail().and().
+		the_results_should_show_n_successful_iterations(3).and().
+		the_results_should_show_n_failures(3).and().
+		the_top_failure_reason_should_be("all rows have been used: 3 rows of ../testdata/accounts.jsonl")
+}
+
+func TestRunWithDataPartitionedPerVirtualUser(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_trigger_type_of(Users).and().
+		a_concurrency_of(2).and().
+		an_iteration_limit_of(20).and().
+		a_duration_of(5 * time.Second).and().
+		a_scenario_that_records_the_data(scenarios.DataFile{
+			Path: "../testdata/accounts.csv",
+			Mode: scenarios.DataPerVU,
+		})
+
+	when.the_run_command_is_executed()
+
+	then.
+		the_command_finished_successfully().and().
+		the_virtual_user_should_only_use_rows(0, "acc-1", "acc-3").and().
+		the_virtual_user_should_only_use_rows(1, "acc-2", "acc-4")
+}
+
+func TestRunWithCallbacks(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_trigger_type_of(Users).and().
+		a_concurrency_of(2).and().
+		an_iteration_limit_of(4).and().
+		a_duration_of(5 * time.Second).and().
+		a_scenario_where_each_iteration_awaits_its_callback()
+
+	when.the_run_command_is_executed()
+
+	then.
+		the_command_finished_successfully().and().
+		the_results_should_show_n_successful_iterations(4).and().
+		the_stdout_output_should_contain_n_times("callbacks.received=4 callbacks.missing=0 callbacks.unexpected=0", 1)
+}
+
+func TestRunClosesTheEventsFileWhenItCantBeCreated(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_trigger_type_of(Users).and().
+		an_events_file().and().
+		a_scenario_receiving_callbacks_on_an_address_in_use()
+
+	when.the_run_is_created()
+
+	// the writer of the events file would be reported as a leaked goroutine
+	then.creating_the_run_should_fail_with("scenario callbacks")
+}
+
+func TestRunWithSeed(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_trigger_type_of(Users).and().
+		a_concurrency_of(2).and().
+		an_iteration_limit_of(6).and().
+		a_duration_of(5 * time.Second).and().
+		a_seed_of(42).and().
+		a_scenario_that_records_random_draws()
+
+	when.the_run_command_is_executed()
+
+	then.
+		the_command_finished_successfully().and().
+		each_iteration_should_draw_from_the_seed(6).and().
+		the_stdout_output_should_contain_n_times("seed=42", 2)
+}
+
+func TestRunUsersWithThinkTime(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_trigger_type_of(Users).and().
+		a_concurrency_of(2).and().
+		an_iteration_limit_of(6).and().
+		a_duration_of(5 * time.Second).and().
+		a_think_time_of("100ms").and().
+		a_scenario_where_each_iteration_takes(0)
+
+	when.the_run_command_is_executed()
+
+	then.
+		the_command_finished_successfully().and().
+		the_command_should_have_run_for_approx(200*time.Millisecond).and().
+		the_number_of_started_iterations_should_be(6).and().
+		users_should_have_waited(4, 100*time.Millisecond).and().
+		iterations_should_take_less_than(50 * time.Millisecond)
+}
+
+func TestRunUsersWithPacing(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_trigger_type_of(Users).and().
+		a_concurrency_of(2).and().
+		an_iteration_limit_of(6).and().
+		a_duration_of(5 * time.Second).and().
+		a_pacing_of("200ms").and().
+		a_scenario_where_each_iteration_takes(100 * time.Millisecond)
+
+	when.the_run_command_is_executed()
+
+	then.
+		the_command_finished_successfully().and().
+		the_command_should_have_run_for_approx(500*time.Millisecond).and().
+		the_number_of_started_iterations_should_be(6).and().
+		users_should_have_waited(4, 50*time.Millisecond).and().
+		iterations_should_take_less_than(200 * time.Millisecond)
+}
+
+func TestRunUsersThinkTimeIsInterruptedAtTheEndOfTheRun(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_trigger_type_of(Users).and().
+		a_concurrency_of(2).and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_think_time_of("10s").and().
+		a_scenario_where_each_iteration_takes(0)
+
+	when.the_run_command_is_executed()
+
+	then.
+		the_command_finished_successfully().and().
+		the_command_should_have_run_for_approx(500 * time.Millisecond).and().
+		the_number_of_started_iterations_should_be(2)
+}
+
+func TestRunWithTags(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_trigger_type_of(Users).and().
+		a_concurrency_of(2).and().
+		an_iteration_limit_of(10).and().
+		a_duration_of(5 * time.Second).and().
+		a_scenario_that_tags_iterations_by_tenant()
+
+	when.the_run_command_is_executed()
+
+	then.
+		the_command_finished_successfully().and().
+		the_iteration_metric_has_n_results_tagged(5, "tenant", "even").and().
+		the_iteration_metric_has_n_results_tagged(5, "tenant", "odd").and().
+		the_iteration_metric_has_no_label("request_id").and().
+		the_stdout_output_should_contain_n_times("Key:tenant Values:[{Value:even", 1).and().
+		the_stdout_output_should_contain_n_times("{Value:odd", 1)
+}
+
+func TestRunFailsWithInvalidTagLabels(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_trigger_type_of(Users).and().
+		a_scenario_with_tag_labels("result")
+
+	when.the_run_is_created()
+
+	then.
+		creating_the_run_should_fail_with("tag label 'result' is already a label of the iteration metric")
+}
+
+func TestRunReportsLifecycleEvents(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_reporter().and().
+		a_rate_of("10/100ms").and().
+		a_duration_of(500 * time.Millisecond).and().
+		a_distribution_type("none").and().
+		a_scenario_where_each_iteration_takes(0)
+
+	when.the_run_command_is_executed()
+
+	then.
+		the_reported_events_should_cover_the_run_lifecycle().and().
+		the_reporter_should_receive_n_iterations(50).and().
+		the_reported_result_should_have_iterations(50, 0)
+}
+
+func TestRunReportsFileStages(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_reporter().and().
+		a_trigger_type_of(File).and().
+		a_config_file_location_of("../testdata/config-file.yaml").and().
+		a_duration_of(5 * time.Second).and().
+		a_concurrency_of(50).and().
+		a_scenario_where_each_iteration_takes(0)
+
+	when.the_run_command_is_executed()
+
+	then.
+		the_reported_events_should_cover_the_run_lifecycle().and().
+		the_reporter_should_receive_progress().and().
+		the_reporter_should_receive_n_stages(6)
+}
+
 func TestInterruptedRun(t *testing.T) {
 	t.Parallel()
 
@@ -609,6 +1063,29 @@ func TestInterruptedRun_TimesOut(t *testing.T) {
 	time.Sleep(3 * time.Second)
 }
 
+func TestInterruptedRun_StopsPollingIterations(t *testing.T) {
+	t.Parallel()
+
+	given, when, then := NewRunTestStage(t)
+
+	given.
+		a_timer_is_started().
+		a_rate_of("1/1s").and().
+		a_duration_of(5 * time.Second).and().
+		a_scenario_where_each_iteration_polls_until_the_run_is_interrupted().and().
+		wait_for_completion_timeout_of(5 * time.Second).and().
+		a_distribution_type("none")
+
+	when.
+		the_run_command_is_executed_and_cancelled_after(500 * time.Millisecond)
+
+	then.
+		setup_teardown_is_called_within(1 * time.Second).and().
+		expect_the_stdout_output_to_include([]string{
+			"Interrupted - waiting for active tests to complete",
+		})
+}
+
 func TestMaxDurationReached_TimesOut(t *testing.T) {
 	t.Parallel()
 
@@ -920,6 +1397,7 @@ func TestOutput_JSONLogging(t *testing.T) {
 		name                    string
 		verbose                 bool
 		interactive             bool
+		tui                     bool
 		logFilePath             string
 		expectedStdoutContains  []string
 		expectedStdoutLogLines  []logFieldMatchers
@@ -956,6 +1434,14 @@ func TestOutput_JSONLogging(t *testing.T) {
 			expectedStdoutLogLines:  uiOnlyLogs,
 			expectedLogFileLogLines: scenarioOnlyLogs,
 		},
+		{
+			name:                    "non interactive tui - falls back to structured logs to stdout & scenario logs to file",
+			interactive:             false,
+			verbose:                 false,
+			tui:                     true,
+			expectedStdoutLogLines:  uiOnlyLogs,
+			expectedLogFileLogLines: scenarioOnlyLogs,
+		},
 		{
 			name:                   "non interactive verbose - only structured logs to stdout",
 			interactive:            false,
@@ -972,6 +1458,7 @@ func TestOutput_JSONLogging(t *testing.T) {
 
 			given.
 				verbose_flag_is(testCase.verbose).and().
+				tui_flag_is(testCase.tui).and().
 				json_logging_is_enabled().and().
 				terminal_is_interactive(testCase.interactive).and().
 				a_trigger_type_of(Users).and().
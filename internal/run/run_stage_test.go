@@ -6,9 +6,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"math"
+	"net"
+	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -22,6 +26,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/form3tech-oss/f1/v2/internal/envsettings"
+	"github.com/form3tech-oss/f1/v2/internal/events"
 	"github.com/form3tech-oss/f1/v2/internal/log"
 	"github.com/form3tech-oss/f1/v2/internal/logutils"
 	"github.com/form3tech-oss/f1/v2/internal/metrics"
@@ -35,6 +40,8 @@ import (
 	"github.com/form3tech-oss/f1/v2/internal/trigger/users"
 	"github.com/form3tech-oss/f1/v2/internal/ui"
 	"github.com/form3tech-oss/f1/v2/pkg/f1"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/reporter"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
 	f1_testing "github.com/form3tech-oss/f1/v2/pkg/f1/testing"
 )
 
@@ -69,7 +76,6 @@ type (
 
 type RunTestStage struct {
 	startTime                time.Time
-	metrics                  *metrics.Metrics
 	output                   *ui.Output
 	runInstance              *run.Run
 	runResult                *run.Result
@@ -105,6 +111,22 @@ type RunTestStage struct {
 	stderr                   syncWriter
 	interactive              bool
 	verbose                  bool
+	verboseFail              bool
+	maxFailedIterationLogs   uint64
+	eventsFile               string
+	params                   map[string]string
+	paramValues              sync.Map
+	reporter                 *recordingReporter
+	newRunErr                error
+	vuSetupCount             atomic.Uint32
+	vuTeardownCount          atomic.Uint32
+	vuStateMismatches        atomic.Uint32
+	dataRows                 sync.Map
+	randomDraws              sync.Map
+	seed                     uint64
+	thinkTime                string
+	pacing                   string
+	tui                      bool
 }
 
 func NewRunTestStage(t *testing.T) (*RunTestStage, *RunTestStage, *RunTestStage) {
@@ -118,7 +140,6 @@ func NewRunTestStage(t *testing.T) (*RunTestStage, *RunTestStage, *RunTestStage)
 		settings:                 envsettings.Get(),
 		metricData:               NewMetricData(),
 		output:                   ui.NewDiscardOutput(),
-		metrics:                  metrics.NewInstance(prometheus.NewRegistry(), true, nil),
 		stdout:                   syncWriter{writer: &bytes.Buffer{}},
 		stderr:                   syncWriter{writer: &bytes.Buffer{}},
 		waitForCompletionTimeout: 5 * time.Second,
@@ -204,14 +225,152 @@ func (s *RunTestStage) setupRun() {
 		MaxIterations:            s.maxIterations,
 		MaxFailures:              s.maxFailures,
 		MaxFailuresRate:          s.maxFailuresRate,
-		Verbose:                  s.verbose,
+		Verbose:                  s.verbose || s.verboseFail,
+		VerboseFail:              s.verboseFail,
+		MaxFailedIterationLogs:   s.maxFailedIterationLogs,
+		EventsFile:               s.eventsFile,
+		Params:                   s.params,
+		Reporter:                 s.runReporter(),
+		TUI:                      s.tui,
 		WaitForCompletionTimeout: s.waitForCompletionTimeout,
-	}, s.f1.GetScenarios(), s.build_trigger(), s.settings, s.metrics, outputer)
+		Seed:                     s.seed,
+	}, s.f1.GetScenarios(), s.build_trigger(), s.settings, s.newMetrics, outputer)
 
 	s.require.NoError(err)
 	s.runInstance = r
 }
 
+func (s *RunTestStage) newMetrics(opts ...metrics.Option) *metrics.Metrics {
+	return metrics.NewInstance(prometheus.NewRegistry(), true, nil, opts...)
+}
+
+func (s *RunTestStage) runReporter() reporter.Reporter {
+	if s.reporter == nil {
+		return nil
+	}
+
+	return s.reporter
+}
+
+// recordingReporter keeps the reported events
+type recordingReporter struct {
+	events []reporter.Event
+	mu     sync.Mutex
+}
+
+func (r *recordingReporter) Report(event reporter.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.events = append(r.events, event)
+}
+
+func (r *recordingReporter) reported() []reporter.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.events)
+}
+
+func countEvents[E reporter.Event](events []reporter.Event) int {
+	count := 0
+	for _, event := range events {
+		if _, ok := event.(E); ok {
+			count++
+		}
+	}
+
+	return count
+}
+
+func (s *RunTestStage) a_reporter() *RunTestStage {
+	s.reporter = &recordingReporter{}
+	return s
+}
+
+func (s *RunTestStage) the_reported_events_should_cover_the_run_lifecycle() *RunTestStage {
+	events := s.reporter.reported()
+	s.require.NotEmpty(events)
+
+	s.require.IsType(reporter.RunStarted{}, events[0])
+	s.assert.Equal(s.scenario, events[0].(reporter.RunStarted).Scenario)
+	s.require.IsType(reporter.SetupFinished{}, events[1])
+	s.assert.False(events[1].(reporter.SetupFinished).Failed)
+	s.require.IsType(reporter.RunFinished{}, events[len(events)-1])
+	s.require.IsType(reporter.TeardownFinished{}, events[len(events)-2])
+
+	s.assert.Equal(1, countEvents[reporter.RunStarted](events))
+	s.assert.Equal(1, countEvents[reporter.RunFinished](events))
+	return s
+}
+
+func (s *RunTestStage) the_reporter_should_receive_progress() *RunTestStage {
+	s.assert.Positive(countEvents[reporter.Progress](s.reporter.reported()))
+	return s
+}
+
+func (s *RunTestStage) the_reporter_should_receive_n_iterations(expected int) *RunTestStage {
+	s.assert.Equal(expected, countEvents[reporter.IterationFinished](s.reporter.reported()))
+	return s
+}
+
+func (s *RunTestStage) the_reporter_should_receive_n_stages(expected int) *RunTestStage {
+	events := s.reporter.reported()
+	s.require.Equal(expected, countEvents[reporter.StageStarted](events))
+
+	index := 0
+	for _, event := range events {
+		if stage, ok := event.(reporter.StageStarted); ok {
+			s.assert.Equal(index, stage.Index)
+			s.assert.Equal(expected, stage.Stages)
+			index++
+		}
+	}
+	return s
+}
+
+func (s *RunTestStage) the_reported_result_should_have_iterations(successful, failed uint64) *RunTestStage {
+	events := s.reporter.reported()
+	s.require.NotEmpty(events)
+
+	finished, ok := events[len(events)-1].(reporter.RunFinished)
+	s.require.True(ok, "the last event should be RunFinished")
+	s.assert.Equal(successful, finished.Result.SuccessfulIterations)
+	s.assert.Equal(failed, finished.Result.FailedIterations)
+	s.assert.Equal(failed == 0, finished.Result.Passed)
+	return s
+}
+
+func (s *RunTestStage) the_run_is_created() *RunTestStage {
+	printer := ui.NewPrinter(&s.stdout, &s.stderr)
+	outputer := ui.NewOutput(log.NewDiscardLogger(), printer, s.interactive, false)
+
+	_, s.newRunErr = run.NewRun(options.RunOptions{
+		Scenario:                 s.scenario,
+		MaxDuration:              s.duration,
+		Concurrency:              s.concurrency,
+		Params:                   s.params,
+		EventsFile:               s.eventsFile,
+		WaitForCompletionTimeout: s.waitForCompletionTimeout,
+	}, s.f1.GetScenarios(), s.build_trigger(), s.settings, s.newMetrics, outputer)
+
+	return s
+}
+
+func (s *RunTestStage) the_help_of_the_trigger_command_is_shown() *RunTestStage {
+	cmd := run.Cmd(s.f1.GetScenarios(), nil, []api.Builder{constant.Rate()}, s.settings, s.newMetrics, nil, s.output)
+	cmd.SetOut(&s.stdout)
+	cmd.SetArgs([]string{"constant", "--help"})
+	s.require.NoError(cmd.Execute())
+
+	return s
+}
+
+func (s *RunTestStage) creating_the_run_should_fail_with(expected string) *RunTestStage {
+	s.require.ErrorContains(s.newRunErr, expected)
+	return s
+}
+
 func (s *RunTestStage) the_run_command_is_executed() *RunTestStage {
 	s.setupRun()
 
@@ -355,6 +514,19 @@ func (s *RunTestStage) a_scenario_where_each_iteration_takes(duration time.Durat
 	return s
 }
 
+func (s *RunTestStage) a_scenario_where_each_iteration_polls_until_the_run_is_interrupted() *RunTestStage {
+	s.scenario = "scenario_where_each_iteration_polls"
+	s.f1.Add(s.scenario, func(scenarioT *f1_testing.T) f1_testing.RunFn {
+		scenarioT.Cleanup(s.scenarioCleanup)
+
+		return func(iterationT *f1_testing.T) {
+			s.runCount.Add(1)
+			iterationT.Eventually("poll", func() bool { return false }, time.Hour, 10*time.Millisecond)
+		}
+	})
+	return s
+}
+
 func (s *RunTestStage) setup_teardown_is_called() *RunTestStage {
 	s.assert.Equal(1, int(s.setupTeardownCount.Load()), "setup teardown was not called")
 	return s
@@ -385,11 +557,294 @@ func (s *RunTestStage) a_test_scenario_that_fails_intermittently() *RunTestStage
 	return s
 }
 
+func (s *RunTestStage) a_test_scenario_that_logs_and_fails_intermittently() *RunTestStage {
+	s.scenario = "scenario_that_logs_and_fails_intermittently"
+	s.f1.Add(s.scenario, func(scenarioT *f1_testing.T) f1_testing.RunFn {
+		scenarioT.Cleanup(s.scenarioCleanup)
+
+		return func(t *f1_testing.T) {
+			t.Cleanup(s.iterationCleanup)
+
+			count := s.runCount.Add(1)
+			t.Logf("running iteration %d", count)
+			t.Require().Equal(uint32(0), count%2)
+		}
+	})
+	return s
+}
+
+func (s *RunTestStage) a_scenario_that_records_the_param(
+	name string,
+	declared ...scenarios.ScenarioParameter,
+) *RunTestStage {
+	s.scenario = "scenario_that_records_the_param_" + name
+
+	options := make([]scenarios.ScenarioOption, len(declared))
+	for i, parameter := range declared {
+		options[i] = scenarios.Parameter(parameter)
+	}
+
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		return func(t *f1_testing.T) {
+			count, _ := s.paramValues.LoadOrStore(t.Param(name), &atomic.Int64{})
+			count.(*atomic.Int64).Add(1)
+		}
+	}, options...)
+	return s
+}
+
+func (s *RunTestStage) a_scenario_with_virtual_user_setup() *RunTestStage {
+	s.scenario = "scenario_with_virtual_user_setup"
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		return func(t *f1_testing.T) {
+			if t.VU() != t.VUID {
+				s.vuStateMismatches.Add(1)
+			}
+		}
+	}, scenarios.WithVUSetup(func(t *f1_testing.T) any {
+		s.vuSetupCount.Add(1)
+		t.Cleanup(func() {
+			s.vuTeardownCount.Add(1)
+		})
+		return t.VUID
+	}))
+	return s
+}
+
+func (s *RunTestStage) a_scenario_with_failing_virtual_user_setup() *RunTestStage {
+	s.scenario = "scenario_with_failing_virtual_user_setup"
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		return func(*f1_testing.T) {
+			s.runCount.Add(1)
+		}
+	}, scenarios.WithVUSetup(func(t *f1_testing.T) any {
+		t.Errorf("no connection")
+		return nil
+	}))
+	return s
+}
+
+func (s *RunTestStage) n_virtual_users_should_be_set_up_and_torn_down(n uint32) *RunTestStage {
+	s.assert.Equal(n, s.vuSetupCount.Load(), "virtual user setups")
+	s.assert.Equal(n, s.vuTeardownCount.Load(), "virtual user teardowns")
+	return s
+}
+
+func (s *RunTestStage) iterations_should_see_the_state_of_their_virtual_user() *RunTestStage {
+	s.assert.Zero(s.vuStateMismatches.Load(), "iterations which saw the state of another virtual user")
+	return s
+}
+
+func (s *RunTestStage) the_scenario_should_not_have_run() *RunTestStage {
+	s.assert.Zero(s.runCount.Load(), "scenario iterations run")
+	return s
+}
+
+func (s *RunTestStage) a_scenario_that_records_the_data(file scenarios.DataFile) *RunTestStage {
+	s.scenario = "scenario_that_records_the_data_" + string(file.Mode)
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		return func(t *f1_testing.T) {
+			key := fmt.Sprintf("%d:%s", t.VUID, t.Data()["account_id"])
+			count, _ := s.dataRows.LoadOrStore(key, &atomic.Int64{})
+			count.(*atomic.Int64).Add(1)
+		}
+	}, scenarios.WithData(file))
+	return s
+}
+
+func (s *RunTestStage) a_seed_of(seed uint64) *RunTestStage {
+	s.seed = seed
+	return s
+}
+
+func (s *RunTestStage) a_scenario_that_records_random_draws() *RunTestStage {
+	s.scenario = "scenario_that_records_random_draws"
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		return func(t *f1_testing.T) {
+			s.randomDraws.Store(fmt.Sprintf("%s/%d", t.Iteration, t.VUID), t.Rand().Uint64())
+		}
+	})
+	return s
+}
+
+// each_iteration_should_draw_from_the_seed checks that the draws of each iteration are those of a
+// T with the same seed, iteration and VUID.
+func (s *RunTestStage) each_iteration_should_draw_from_the_seed(iterations int) *RunTestStage {
+	draws := 0
+	s.randomDraws.Range(func(key, value any) bool {
+		iteration, vuid, ok := strings.Cut(key.(string), "/")
+		s.require.True(ok)
+		id, err := strconv.Atoi(vuid)
+		s.require.NoError(err)
+
+		t, teardown := f1_testing.NewTWithOptions(s.scenario,
+			f1_testing.WithSeed(s.seed),
+			f1_testing.WithIteration(iteration),
+			f1_testing.WithVUID(id),
+		)
+		defer teardown()
+
+		s.assert.Equal(t.Rand().Uint64(), value, "draw of iteration %s", key)
+		draws++
+		return true
+	})
+	s.assert.Equal(iterations, draws, "iterations which drew random numbers")
+	return s
+}
+
+func (s *RunTestStage) a_scenario_where_each_iteration_awaits_its_callback() *RunTestStage {
+	s.scenario = "scenario_where_each_iteration_awaits_its_callback"
+	s.f1.Add(s.scenario, func(t *f1_testing.T) f1_testing.RunFn {
+		url := "http://" + t.CallbackAddress()
+
+		return func(t *f1_testing.T) {
+			id := "request-" + t.Iteration
+			t.ExpectCallback(id)
+			go func() {
+				req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, url, http.NoBody)
+				s.assert.NoError(err)
+				req.Header.Set("X-Request-Id", id)
+				res, err := http.DefaultClient.Do(req)
+				if s.assert.NoError(err) {
+					s.assert.NoError(res.Body.Close())
+				}
+			}()
+			t.AwaitCallback(id, time.Second)
+		}
+	}, scenarios.WithCallbacks(scenarios.Callbacks{Address: "127.0.0.1:0", IDHeader: "X-Request-Id"}))
+	return s
+}
+
+func (s *RunTestStage) a_scenario_receiving_callbacks_on_an_address_in_use() *RunTestStage {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	s.require.NoError(err)
+	s.t.Cleanup(func() { _ = listener.Close() })
+
+	s.scenario = "scenario_receiving_callbacks_on_an_address_in_use"
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		return func(*f1_testing.T) {}
+	}, scenarios.WithCallbacks(scenarios.Callbacks{Address: listener.Addr().String(), IDHeader: "X-Request-Id"}))
+	return s
+}
+
+func (s *RunTestStage) a_scenario_that_tags_iterations_by_tenant() *RunTestStage {
+	s.scenario = "scenario_that_tags_iterations_by_tenant"
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		return func(t *f1_testing.T) {
+			iteration, err := strconv.Atoi(t.Iteration)
+			s.require.NoError(err)
+
+			tenant := "odd"
+			if iteration%2 == 0 {
+				tenant = "even"
+			}
+			t.Tag("tenant", tenant)
+			t.Tag("request_id", t.Iteration)
+		}
+	}, scenarios.WithTagLabels("tenant"))
+	return s
+}
+
+func (s *RunTestStage) a_scenario_with_tag_labels(keys ...string) *RunTestStage {
+	s.scenario = "scenario_with_tag_labels"
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		return func(*f1_testing.T) {}
+	}, scenarios.WithTagLabels(keys...))
+	return s
+}
+
+// the_data_rows_used returns the number of iterations which used each account id, and
+// the account ids used by each virtual user.
+func (s *RunTestStage) the_data_rows_used() (map[string]int64, map[int][]string) {
+	byAccount := map[string]int64{}
+	byVU := map[int][]string{}
+	s.dataRows.Range(func(key, value any) bool {
+		var vuid int
+		var account string
+		_, err := fmt.Sscanf(strings.Replace(key.(string), ":", " ", 1), "%d %s", &vuid, &account)
+		s.require.NoError(err)
+
+		byAccount[account] += value.(*atomic.Int64).Load()
+		byVU[vuid] = append(byVU[vuid], account)
+		return true
+	})
+	return byAccount, byVU
+}
+
+func (s *RunTestStage) each_data_row_should_be_used_once(accounts ...string) *RunTestStage {
+	byAccount, _ := s.the_data_rows_used()
+	expected := map[string]int64{}
+	for _, account := range accounts {
+		expected[account] = 1
+	}
+	s.assert.Equal(expected, byAccount)
+	return s
+}
+
+func (s *RunTestStage) the_virtual_user_should_only_use_rows(vuid int, accounts ...string) *RunTestStage {
+	_, byVU := s.the_data_rows_used()
+	s.assert.Subset(accounts, byVU[vuid], "rows used by virtual user %d", vuid)
+	return s
+}
+
+func (s *RunTestStage) a_think_time_of(thinkTime string) *RunTestStage {
+	s.thinkTime = thinkTime
+	return s
+}
+
+func (s *RunTestStage) a_pacing_of(pacing string) *RunTestStage {
+	s.pacing = pacing
+	return s
+}
+
+// users_should_have_waited checks the waits between iterations, which include waits interrupted
+// at the end of the run.
+func (s *RunTestStage) users_should_have_waited(atLeast uint64, longestWait time.Duration) *RunTestStage {
+	waits := s.runResult.Snapshot().WaitDurations
+	s.assert.GreaterOrEqual(waits.Count, atLeast, "waits between iterations")
+	s.assert.GreaterOrEqual(waits.Max, longestWait, "longest wait between iterations")
+	return s
+}
+
+func (s *RunTestStage) iterations_should_take_less_than(maxDuration time.Duration) *RunTestStage {
+	s.assert.Less(s.runResult.Snapshot().SuccessfulIterationDurations.Max, maxDuration, "longest iteration")
+	return s
+}
+
+func (s *RunTestStage) a_param_of(name, value string) *RunTestStage {
+	if s.params == nil {
+		s.params = map[string]string{}
+	}
+	s.params[name] = value
+	return s
+}
+
+func (s *RunTestStage) the_param_value_should_be_seen_n_times(value string, n int64) *RunTestStage {
+	count, ok := s.paramValues.Load(value)
+	s.require.True(ok, "param value %q was not seen", value)
+	s.assert.Equal(n, count.(*atomic.Int64).Load(), "iterations which saw param value %q", value)
+	return s
+}
+
+func (s *RunTestStage) the_param_value_should_be_seen(value string) *RunTestStage {
+	_, ok := s.paramValues.Load(value)
+	s.assert.True(ok, "param value %q was not seen", value)
+	return s
+}
+
 func (s *RunTestStage) the_results_should_show_n_failures(expectedFailures uint64) *RunTestStage {
 	s.assert.Equal(expectedFailures, s.runResult.Snapshot().FailedIterationDurations.Count, "failure count does not match expected")
 	return s
 }
 
+func (s *RunTestStage) the_top_failure_reason_should_be(expectedReason string) *RunTestStage {
+	failures := s.runResult.TopFailures(1)
+	s.require.Len(failures, 1)
+	s.assert.Equal(expectedReason, failures[0].Reason)
+	s.assert.Equal(s.runResult.Snapshot().FailedIterationDurations.Count, failures[0].Count)
+	return s
+}
+
 func (s *RunTestStage) the_results_should_show_n_successful_iterations(expected uint64) *RunTestStage {
 	s.assert.Equal(expected, s.runResult.Snapshot().SuccessfulIterationDurations.Count, "success count does not match expected")
 	return s
@@ -488,6 +943,17 @@ func (s *RunTestStage) build_trigger() *api.Trigger {
 		require.NoError(s.t, err)
 	case Users:
 		flags := users.Rate().Flags
+
+		if s.thinkTime != "" {
+			err = flags.Set("think-time", s.thinkTime)
+			require.NoError(s.t, err)
+		}
+
+		if s.pacing != "" {
+			err = flags.Set("pacing", s.pacing)
+			require.NoError(s.t, err)
+		}
+
 		t, err = users.Rate().New(flags)
 		require.NoError(s.t, err)
 	case Ramp:
@@ -622,6 +1088,42 @@ func (s *RunTestStage) the_iteration_metric_has_n_results(n int, result string)
 	return s
 }
 
+func (s *RunTestStage) the_iteration_metric_has_n_results_tagged(n int, key, value string) *RunTestStage {
+	err := retry(func() error {
+		metricFamily := s.metricData.GetMetricFamily(iterationMetricFamily)
+		if metricFamily == nil {
+			return fmt.Errorf("metric family %s not found", iterationMetricFamily)
+		}
+
+		var count uint64
+		for _, metric := range metricFamily.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == key && label.GetValue() == value {
+					count += metric.GetSummary().GetSampleCount()
+				}
+			}
+		}
+		if uint64(n) == count {
+			return nil
+		}
+		return fmt.Errorf("expected %d results tagged %s=%s, got %d", n, key, value, count)
+	}, 10, 50*time.Millisecond)
+	s.require.NoError(err)
+	return s
+}
+
+func (s *RunTestStage) the_iteration_metric_has_no_label(labelName string) *RunTestStage {
+	metricFamily := s.metricData.GetMetricFamily(iterationMetricFamily)
+	s.require.NotNil(metricFamily, "metric family %s not found", iterationMetricFamily)
+
+	for _, metric := range metricFamily.GetMetric() {
+		for _, label := range metric.GetLabel() {
+			s.assert.NotEqual(labelName, label.GetName())
+		}
+	}
+	return s
+}
+
 func (s *RunTestStage) all_exported_metrics_contain_label(labelName string, labelValue string) *RunTestStage {
 	metricNames := s.metricData.GetMetricNames()
 
@@ -658,6 +1160,44 @@ func (s *RunTestStage) verbose_flag_is(verbose bool) *RunTestStage {
 	return s
 }
 
+func (s *RunTestStage) verbose_fail_flag_is(verboseFail bool) *RunTestStage {
+	s.verboseFail = verboseFail
+	return s
+}
+
+func (s *RunTestStage) a_max_failed_iteration_logs_of(maxLogs uint64) *RunTestStage {
+	s.maxFailedIterationLogs = maxLogs
+	return s
+}
+
+func (s *RunTestStage) an_events_file() *RunTestStage {
+	s.eventsFile = filepath.Join(s.t.TempDir(), "events.ndjson")
+	return s
+}
+
+func (s *RunTestStage) the_events_file_should_contain_n_events_with_result(n int, result string) *RunTestStage {
+	file, err := os.Open(s.eventsFile)
+	s.require.NoError(err)
+	defer file.Close()
+
+	count := 0
+	s.require.NoError(events.Read(file, func(event events.Event) error {
+		if event.Result == result {
+			count++
+			s.assert.NotZero(event.Iteration)
+			s.assert.GreaterOrEqual(event.StartedAt, event.ScheduledAt)
+		}
+		return nil
+	}))
+	s.assert.Equal(n, count, "number of %s events", result)
+	return s
+}
+
+func (s *RunTestStage) tui_flag_is(tui bool) *RunTestStage {
+	s.tui = tui
+	return s
+}
+
 func (s *RunTestStage) json_logging_is_enabled() *RunTestStage {
 	s.settings.Log.Format = "json"
 	return s
@@ -668,6 +1208,11 @@ func (s *RunTestStage) expect_the_stdout_output_to_include(expectedList []string
 	return s
 }
 
+func (s *RunTestStage) the_stdout_output_should_contain_n_times(expected string, n int) *RunTestStage {
+	s.assert.Equal(n, strings.Count(s.stdout.String(), expected), "occurrences of %q in stdout", expected)
+	return s
+}
+
 func (s *RunTestStage) expect_stderr_to_match_json_log(expectedLogLines []logFieldMatchers) *RunTestStage {
 	s.assertJSONLogMatches(s.t, s.stderr.String(), expectedLogLines, "error matching stderr")
 	return s
@@ -4,23 +4,32 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus/push"
 
+	"github.com/form3tech-oss/f1/v2/internal/callbacks"
+	"github.com/form3tech-oss/f1/v2/internal/clock"
+	"github.com/form3tech-oss/f1/v2/internal/data"
 	"github.com/form3tech-oss/f1/v2/internal/envsettings"
+	"github.com/form3tech-oss/f1/v2/internal/events"
 	"github.com/form3tech-oss/f1/v2/internal/log"
 	"github.com/form3tech-oss/f1/v2/internal/logutils"
 	"github.com/form3tech-oss/f1/v2/internal/metrics"
 	"github.com/form3tech-oss/f1/v2/internal/options"
+	"github.com/form3tech-oss/f1/v2/internal/params"
 	"github.com/form3tech-oss/f1/v2/internal/progress"
+	"github.com/form3tech-oss/f1/v2/internal/queues"
 	"github.com/form3tech-oss/f1/v2/internal/raterun"
 	"github.com/form3tech-oss/f1/v2/internal/run/views"
 	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
+	"github.com/form3tech-oss/f1/v2/internal/tui"
 	"github.com/form3tech-oss/f1/v2/internal/ui"
 	"github.com/form3tech-oss/f1/v2/internal/workers"
 	"github.com/form3tech-oss/f1/v2/internal/xcontext"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/reporter"
 	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
 )
 
@@ -35,11 +44,20 @@ type Run struct {
 	metrics        *metrics.Metrics
 	views          *views.Views
 	activeScenario *workers.ActiveScenario
+	poolManager    *workers.PoolManager
 	trigger        *api.Trigger
 	output         *ui.Output
+	// summaryOutput displays the result summary, which is printed after the dashboard is closed
+	summaryOutput  *ui.Output
 	scenarioLogger *ScenarioLogger
 	result         *Result
+	dashboard      *tui.Dashboard
+	events         *events.Writer
+	callbacks      *callbacks.Listener
+	releaseQueues  func()
+	stop           context.CancelFunc
 	options        options.RunOptions
+	lastTriggered  uint64
 }
 
 func NewRun(
@@ -47,9 +65,19 @@ func NewRun(
 	scenarios *scenarios.Scenarios,
 	trigger *api.Trigger,
 	settings envsettings.Settings,
-	metricsInstance *metrics.Metrics,
+	newMetrics metrics.Factory,
 	parentOutput *ui.Output,
-) (*Run, error) {
+) (_ *Run, err error) {
+	// closers close what was opened for the run, if it can't be created
+	var closers []func()
+	defer func() {
+		if err != nil {
+			for _, closer := range slices.Backward(closers) {
+				closer()
+			}
+		}
+	}()
+
 	progressStats := &progress.Stats{}
 	viewsInstance := views.New()
 
@@ -58,7 +86,71 @@ func NewRun(
 		return nil, fmt.Errorf("scenario not defined: %s", options.Scenario)
 	}
 
+	declaredParams := make([]params.Declared, len(scenario.Parameters))
+	for i, parameter := range scenario.Parameters {
+		declaredParams[i] = params.Declared{Name: parameter.Name, Default: parameter.Default}
+	}
+	scenarioParams, err := params.New(declaredParams, options.Params)
+	if err != nil {
+		return nil, fmt.Errorf("scenario parameters: %w", err)
+	}
+	for i, values := range trigger.StageParams {
+		if err := params.Check(declaredParams, values); err != nil {
+			return nil, fmt.Errorf("scenario parameters of stage %d: %w", i+1, err)
+		}
+	}
+
+	if err := metrics.ValidateTagLabels(scenario.TagLabels); err != nil {
+		return nil, fmt.Errorf("scenario tag labels: %w", err)
+	}
+	metricsInstance := newMetrics(metrics.WithTagLabels(scenario.TagLabels...))
+
+	var feeder *data.Feeder
+	limitedByData := false
+	if scenario.Data != nil {
+		feeder, err = data.Load(
+			scenario.Data.Path,
+			data.Mode(scenario.Data.Mode),
+			scenario.Data.StopWhenExhausted,
+			options.Concurrency,
+			options.Seed,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("scenario data: %w", err)
+		}
+
+		// the run stops with the max iterations once all rows have been used
+		if limit := feeder.Limit(); limit > 0 && (options.MaxIterations == 0 || options.MaxIterations > limit) {
+			options.MaxIterations = limit
+			limitedByData = true
+		}
+	}
+
+	queueRegistry := options.Queues
+	if queueRegistry == nil {
+		queueRegistry = queues.NewRegistry()
+	}
+	queueBindings := make(map[string]queues.Binding, len(scenario.Queues))
+	releaseQueues := func() {
+		for name := range queueBindings {
+			queueRegistry.Release(name)
+		}
+	}
+	closers = append(closers, releaseQueues)
+	for _, queue := range scenario.Queues {
+		capacity, err := queueCapacity(scenarios, queue.Name)
+		if err != nil {
+			return nil, fmt.Errorf("scenario queues: %w", err)
+		}
+		binding, err := queueRegistry.Bind(queue.Name, capacity, queues.EmptyPolicy(queue.WhenEmpty))
+		if err != nil {
+			return nil, fmt.Errorf("scenario queues: %w", err)
+		}
+		queueBindings[queue.Name] = binding
+	}
+
 	result := NewResult(options, viewsInstance, progressStats)
+	result.feeder = feeder
 
 	outputer := ui.NewOutput(
 		parentOutput.Logger.With(log.ScenarioAttr(scenario.Name)),
@@ -66,6 +158,20 @@ func NewRun(
 		parentOutput.Interactive,
 		options.LogToFile(),
 	)
+	summaryOutput := outputer
+
+	// the dashboard is only used on a terminal, otherwise the output falls back to the default behaviour
+	var dashboard *tui.Dashboard
+	if options.TUI && parentOutput.Interactive && options.LogToFile() {
+		dashboard = tui.New(scenario.Name)
+		closers = append(closers, dashboard.Stop)
+		outputer = ui.NewOutput(
+			outputer.Logger,
+			ui.NewPrinter(dashboard, dashboard),
+			outputer.Interactive,
+			outputer.AllowPrinting,
+		)
+	}
 
 	scenarioLogger := NewScenarioLogger(outputer)
 	result.LogFilePath = scenarioLogger.Open(
@@ -74,23 +180,69 @@ func NewRun(
 		scenario.Name,
 		options.LogToFile(),
 	)
+	closers = append(closers, func() { _ = scenarioLogger.Close() })
 
-	progressRunner, err := newProgressRunner(result, outputer)
-	if err != nil {
-		return nil, fmt.Errorf("creating progress runner: %w", err)
+	logger := scenarioLogger.Logger
+
+	if limitedByData {
+		outputer.Display(ui.InfoMessage{Message: fmt.Sprintf(
+			"Limiting the run to %d iterations, the number of rows in %s", options.MaxIterations, feeder.Path(),
+		)})
+	}
+
+	var failedIterationLogs *log.FlushLimit
+	if options.VerboseFail {
+		failedIterationLogs = log.NewFlushLimit(options.MaxFailedIterationLogs)
+	}
+
+	var eventsWriter *events.Writer
+	if options.EventsFile != "" {
+		eventsWriter, err = events.Create(options.EventsFile)
+		if err != nil {
+			return nil, fmt.Errorf("opening events file: %w", err)
+		}
+		closers = append(closers, func() { _ = eventsWriter.Close() })
+		outputer.Display(ui.InfoMessage{Message: "Saving iteration events to " + options.EventsFile})
+	}
+
+	var listener *callbacks.Listener
+	if scenario.Callbacks != nil {
+		listener, err = callbacks.Listen(callbacks.Config{
+			Address:  scenario.Callbacks.Address,
+			IDHeader: scenario.Callbacks.IDHeader,
+			IDPath:   scenario.Callbacks.IDPath,
+		}, func(result callbacks.Result) {
+			metricsInstance.RecordCallback(scenario.Name, string(result))
+		})
+		if err != nil {
+			return nil, fmt.Errorf("scenario callbacks: %w", err)
+		}
+		closers = append(closers, func() { _ = listener.Close() })
+		result.callbacks = listener
+		outputer.Display(ui.InfoMessage{Message: "Receiving callbacks on " + listener.Addr()})
 	}
 
 	activeScenario := workers.NewActiveScenario(
 		scenario,
 		metricsInstance,
 		progressStats,
-		scenarioLogger.Logger,
-		log.NewSlogLogrusLogger(scenarioLogger.Logger),
+		logger,
+		log.NewSlogLogrusLogger(logger),
+		workers.ActiveScenarioOptions{
+			Params:              scenarioParams,
+			Feeder:              feeder,
+			Listener:            listener,
+			Queues:              queueBindings,
+			FailedIterationLogs: failedIterationLogs,
+			Events:              eventsWriter,
+			Reporter:            options.Reporter,
+			Seed:                options.Seed,
+		},
 	)
 
 	pusher := newMetricsPusher(settings, scenario.Name, metricsInstance)
 
-	return &Run{
+	run := &Run{
 		options:        options,
 		trigger:        trigger,
 		metrics:        metricsInstance,
@@ -98,10 +250,42 @@ func NewRun(
 		result:         result,
 		pusher:         pusher,
 		output:         outputer,
-		progressRunner: progressRunner,
+		summaryOutput:  summaryOutput,
 		activeScenario: activeScenario,
+		poolManager:    workers.New(options.MaxIterations, activeScenario, clock.Real{}),
 		scenarioLogger: scenarioLogger,
-	}, nil
+		dashboard:      dashboard,
+		events:         eventsWriter,
+		callbacks:      listener,
+		releaseQueues:  releaseQueues,
+	}
+
+	progressRunner, err := run.newProgressRunner()
+	if err != nil {
+		return nil, fmt.Errorf("creating progress runner: %w", err)
+	}
+	run.progressRunner = progressRunner
+
+	return run, nil
+}
+
+// queueCapacity returns the capacity of the queue name declared by the scenarios, so that it
+// doesn't depend on which of them runs first, or 0 if none declares one.
+func queueCapacity(all *scenarios.Scenarios, name string) (int, error) {
+	capacity := 0
+	for _, scenarioName := range all.GetScenarioNames() {
+		for _, queue := range all.GetScenario(scenarioName).Queues {
+			if queue.Name != name || queue.Capacity == 0 {
+				continue
+			}
+			if capacity != 0 && queue.Capacity != capacity {
+				return 0, fmt.Errorf("queue %s is declared with capacities %d and %d", name, capacity, queue.Capacity)
+			}
+			capacity = queue.Capacity
+		}
+	}
+
+	return capacity, nil
 }
 
 func newMetricsPusher(
@@ -127,50 +311,149 @@ func newMetricsPusher(
 	return pusher
 }
 
-func newProgressRunner(result *Result, output *ui.Output) (*raterun.Runner, error) {
+func (r *Run) newProgressRunner() (*raterun.Runner, error) {
 	notifyDropped := sync.Once{}
 
-	r, err := raterun.New(func(rate time.Duration) {
-		result.SnapshotProgress(rate)
-		output.Display(result.Progress())
-		if result.HasDroppedIterations() {
+	schedules := raterun.ProgressSchedules()
+	if r.dashboard != nil {
+		// the dashboard doesn't scroll, so it can be refreshed every second for the whole run
+		schedules = schedules[:1]
+	}
+
+	runner, err := raterun.New(func(rate time.Duration) {
+		r.result.SnapshotProgress(rate)
+		r.report(r.result.ProgressEvent())
+		if r.dashboard != nil {
+			r.dashboard.Update(r.dashboardStatus(rate))
+		} else {
+			r.output.Display(r.result.Progress())
+		}
+		if r.result.HasDroppedIterations() {
 			notifyDropped.Do(func() {
-				output.Display(ui.WarningMessage{
+				r.output.Display(ui.WarningMessage{
 					Message: "Dropping requests as workers are too busy. " +
 						"Considering increasing `--concurrency` argument",
 				})
 			})
 		}
-	}, []raterun.Schedule{
-		{StartDelay: 0, Frequency: time.Second},
-		{StartDelay: time.Minute, Frequency: 10 * time.Second},
-		{StartDelay: 5 * time.Minute, Frequency: 30 * time.Second},
-		{StartDelay: 10 * time.Minute, Frequency: time.Minute},
-	})
+	}, schedules, clock.Real{})
 	if err != nil {
 		return nil, fmt.Errorf("new progress runner: %w", err)
 	}
 
-	return r, nil
+	return runner, nil
+}
+
+func dashboardErrors(failures []progress.FailureReason) []tui.ErrorCount {
+	errs := make([]tui.ErrorCount, len(failures))
+	for i, failure := range failures {
+		errs[i] = tui.ErrorCount{Message: failure.Reason, Count: failure.Count}
+	}
+
+	return errs
+}
+
+func (r *Run) dashboardStatus(period time.Duration) tui.Status {
+	snapshot := r.result.Snapshot()
+
+	triggered := r.poolManager.TriggeredIterations()
+	triggeredRate := 0.0
+	if period > 0 {
+		triggeredRate = float64(triggered-r.lastTriggered) / period.Seconds()
+	}
+	r.lastTriggered = triggered
+
+	stage := r.poolManager.Stage()
+	if stage == "" {
+		stage = r.trigger.Description
+	}
+
+	return tui.Status{
+		Stage:                    stage,
+		Errors:                   dashboardErrors(r.result.TopFailures(tui.MaxErrorRows)),
+		Duration:                 r.result.Elapsed(),
+		Period:                   snapshot.Period,
+		PeriodAverage:            snapshot.SuccessfulIterationDurationsForPeriod.Average,
+		PeriodIterationCount:     snapshot.SuccessfulIterationDurationsForPeriod.Count,
+		SuccessfulIterationCount: snapshot.SuccessfulIterationDurations.Count,
+		FailedIterationCount:     snapshot.FailedIterationDurations.Count,
+		DroppedIterationCount:    snapshot.DroppedIterationCount,
+		TriggeredRate:            triggeredRate,
+		HasTriggeredRate:         !r.poolManager.Continuous(),
+		Workers:                  r.poolManager.Workers(),
+		BusyWorkers:              r.poolManager.BusyWorkers(),
+		Paused:                   r.poolManager.Paused(),
+	}
+}
+
+// Pause stops new iterations from being started.
+func (r *Run) Pause() {
+	r.poolManager.Pause()
+}
+
+// Resume continues starting iterations after Pause.
+func (r *Run) Resume() {
+	r.poolManager.Resume()
+}
+
+// Stop interrupts the run, as if it had received a signal.
+func (r *Run) Stop() {
+	if r.stop != nil {
+		r.stop()
+	}
 }
 
 func (r *Run) Do(ctx context.Context) (*Result, error) {
 	defer r.scenarioLogger.Close()
+	defer r.closeEvents()
+	defer r.closeCallbacks()
+	// released once the teardown, which can use the queues, is done
+	defer r.releaseQueues()
+
+	if r.dashboard != nil {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+
+		r.stop = cancel
+		r.dashboard.Start(r)
+	}
+
+	// iterations waiting on T.Context stop once the run is interrupted
+	stopInterrupt := context.AfterFunc(ctx, r.activeScenario.Interrupt)
+	defer stopInterrupt()
 
 	welcomeMessage := r.views.Start(views.StartData{
 		Scenario:        r.options.Scenario,
 		MaxDuration:     r.options.MaxDuration,
 		MaxIterations:   r.options.MaxIterations,
 		RateDescription: r.trigger.Description,
+		Seed:            r.options.Seed,
 	})
 
 	r.output.Display(welcomeMessage)
+	r.report(reporter.RunStarted{
+		Time:          time.Now(),
+		Scenario:      r.options.Scenario,
+		Trigger:       r.trigger.Description,
+		MaxDuration:   r.options.MaxDuration,
+		MaxIterations: r.options.MaxIterations,
+		Concurrency:   r.options.Concurrency,
+		Seed:          r.options.Seed,
+	})
 
+	defer r.reportFinished()
 	defer r.printSummary()
+	defer r.stopDashboard()
 
 	r.metrics.Reset()
 
+	setupStart := time.Now()
 	r.activeScenario.Setup()
+	r.report(reporter.SetupFinished{
+		Duration: time.Since(setupStart),
+		Failed:   r.activeScenario.Failed(),
+	})
 
 	r.pushMetrics(ctx)
 
@@ -225,12 +508,60 @@ func (r *Run) teardownActiveScenario(ctx context.Context) {
 	if r.activeScenario.TeardownFailed() {
 		r.fail("teardown failed")
 	}
+	r.report(reporter.TeardownFinished{Failed: r.activeScenario.TeardownFailed()})
 	r.pushMetrics(ctx)
 	r.output.Display(r.result.Teardown())
 }
 
 func (r *Run) printSummary() {
-	r.output.Display(r.result.Summary())
+	r.summaryOutput.Display(r.result.Summary())
+}
+
+func (r *Run) report(event reporter.Event) {
+	if r.options.Reporter != nil {
+		r.options.Reporter.Report(event)
+	}
+}
+
+func (r *Run) reportFinished() {
+	r.report(reporter.RunFinished{Result: r.result.Report()})
+}
+
+func (r *Run) interrupted() {
+	r.output.Display(r.result.Interrupted())
+	r.report(reporter.Interrupted{Elapsed: r.result.Elapsed()})
+}
+
+func (r *Run) closeEvents() {
+	if r.events == nil {
+		return
+	}
+
+	if err := r.events.Close(); err != nil {
+		r.summaryOutput.Display(ui.ErrorMessage{Message: "Error writing iteration events", Error: err})
+	}
+
+	if dropped := r.events.Dropped(); dropped > 0 {
+		r.summaryOutput.Display(ui.WarningMessage{
+			Message: fmt.Sprintf("%d iteration events were dropped, as they could not be written fast enough", dropped),
+		})
+	}
+}
+
+func (r *Run) closeCallbacks() {
+	if r.callbacks == nil {
+		return
+	}
+
+	if err := r.callbacks.Close(); err != nil {
+		r.summaryOutput.Display(ui.ErrorMessage{Message: "Error closing callback listener", Error: err})
+	}
+}
+
+func (r *Run) stopDashboard() {
+	if r.dashboard != nil {
+		r.dashboard.Stop()
+	}
 }
 
 func (r *Run) run(ctx context.Context) {
@@ -247,43 +578,46 @@ func (r *Run) run(ctx context.Context) {
 	triggerCtx, triggerCancel := context.WithTimeout(ctx, duration-nextIterationWindow)
 	defer triggerCancel()
 
-	poolManager := workers.New(r.options.MaxIterations, r.activeScenario)
-	r.trigger.Trigger(triggerCtx, r.output, poolManager, r.options)
+	r.trigger.Trigger(triggerCtx, r.output, r.poolManager, r.options)
 
 	select {
 	case <-ctx.Done():
-		r.output.Display(r.result.Interrupted())
+		r.interrupted()
 		r.progressRunner.Restart()
 		select {
-		case <-poolManager.WaitForCompletion():
+		case <-r.poolManager.WaitForCompletion():
 		case <-time.After(r.options.WaitForCompletionTimeout):
-			r.output.Display(ui.WarningMessage{
-				Message: fmt.Sprintf("Active tests not completed after %s. Stopping...",
-					r.options.WaitForCompletionTimeout.String()),
-			})
+			r.stopActiveIterations()
 		}
 
 	case <-triggerCtx.Done():
 		if errors.Is(triggerCtx.Err(), context.DeadlineExceeded) {
 			r.output.Display(r.result.MaxDurationElapsed())
 		} else {
-			r.output.Display(r.result.Interrupted())
+			r.interrupted()
 		}
 		select {
-		case <-poolManager.WaitForCompletion():
+		case <-r.poolManager.WaitForCompletion():
 		case <-time.After(r.options.WaitForCompletionTimeout):
-			r.output.Display(ui.WarningMessage{
-				Message: fmt.Sprintf("Active tests not completed after %s. Stopping...",
-					r.options.WaitForCompletionTimeout.String()),
-			})
+			r.stopActiveIterations()
 		}
-	case <-poolManager.WaitForCompletion():
-		if poolManager.MaxIterationsReached() {
+	case <-r.poolManager.WaitForCompletion():
+		if r.poolManager.MaxIterationsReached() {
 			r.output.Display(r.result.MaxIterationsReached())
 		}
 	}
 }
 
+// stopActiveIterations interrupts the iterations still running after the wait for completion
+// timeout, so that the ones waiting on T.Context, such as commands run with --exec, stop.
+func (r *Run) stopActiveIterations() {
+	r.output.Display(ui.WarningMessage{
+		Message: fmt.Sprintf("Active tests not completed after %s. Stopping...",
+			r.options.WaitForCompletionTimeout.String()),
+	})
+	r.activeScenario.Interrupt()
+}
+
 func (r *Run) fail(message string) {
 	r.result.AddError(errors.New(message))
 }
@@ -4,6 +4,8 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/form3tech-oss/f1/v2/internal/callbacks"
+	"github.com/form3tech-oss/f1/v2/internal/data"
 	"github.com/form3tech-oss/f1/v2/internal/log"
 	"github.com/form3tech-oss/f1/v2/internal/progress"
 	"github.com/form3tech-oss/f1/v2/internal/ui"
@@ -29,6 +31,30 @@ const resultTemplate = `
 {{- if .DroppedIterationCount}}
 {bold}Dropped Iterations:{-} {yellow}{{.DroppedIterationCount}} ({{percent .DroppedIterationCount .Iterations | printf "%0.2f"}}%, {{rate .Duration .DroppedIterationCount}}){-} (consider increasing --concurrency setting)
 {{- end}}
+{{- if .WaitDurations.Count}}
+{bold}Waiting between iterations:{-} {{.WaitDurations}} (think time and pacing, not included in iteration durations)
+{{- end}}
+{{- if .DataUsage.Rows}}
+{bold}Data:{-} {{.DataUsage.Used}} of {{.DataUsage.Rows}} rows of {{.DataUsage.Path}} used ({{.DataUsage.Mode}}, {{percent .DataUsage.Used .DataUsage.Rows | printf "%0.2f"}}%)
+{{- end}}
+{{- if .Callbacks.Total}}
+{bold}Callbacks:{-} {{.Callbacks.Received}} received, {{.Callbacks.Missing}} never arrived, {{.Callbacks.Unexpected}} unexpected
+{{- end}}
+{{- if .Seed}}
+{bold}Seed:{-} {{.Seed}} (repeat the run's random choices with --seed {{.Seed}})
+{{- end}}
+{{- range .Tags}}
+{bold}Iterations by {{.Key}}:{-}
+{{- range .Values}}
+  {{.Value}}: {green}{{.Successful}} successful{-}, {red}{{.Failed}} failed{-}, avg: {{.Average}}
+{{- end}}
+{{- end}}
+{{- if .TopFailures}}
+{bold}Top failure causes:{-}
+{{- range .TopFailures}}
+  {red}{{.Count}}×{-} {{.Reason}} {light_black}(first seen in iteration {{.FirstIteration}}){-}
+{{- end}}
+{{- end}}
 {bold}Full logs:{-} {{.LogFilePath}}
 `
 
@@ -39,12 +65,18 @@ type ResultData struct {
 	LogFilePath                  string
 	SuccessfulIterationDurations progress.IterationDurationsSnapshot
 	FailedIterationDurations     progress.IterationDurationsSnapshot
+	WaitDurations                progress.IterationDurationsSnapshot
+	TopFailures                  []progress.FailureReason
+	Tags                         []progress.TagBreakdown
+	DataUsage                    data.Usage
+	Callbacks                    callbacks.Counts
 	IterationsStarted            uint64
 	Duration                     time.Duration
 	SuccessfulIterationCount     uint64
 	Iterations                   uint64
 	FailedIterationCount         uint64
 	DroppedIterationCount        uint64
+	Seed                         uint64
 	Failed                       bool
 }
 
@@ -57,14 +89,46 @@ func (d ResultData) Log(logger *slog.Logger) {
 		d.Duration,
 	)
 
+	attrs := []any{stats}
+	if d.Seed != 0 {
+		attrs = append(attrs, slog.Uint64("seed", d.Seed))
+	}
+	if len(d.TopFailures) > 0 {
+		attrs = append(attrs, slog.Any("top_failures", d.TopFailures))
+	}
+	if len(d.Tags) > 0 {
+		attrs = append(attrs, slog.Any("tags", d.Tags))
+	}
+	if d.WaitDurations.Count > 0 {
+		attrs = append(attrs, slog.Group("wait",
+			slog.Duration("avg", d.WaitDurations.Average),
+			slog.Duration("min", d.WaitDurations.Min),
+			slog.Duration("max", d.WaitDurations.Max),
+		))
+	}
+	if d.DataUsage.Rows > 0 {
+		attrs = append(attrs, slog.Group("data",
+			slog.String("path", d.DataUsage.Path),
+			slog.Uint64("rows", d.DataUsage.Rows),
+			slog.Uint64("used", d.DataUsage.Used),
+		))
+	}
+	if d.Callbacks.Total() > 0 {
+		attrs = append(attrs, slog.Group("callbacks",
+			slog.Uint64("received", d.Callbacks.Received),
+			slog.Uint64("missing", d.Callbacks.Missing),
+			slog.Uint64("unexpected", d.Callbacks.Unexpected),
+		))
+	}
+
 	if d.Failed {
 		if d.Error != nil {
-			logger.Error("Load Test Failed", log.ErrorAttr(d.Error), stats)
+			logger.Error("Load Test Failed", append([]any{log.ErrorAttr(d.Error)}, attrs...)...)
 		} else {
-			logger.Error("Load Test Failed", stats)
+			logger.Error("Load Test Failed", attrs...)
 		}
 	} else {
-		logger.Info("Load Test Passed", stats)
+		logger.Info("Load Test Passed", attrs...)
 	}
 }
 
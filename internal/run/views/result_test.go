@@ -8,6 +8,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 
+	"github.com/form3tech-oss/f1/v2/internal/callbacks"
+	"github.com/form3tech-oss/f1/v2/internal/data"
 	"github.com/form3tech-oss/f1/v2/internal/log"
 	"github.com/form3tech-oss/f1/v2/internal/progress"
 	"github.com/form3tech-oss/f1/v2/internal/run/views"
@@ -44,6 +46,12 @@ func Test_RenderResult(t *testing.T) {
 				},
 				DroppedIterationCount: 3,
 				LogFilePath:           "log/file/path.log",
+				TopFailures:           nil,
+				Tags:                  nil,
+				WaitDurations:         progress.IterationDurationsSnapshot{},
+				DataUsage:             data.Usage{},
+				Callbacks:             callbacks.Counts{},
+				Seed:                  0,
 			},
 			expected: "\nLoad Test Failed\n" +
 				"Error: errorMessage\n" +
@@ -82,6 +90,12 @@ func Test_RenderResult(t *testing.T) {
 				},
 				DroppedIterationCount: 3,
 				LogFilePath:           "log/file/path.log",
+				TopFailures:           nil,
+				Tags:                  nil,
+				WaitDurations:         progress.IterationDurationsSnapshot{},
+				DataUsage:             data.Usage{},
+				Callbacks:             callbacks.Counts{},
+				Seed:                  0,
 			},
 			expected: "\nLoad Test Failed\n" +
 				"20 iterations started in 1s (20/second)\n" +
@@ -114,6 +128,12 @@ func Test_RenderResult(t *testing.T) {
 				Error:                    nil,
 				FailedIterationCount:     0,
 				DroppedIterationCount:    0,
+				TopFailures:              nil,
+				Tags:                     nil,
+				WaitDurations:            progress.IterationDurationsSnapshot{},
+				DataUsage:                data.Usage{},
+				Callbacks:                callbacks.Counts{},
+				Seed:                     0,
 			},
 			expected: "\nLoad Test Passed\n" +
 				"20 iterations started in 1s (20/second)\n" +
@@ -144,6 +164,12 @@ func Test_RenderResult(t *testing.T) {
 				LogFilePath:              "log/file/path.log",
 				FailedIterationCount:     0,
 				Error:                    nil,
+				TopFailures:              nil,
+				Tags:                     nil,
+				WaitDurations:            progress.IterationDurationsSnapshot{},
+				DataUsage:                data.Usage{},
+				Callbacks:                callbacks.Counts{},
+				Seed:                     0,
 			},
 			expected: "\nLoad Test Passed\n" +
 				"20 iterations started in 1s (20/second)\n" +
@@ -157,6 +183,208 @@ func Test_RenderResult(t *testing.T) {
 				"iteration_stats.dropped=10 " +
 				"iteration_stats.period=1s\n",
 		},
+		{
+			name: "failed with top failure causes",
+			data: views.ResultData{
+				Failed:                       true,
+				Error:                        nil,
+				IterationsStarted:            10,
+				Duration:                     1 * time.Second,
+				SuccessfulIterationCount:     0,
+				Iterations:                   10,
+				SuccessfulIterationDurations: progress.IterationDurationsSnapshot{},
+				FailedIterationCount:         10,
+				FailedIterationDurations: progress.IterationDurationsSnapshot{
+					Min:     4 * time.Microsecond,
+					Average: 5 * time.Microsecond,
+					Max:     6 * time.Microsecond,
+				},
+				DroppedIterationCount: 0,
+				LogFilePath:           "log/file/path.log",
+				TopFailures: []progress.FailureReason{
+					{Reason: "connection refused", FirstIteration: "3", Count: 7},
+					{Reason: "timeout", FirstIteration: "0", Count: 3},
+				},
+				Tags:          nil,
+				WaitDurations: progress.IterationDurationsSnapshot{},
+				DataUsage:     data.Usage{},
+				Callbacks:     callbacks.Counts{},
+				Seed:          0,
+			},
+			expected: "\nLoad Test Failed\n" +
+				"10 iterations started in 1s (10/second)\n" +
+				"Failed Iterations: 10 (100.00%, 10) avg: 5µs, min: 4µs, max: 6µs\n" +
+				"Top failure causes:\n" +
+				"  7× connection refused (first seen in iteration 3)\n" +
+				"  3× timeout (first seen in iteration 0)\n" +
+				"Full logs: log/file/path.log\n",
+			expectedLog: "level=ERROR msg=\"Load Test Failed\" " +
+				"iteration_stats.started=10 " +
+				"iteration_stats.successful=0 " +
+				"iteration_stats.failed=10 " +
+				"iteration_stats.dropped=0 " +
+				"iteration_stats.period=1s " +
+				"top_failures=\"[{Reason:connection refused FirstIteration:3 Count:7} " +
+				"{Reason:timeout FirstIteration:0 Count:3}]\"\n",
+		},
+		{
+			name: "passed with waits and data usage",
+			data: views.ResultData{
+				Failed:                       false,
+				Error:                        nil,
+				IterationsStarted:            10,
+				Duration:                     1 * time.Second,
+				SuccessfulIterationCount:     10,
+				Iterations:                   10,
+				SuccessfulIterationDurations: progress.IterationDurationsSnapshot{},
+				FailedIterationCount:         0,
+				FailedIterationDurations:     progress.IterationDurationsSnapshot{},
+				DroppedIterationCount:        0,
+				LogFilePath:                  "log/file/path.log",
+				TopFailures:                  nil,
+				Tags:                         nil,
+				WaitDurations: progress.IterationDurationsSnapshot{
+					Average: 2 * time.Second,
+					Count:   10,
+					Min:     1 * time.Second,
+					Max:     3 * time.Second,
+				},
+				DataUsage: data.Usage{Path: "accounts.csv", Mode: data.Unique, Rows: 40, Used: 10},
+				Callbacks: callbacks.Counts{},
+				Seed:      0,
+			},
+			expected: "\nLoad Test Passed\n" +
+				"10 iterations started in 1s (10/second)\n" +
+				"Successful Iterations: 10 (100.00%, 10/second) avg: 0s, min: 0s, max: 0s\n" +
+				"Waiting between iterations: avg: 2s, min: 1s, max: 3s (think time and pacing, not included in iteration durations)\n" +
+				"Data: 10 of 40 rows of accounts.csv used (unique, 25.00%)\n" +
+				"Full logs: log/file/path.log\n",
+			expectedLog: "level=INFO msg=\"Load Test Passed\" " +
+				"iteration_stats.started=10 " +
+				"iteration_stats.successful=10 " +
+				"iteration_stats.failed=0 " +
+				"iteration_stats.dropped=0 " +
+				"iteration_stats.period=1s " +
+				"wait.avg=2s " +
+				"wait.min=1s " +
+				"wait.max=3s " +
+				"data.path=accounts.csv " +
+				"data.rows=40 " +
+				"data.used=10\n",
+		},
+		{
+			name: "passed with callbacks",
+			data: views.ResultData{
+				Failed:                       false,
+				Error:                        nil,
+				IterationsStarted:            10,
+				Duration:                     1 * time.Second,
+				SuccessfulIterationCount:     10,
+				Iterations:                   10,
+				SuccessfulIterationDurations: progress.IterationDurationsSnapshot{},
+				FailedIterationCount:         0,
+				FailedIterationDurations:     progress.IterationDurationsSnapshot{},
+				DroppedIterationCount:        0,
+				LogFilePath:                  "log/file/path.log",
+				TopFailures:                  nil,
+				Tags:                         nil,
+				WaitDurations:                progress.IterationDurationsSnapshot{},
+				DataUsage:                    data.Usage{},
+				Callbacks:                    callbacks.Counts{Received: 8, Missing: 2, Unexpected: 1},
+				Seed:                         0,
+			},
+			expected: "\nLoad Test Passed\n" +
+				"10 iterations started in 1s (10/second)\n" +
+				"Successful Iterations: 10 (100.00%, 10/second) avg: 0s, min: 0s, max: 0s\n" +
+				"Callbacks: 8 received, 2 never arrived, 1 unexpected\n" +
+				"Full logs: log/file/path.log\n",
+			expectedLog: "level=INFO msg=\"Load Test Passed\" " +
+				"iteration_stats.started=10 " +
+				"iteration_stats.successful=10 " +
+				"iteration_stats.failed=0 " +
+				"iteration_stats.dropped=0 " +
+				"iteration_stats.period=1s " +
+				"callbacks.received=8 " +
+				"callbacks.missing=2 " +
+				"callbacks.unexpected=1\n",
+		},
+		{
+			name: "passed with a seed",
+			data: views.ResultData{
+				Failed:                       false,
+				Error:                        nil,
+				IterationsStarted:            10,
+				Duration:                     1 * time.Second,
+				SuccessfulIterationCount:     10,
+				Iterations:                   10,
+				SuccessfulIterationDurations: progress.IterationDurationsSnapshot{},
+				FailedIterationCount:         0,
+				FailedIterationDurations:     progress.IterationDurationsSnapshot{},
+				DroppedIterationCount:        0,
+				LogFilePath:                  "log/file/path.log",
+				TopFailures:                  nil,
+				Tags:                         nil,
+				WaitDurations:                progress.IterationDurationsSnapshot{},
+				DataUsage:                    data.Usage{},
+				Callbacks:                    callbacks.Counts{},
+				Seed:                         42,
+			},
+			expected: "\nLoad Test Passed\n" +
+				"10 iterations started in 1s (10/second)\n" +
+				"Successful Iterations: 10 (100.00%, 10/second) avg: 0s, min: 0s, max: 0s\n" +
+				"Seed: 42 (repeat the run's random choices with --seed 42)\n" +
+				"Full logs: log/file/path.log\n",
+			expectedLog: "level=INFO msg=\"Load Test Passed\" " +
+				"iteration_stats.started=10 " +
+				"iteration_stats.successful=10 " +
+				"iteration_stats.failed=0 " +
+				"iteration_stats.dropped=0 " +
+				"iteration_stats.period=1s " +
+				"seed=42\n",
+		},
+		{
+			name: "passed with tags",
+			data: views.ResultData{
+				Failed:                       false,
+				Error:                        nil,
+				IterationsStarted:            10,
+				Duration:                     1 * time.Second,
+				SuccessfulIterationCount:     8,
+				Iterations:                   10,
+				SuccessfulIterationDurations: progress.IterationDurationsSnapshot{},
+				FailedIterationCount:         2,
+				FailedIterationDurations:     progress.IterationDurationsSnapshot{},
+				DroppedIterationCount:        0,
+				LogFilePath:                  "log/file/path.log",
+				TopFailures:                  nil,
+				Tags: []progress.TagBreakdown{
+					{Key: "tenant", Values: []progress.TagValue{
+						{Value: "acme", Average: 2 * time.Millisecond, Successful: 6, Failed: 1},
+						{Value: "globex", Average: 3 * time.Millisecond, Successful: 2, Failed: 1},
+					}},
+				},
+				WaitDurations: progress.IterationDurationsSnapshot{},
+				DataUsage:     data.Usage{},
+				Callbacks:     callbacks.Counts{},
+				Seed:          0,
+			},
+			expected: "\nLoad Test Passed\n" +
+				"10 iterations started in 1s (10/second)\n" +
+				"Successful Iterations: 8 (80.00%, 8/second) avg: 0s, min: 0s, max: 0s\n" +
+				"Failed Iterations: 2 (20.00%, 2) avg: 0s, min: 0s, max: 0s\n" +
+				"Iterations by tenant:\n" +
+				"  acme: 6 successful, 1 failed, avg: 2ms\n" +
+				"  globex: 2 successful, 1 failed, avg: 3ms\n" +
+				"Full logs: log/file/path.log\n",
+			expectedLog: "level=INFO msg=\"Load Test Passed\" " +
+				"iteration_stats.started=10 " +
+				"iteration_stats.successful=8 " +
+				"iteration_stats.failed=2 " +
+				"iteration_stats.dropped=0 " +
+				"iteration_stats.period=1s " +
+				"tags=\"[{Key:tenant Values:[{Value:acme Average:2ms Successful:6 Failed:1} " +
+				"{Value:globex Average:3ms Successful:2 Failed:1}]}]\"\n",
+		},
 	}
 
 	v := views.New()
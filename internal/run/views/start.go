@@ -11,6 +11,9 @@ import (
 //nolint:lll // templates read better with long lines
 const startTemplate = `{u}{bold}{intensive_blue}F1 Load Tester{-}
 Running {yellow}{{.Scenario}}{-} scenario for {{if .MaxIterations}}up to {{.MaxIterations}} iterations or up to {{end}}{{duration .MaxDuration}} at a rate of {{.RateDescription}}.
+{{- if .Seed}}
+Random choices are seeded with {{.Seed}}, repeat them with --seed {{.Seed}}.
+{{- end}}
 `
 
 var _ ui.Outputable = (*ViewContext[StartData])(nil)
@@ -20,6 +23,7 @@ type StartData struct {
 	RateDescription string
 	MaxIterations   uint64
 	MaxDuration     time.Duration
+	Seed            uint64
 }
 
 func (c StartData) Log(logger *slog.Logger) {
@@ -31,6 +35,11 @@ func (c StartData) Log(logger *slog.Logger) {
 	message += c.MaxDuration.String()
 	message += " at a rate of " + c.RateDescription
 
+	if c.Seed != 0 {
+		logger.Info(message, slog.Uint64("seed", c.Seed))
+		return
+	}
+
 	logger.Info(message)
 }
 
@@ -27,6 +27,7 @@ func Test_RenderStart(t *testing.T) {
 				MaxDuration:     1 * time.Minute,
 				RateDescription: "rate-description",
 				MaxIterations:   10,
+				Seed:            0,
 			},
 			expected: "F1 Load Tester\n" +
 				"Running scenarioName scenario for up to 10 iterations or up to 1m0s at a rate of rate-description.\n",
@@ -39,11 +40,26 @@ func Test_RenderStart(t *testing.T) {
 				MaxDuration:     1 * time.Minute,
 				RateDescription: "rate-description",
 				MaxIterations:   0,
+				Seed:            0,
 			},
 			expected: "F1 Load Tester\n" +
 				"Running scenarioName scenario for 1m0s at a rate of rate-description.\n",
 			expectedLog: "level=INFO msg=\"Running scenarioName for 1m0s at a rate of rate-description\"\n",
 		},
+		{
+			name: "with Seed",
+			data: views.StartData{
+				Scenario:        "scenarioName",
+				MaxDuration:     1 * time.Minute,
+				RateDescription: "rate-description",
+				MaxIterations:   0,
+				Seed:            42,
+			},
+			expected: "F1 Load Tester\n" +
+				"Running scenarioName scenario for 1m0s at a rate of rate-description.\n" +
+				"Random choices are seeded with 42, repeat them with --seed 42.\n",
+			expectedLog: "level=INFO msg=\"Running scenarioName for 1m0s at a rate of rate-description\" seed=42\n",
+		},
 	}
 
 	v := views.New()
@@ -0,0 +1,102 @@
+package simulate
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/events"
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+)
+
+// z99 is the standard score of the 99th percentile of a normal distribution
+const z99 = 2.3263478740408408
+
+// ParseLatency parses the latency of simulated iterations: a fixed duration such as "100ms", a
+// lognormal distribution with a median and a 99th percentile such as "lognormal:100ms,1s", or the
+// durations of the iterations in the events file of a previous run, such as
+// "histogram:events.ndjson". Random latencies are drawn from random, which must be safe for
+// concurrent use, see api.NewRand.
+func ParseLatency(value string, random *rand.Rand) (func() time.Duration, error) {
+	if filename, ok := strings.CutPrefix(value, "histogram:"); ok {
+		durations, err := readDurations(filename)
+		if err != nil {
+			return nil, err
+		}
+
+		return func() time.Duration {
+			return durations[random.IntN(len(durations))]
+		}, nil
+	}
+
+	if percentiles, ok := strings.CutPrefix(value, "lognormal:"); ok {
+		median, p99, ok := strings.Cut(percentiles, ",")
+		if !ok {
+			return nil, fmt.Errorf("lognormal latency %s needs a median and a 99th percentile", value)
+		}
+		medianDuration, err := parseLatencyDuration(median)
+		if err != nil {
+			return nil, err
+		}
+		p99Duration, err := parseLatencyDuration(p99)
+		if err != nil {
+			return nil, err
+		}
+		if medianDuration == 0 || p99Duration < medianDuration {
+			return nil, fmt.Errorf("lognormal latency %s needs a positive median below the 99th percentile", value)
+		}
+
+		sigma := math.Log(float64(p99Duration)/float64(medianDuration)) / z99
+		return func() time.Duration {
+			return time.Duration(float64(medianDuration) * math.Exp(sigma*random.NormFloat64()))
+		}, nil
+	}
+
+	fixed, err := parseLatencyDuration(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() time.Duration { return fixed }, nil
+}
+
+func parseLatencyDuration(value string) (time.Duration, error) {
+	duration, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("parsing latency: %w", err)
+	}
+	if duration < 0 {
+		return 0, fmt.Errorf("latency %s can't be negative", duration)
+	}
+
+	return duration, nil
+}
+
+// readDurations returns the durations of the iterations which ran in an events file.
+func readDurations(filename string) ([]time.Duration, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, fmt.Errorf("opening events file: %w", err)
+	}
+	defer file.Close()
+
+	var durations []time.Duration
+	err = events.Read(file, func(event events.Event) error {
+		if event.Result != metrics.DroppedResult.String() {
+			durations = append(durations, time.Duration(event.Duration))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading events file: %w", err)
+	}
+	if len(durations) == 0 {
+		return nil, errors.New("no iterations ran in events file " + filename)
+	}
+
+	return durations, nil
+}
@@ -0,0 +1,76 @@
+package simulate_test
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/events"
+	"github.com/form3tech-oss/f1/v2/internal/simulate"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
+)
+
+func TestParseLatency_Fixed(t *testing.T) {
+	t.Parallel()
+
+	latency, err := simulate.ParseLatency("150ms", api.NewRand(1))
+	require.NoError(t, err)
+
+	assert.Equal(t, 150*time.Millisecond, latency())
+}
+
+func TestParseLatency_LognormalHasTheMedianAndPercentile(t *testing.T) {
+	t.Parallel()
+
+	latency, err := simulate.ParseLatency("lognormal:100ms,1s", api.NewRand(1))
+	require.NoError(t, err)
+
+	samples := make([]time.Duration, 100000)
+	for i := range samples {
+		samples[i] = latency()
+	}
+	slices.Sort(samples)
+
+	assert.InEpsilon(t, float64(100*time.Millisecond), float64(samples[len(samples)/2]), 0.05)
+	assert.InEpsilon(t, float64(time.Second), float64(samples[len(samples)*99/100]), 0.1)
+}
+
+func TestParseLatency_HistogramSamplesTheIterationsOfAnEventsFile(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "events.ndjson")
+	writer, err := events.Create(path)
+	require.NoError(t, err)
+	writer.Record(events.Event{Result: "success", Duration: int64(10 * time.Millisecond)})
+	writer.Record(events.Event{Result: "fail", Duration: int64(30 * time.Millisecond)})
+	writer.Record(events.Event{Result: "dropped"})
+	require.NoError(t, writer.Close())
+
+	latency, err := simulate.ParseLatency("histogram:"+path, api.NewRand(1))
+	require.NoError(t, err)
+
+	for range 100 {
+		assert.Contains(t, []time.Duration{10 * time.Millisecond, 30 * time.Millisecond}, latency())
+	}
+}
+
+func TestParseLatency_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, value := range []string{
+		"",
+		"fast",
+		"-1s",
+		"lognormal:100ms",
+		"lognormal:1s,100ms",
+		"lognormal:0s,1s",
+		"histogram:" + filepath.Join(t.TempDir(), "missing.ndjson"),
+	} {
+		_, err := simulate.ParseLatency(value, api.NewRand(1))
+		assert.Error(t, err, value)
+	}
+}
@@ -0,0 +1,82 @@
+package simulate
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Report is the outcome of a simulation.
+type Report struct {
+	Timeline []Period
+	Duration time.Duration
+	// Triggered is 0 for trigger modes whose users start iterations as soon as they can
+	Triggered       uint64
+	Started         uint64
+	Dropped         uint64
+	Concurrency     int
+	PeakConcurrency int
+	// Seed is the seed of the random choices of the trigger mode and of the latencies
+	Seed uint64
+}
+
+// Period is the iterations of a period of the simulation, which are as long as the periods of
+// the progress of runs.
+type Period struct {
+	// Offset is the end of the period since the start of the simulation
+	Offset          time.Duration
+	Length          time.Duration
+	Triggered       uint64
+	Started         uint64
+	Dropped         uint64
+	PeakConcurrency int
+}
+
+// Rate returns the iterations started per second during the period.
+func (p Period) Rate() float64 {
+	if p.Length <= 0 {
+		return 0
+	}
+
+	return float64(p.Started) / p.Length.Seconds()
+}
+
+func peakConcurrency(timeline []Period) int {
+	peak := 0
+	for _, period := range timeline {
+		peak = max(peak, period.PeakConcurrency)
+	}
+
+	return peak
+}
+
+func (r *Report) Render() string {
+	var sb strings.Builder
+
+	if r.Triggered > 0 {
+		fmt.Fprintf(&sb, "%d iterations triggered in %s: %d started, %d dropped (%.1f%%)\n", r.Triggered,
+			r.Duration, r.Started, r.Dropped, float64(r.Dropped)*100/float64(r.Triggered))
+	} else {
+		fmt.Fprintf(&sb, "%d iterations started in %s\n", r.Started, r.Duration)
+	}
+	fmt.Fprintf(&sb, "Peak concurrency: %d of %d\n", r.PeakConcurrency, r.Concurrency)
+	if r.Dropped > 0 {
+		sb.WriteString("Iterations were dropped as all workers were busy, consider increasing `--concurrency`\n")
+	}
+
+	if len(r.Timeline) > 0 {
+		sb.WriteString("\nTimeline:\n")
+		w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintln(w, "time\ttriggered\tstarted\tdropped\trate\tpeak concurrency\t")
+		for _, p := range r.Timeline {
+			fmt.Fprintf(w, "%s\t%d\t%d\t%d\t%.1f/s\t%d\t\n",
+				p.Offset, p.Triggered, p.Started, p.Dropped, p.Rate(), p.PeakConcurrency)
+		}
+		_ = w.Flush()
+	}
+
+	fmt.Fprintf(&sb, "\nSeed: %d (repeat the simulation's random choices with --seed %d)\n", r.Seed, r.Seed)
+
+	return sb.String()
+}
@@ -0,0 +1,106 @@
+package simulate
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/form3tech-oss/f1/v2/internal/options"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
+	"github.com/form3tech-oss/f1/v2/internal/triggerflags"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+)
+
+const flagLatency = "latency"
+
+func Cmd(builders []api.Builder, output *ui.Output) *cobra.Command {
+	simulateCmd := &cobra.Command{
+		Use:   "simulate <subcommand>",
+		Short: "Predicts the dropped iterations and the concurrency of a run, with a simulated latency",
+	}
+
+	for _, t := range builders {
+		triggerCmd := &cobra.Command{
+			Use:   t.Name,
+			Short: t.Description,
+			RunE:  simulateCmdExecute(t, output),
+		}
+		triggerCmd.Flags().String(flagLatency, "",
+			"--latency 100ms (iterations take 100ms), --latency lognormal:100ms,1s (a median of 100ms and a p99 of 1s) "+
+				"or --latency histogram:events.ndjson (the durations of the iterations in an --events-file of a run)")
+		_ = triggerCmd.MarkFlagRequired(flagLatency)
+		triggerflags.SeedFlag(triggerCmd.Flags())
+		if !t.IgnoreCommonFlags {
+			triggerCmd.Flags().DurationP(triggerflags.FlagMaxDuration, "d", time.Second,
+				"--max-duration 1s (stop after 1 second)")
+			triggerCmd.Flags().IntP(triggerflags.FlagConcurrency, "c", 100,
+				"--concurrency 2 (allow at most 2 groups of iterations to run concurrently)")
+			triggerCmd.Flags().Uint64P(triggerflags.FlagMaxIterations, "i", 0,
+				"--max-iterations 100 (stop after 100 iterations, regardless of remaining duration)")
+		}
+		triggerCmd.Flags().AddFlagSet(t.Flags)
+		simulateCmd.AddCommand(triggerCmd)
+	}
+
+	return simulateCmd
+}
+
+func simulateCmdExecute(
+	t api.Builder,
+	output *ui.Output,
+) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, _ []string) error {
+		cmd.SilenceUsage = true
+
+		// the seed is resolved first, so that the trigger mode makes its random choices with it
+		seed, err := triggerflags.ResolveSeed(cmd.Flags())
+		if err != nil {
+			return fmt.Errorf("resolving seed: %w", err)
+		}
+
+		trig, err := t.New(cmd.Flags())
+		if err != nil {
+			return fmt.Errorf("creating trigger command: %w", err)
+		}
+
+		runOptions := options.RunOptions{
+			MaxDuration:   trig.Options.MaxDuration,
+			Concurrency:   trig.Options.Concurrency,
+			MaxIterations: trig.Options.MaxIterations,
+			Seed:          seed,
+		}
+		if !t.IgnoreCommonFlags {
+			runOptions.MaxDuration, err = cmd.Flags().GetDuration(triggerflags.FlagMaxDuration)
+			if err != nil {
+				return fmt.Errorf("getting flag: %w", err)
+			}
+			runOptions.Concurrency, err = cmd.Flags().GetInt(triggerflags.FlagConcurrency)
+			if err != nil {
+				return fmt.Errorf("getting flag: %w", err)
+			}
+			runOptions.MaxIterations, err = cmd.Flags().GetUint64(triggerflags.FlagMaxIterations)
+			if err != nil {
+				return fmt.Errorf("getting flag: %w", err)
+			}
+		}
+
+		latencyValue, err := cmd.Flags().GetString(flagLatency)
+		if err != nil {
+			return fmt.Errorf("getting flag: %w", err)
+		}
+		// the latencies are drawn from their own sequence, rather than the one of the trigger mode
+		latency, err := ParseLatency(latencyValue, api.NewRand(seed+1))
+		if err != nil {
+			return fmt.Errorf("parsing --%s: %w", flagLatency, err)
+		}
+
+		report, err := Simulate(trig, runOptions, latency, time.Now())
+		if err != nil {
+			return err
+		}
+
+		output.Printer.Println(report.Render())
+		return nil
+	}
+}
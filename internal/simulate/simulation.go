@@ -0,0 +1,164 @@
+package simulate
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/form3tech-oss/f1/v2/internal/clock"
+	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+	"github.com/form3tech-oss/f1/v2/internal/options"
+	"github.com/form3tech-oss/f1/v2/internal/params"
+	"github.com/form3tech-oss/f1/v2/internal/progress"
+	"github.com/form3tech-oss/f1/v2/internal/raterun"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+	"github.com/form3tech-oss/f1/v2/internal/workers"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+// nextIterationWindow stops the trigger slightly before the end of the duration, as runs do
+const nextIterationWindow = 10 * time.Millisecond
+
+// simulation counts the iterations of a scenario which waits for a latency on a virtual clock.
+type simulation struct {
+	start    time.Time
+	clock    *clock.Virtual
+	pool     *workers.PoolManager
+	stats    *progress.Stats
+	latency  func() time.Duration
+	timeline []Period
+	// last is the state of the simulation at the end of the last period
+	last        Period
+	started     atomic.Uint64
+	concurrency atomic.Int64
+	// peak is the peak concurrency of the current period
+	peak atomic.Int64
+}
+
+// Simulate runs trig on a virtual clock starting at start, with the run options, as far as they
+// apply, against a scenario whose iterations take the time returned by latency. The latency must
+// be safe for concurrent use.
+func Simulate(
+	trig *api.Trigger,
+	runOptions options.RunOptions,
+	latency func() time.Duration,
+	start time.Time,
+) (*Report, error) {
+	duration := runOptions.MaxDuration
+	if trig.Duration > 0 && trig.Duration < runOptions.MaxDuration {
+		duration = trig.Duration
+	}
+
+	s := &simulation{
+		start:   start,
+		clock:   clock.NewVirtual(start),
+		stats:   &progress.Stats{},
+		latency: latency,
+	}
+
+	scenarioParams, err := params.New(nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("scenario params: %w", err)
+	}
+	logger := log.NewDiscardLogger()
+	activeScenario := workers.NewActiveScenario(
+		&scenarios.Scenario{
+			Name:       "simulation",
+			ScenarioFn: s.scenario,
+		},
+		metrics.NewInstance(prometheus.NewRegistry(), false, nil),
+		s.stats,
+		logger,
+		log.NewSlogLogrusLogger(logger),
+		workers.ActiveScenarioOptions{Params: scenarioParams, Seed: runOptions.Seed},
+	)
+	activeScenario.Setup()
+	defer activeScenario.Teardown()
+	s.pool = workers.New(runOptions.MaxIterations, activeScenario, s.clock)
+
+	progressRunner, err := raterun.New(s.record, raterun.ProgressSchedules(), s.clock)
+	if err != nil {
+		return nil, fmt.Errorf("new progress runner: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	progressRunner.Start(ctx)
+
+	// the simulation lasts for the duration, even if the trigger stops early
+	endCtx, end := s.clock.WithTimeout(ctx, duration)
+	defer end()
+	triggerCtx, triggerCancel := s.clock.WithTimeout(endCtx, duration-nextIterationWindow)
+	defer triggerCancel()
+
+	triggered := make(chan struct{})
+	s.clock.Go(func() {
+		defer close(triggered)
+		trig.Trigger(triggerCtx, ui.NewDiscardOutput(), s.pool, runOptions)
+	})
+	s.clock.Run(endCtx.Done())
+
+	// the iterations still running at the end of the simulation are interrupted
+	s.record(0)
+	cancel()
+	activeScenario.Interrupt()
+	<-triggered
+	<-s.pool.WaitForCompletion()
+
+	return &Report{
+		Timeline:        s.timeline,
+		Duration:        duration,
+		Triggered:       s.last.Triggered,
+		Started:         s.last.Started,
+		Dropped:         s.last.Dropped,
+		Concurrency:     runOptions.Concurrency,
+		PeakConcurrency: peakConcurrency(s.timeline),
+		Seed:            runOptions.Seed,
+	}, nil
+}
+
+func (s *simulation) scenario(*testing.T) testing.RunFn {
+	return func(t *testing.T) {
+		s.started.Add(1)
+		concurrency := s.concurrency.Add(1)
+		for peak := s.peak.Load(); concurrency > peak && !s.peak.CompareAndSwap(peak, concurrency); {
+			peak = s.peak.Load()
+		}
+
+		s.clock.Sleep(t.Context(), s.latency())
+		s.concurrency.Add(-1)
+	}
+}
+
+// record adds the period since the last one to the timeline.
+func (s *simulation) record(time.Duration) {
+	offset := s.clock.Since(s.start)
+	if offset == s.last.Offset {
+		return
+	}
+
+	now := Period{
+		Offset:          offset,
+		Length:          0,
+		Triggered:       s.pool.TriggeredIterations(),
+		Started:         s.started.Load(),
+		Dropped:         s.stats.Total().DroppedIterationCount,
+		PeakConcurrency: int(s.peak.Swap(s.concurrency.Load())),
+	}
+
+	s.timeline = append(s.timeline, Period{
+		Offset:          now.Offset,
+		Length:          now.Offset - s.last.Offset,
+		Triggered:       now.Triggered - s.last.Triggered,
+		Started:         now.Started - s.last.Started,
+		Dropped:         now.Dropped - s.last.Dropped,
+		PeakConcurrency: now.PeakConcurrency,
+	})
+	s.last = now
+}
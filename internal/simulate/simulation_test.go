@@ -0,0 +1,121 @@
+package simulate_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/options"
+	"github.com/form3tech-oss/f1/v2/internal/simulate"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/users"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func fixedLatency(latency time.Duration) func() time.Duration {
+	return func() time.Duration { return latency }
+}
+
+func TestSimulate_PredictsDroppedIterationsOfAConstantRate(t *testing.T) {
+	t.Parallel()
+
+	trig := &api.Trigger{
+		Trigger: api.NewIterationWorker(10*time.Millisecond, func(time.Time) int { return 1 }),
+	}
+	runOptions := options.RunOptions{MaxDuration: 3 * time.Second, Concurrency: 20, Seed: 1}
+
+	report, err := simulate.Simulate(trig, runOptions, fixedLatency(250*time.Millisecond), start)
+	require.NoError(t, err)
+
+	// every 250ms, the 20 workers start 20 of the 25 iterations triggered, until the trigger
+	// stops in the last 10ms
+	assert.Equal(t, uint64(299), report.Triggered)
+	assert.Equal(t, uint64(240), report.Started)
+	assert.Equal(t, uint64(59), report.Dropped)
+	assert.Equal(t, 20, report.PeakConcurrency)
+	assert.Equal(t, 3*time.Second, report.Duration)
+	require.Len(t, report.Timeline, 3)
+	for i, period := range report.Timeline {
+		assert.Equal(t, time.Duration(i+1)*time.Second, period.Offset)
+		assert.InDelta(t, 80.0, period.Rate(), 0.001)
+	}
+	assert.Contains(t, report.Render(), "299 iterations triggered in 3s: 240 started, 59 dropped (19.7%)")
+}
+
+func TestSimulate_DoesNotDropIterationsWithEnoughConcurrency(t *testing.T) {
+	t.Parallel()
+
+	trig := &api.Trigger{
+		Trigger: api.NewIterationWorker(100*time.Millisecond, func(time.Time) int { return 2 }),
+	}
+	runOptions := options.RunOptions{MaxDuration: 2 * time.Second, Concurrency: 20, Seed: 1}
+
+	report, err := simulate.Simulate(trig, runOptions, fixedLatency(time.Second), start)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(40), report.Triggered)
+	assert.Equal(t, uint64(40), report.Started)
+	assert.Zero(t, report.Dropped)
+	assert.Equal(t, 20, report.PeakConcurrency)
+	assert.NotContains(t, report.Render(), "consider increasing")
+}
+
+func TestSimulate_StopsStartingIterationsAfterMaxIterations(t *testing.T) {
+	t.Parallel()
+
+	trig := &api.Trigger{
+		Trigger: api.NewIterationWorker(100*time.Millisecond, func(time.Time) int { return 1 }),
+	}
+	runOptions := options.RunOptions{MaxDuration: 3 * time.Second, Concurrency: 10, MaxIterations: 15, Seed: 1}
+
+	report, err := simulate.Simulate(trig, runOptions, fixedLatency(10*time.Millisecond), start)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint64(15), report.Started)
+	require.Len(t, report.Timeline, 3)
+	assert.Zero(t, report.Timeline[2].Started)
+}
+
+func TestSimulate_RunsUsersBackToBack(t *testing.T) {
+	t.Parallel()
+
+	builder := users.Rate()
+	trig, err := builder.New(builder.Flags)
+	require.NoError(t, err)
+	runOptions := options.RunOptions{MaxDuration: 2 * time.Second, Concurrency: 5, Seed: 1}
+
+	report, err := simulate.Simulate(trig, runOptions, fixedLatency(200*time.Millisecond), start)
+	require.NoError(t, err)
+
+	// 5 users complete an iteration every 200ms, until the trigger stops in the last 10ms
+	assert.Zero(t, report.Triggered)
+	assert.Equal(t, uint64(50), report.Started)
+	assert.Zero(t, report.Dropped)
+	assert.Equal(t, 5, report.PeakConcurrency)
+	assert.Contains(t, report.Render(), "50 iterations started in 2s")
+}
+
+func TestSimulate_IsRepeatableWithTheSameLatencies(t *testing.T) {
+	t.Parallel()
+
+	simulateWithSeed := func(seed uint64) *simulate.Report {
+		latency, err := simulate.ParseLatency("lognormal:100ms,2s", api.NewRand(seed))
+		require.NoError(t, err)
+		trig := &api.Trigger{
+			Trigger: api.NewIterationWorker(10*time.Millisecond, func(time.Time) int { return 1 }),
+		}
+		runOptions := options.RunOptions{MaxDuration: 5 * time.Second, Concurrency: 20, Seed: seed}
+
+		report, err := simulate.Simulate(trig, runOptions, latency, start)
+		require.NoError(t, err)
+		return report
+	}
+
+	first := simulateWithSeed(7)
+	assert.Equal(t, first, simulateWithSeed(7))
+	assert.Positive(t, first.Dropped)
+	assert.NotEqual(t, first.Timeline, simulateWithSeed(8).Timeline)
+}
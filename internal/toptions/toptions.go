@@ -0,0 +1,35 @@
+// Package toptions builds the options of testing.T which take types internal to f1, such as the
+// resources of a run, so that they aren't part of its public API.
+package toptions
+
+import (
+	"github.com/form3tech-oss/f1/v2/internal/callbacks"
+	"github.com/form3tech-oss/f1/v2/internal/data"
+	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+	"github.com/form3tech-oss/f1/v2/internal/params"
+	"github.com/form3tech-oss/f1/v2/internal/queues"
+)
+
+// Options are the resources of a run used by a testing.T. Fields left nil aren't set.
+type Options struct {
+	// FailedIterationLogs buffers the logs of each iteration in memory, and only writes them when
+	// the iteration fails. The limit is shared between all Ts of a run.
+	FailedIterationLogs *log.FlushLimit
+	// Params are the scenario parameter values returned by T.Param.
+	Params *params.Params
+	// Metrics are the metrics of the run, which record the durations measured with T.Time.
+	Metrics *metrics.Metrics
+	// Feeder feeds the rows returned by T.Data.
+	Feeder *data.Feeder
+	// Listener receives the callbacks awaited with T.AwaitCallback.
+	Listener *callbacks.Listener
+	// Queues are the queues returned by T.Queue, by name.
+	Queues map[string]queues.Binding
+}
+
+// New returns a testing.TOption setting options. It is set by package testing, which imports this
+// package, so the option can't be returned with its type.
+//
+//nolint:gochecknoglobals // the hook through which package testing builds its internal options
+var New func(options Options) any
@@ -39,6 +39,9 @@ type Trigger struct {
 	Description string
 	Options     Options
 	Duration    time.Duration
+	// StageParams are the parameter values set by each stage, checked against the parameters
+	// declared by the scenario when the run is created.
+	StageParams []map[string]string
 }
 
 type Options struct {
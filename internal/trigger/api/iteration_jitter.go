@@ -2,18 +2,17 @@ package api
 
 import (
 	"math"
-	"math/rand"
+	"math/rand/v2"
 	"time"
 )
 
-func WithJitter(rate RateFunction, multiple float64) RateFunction {
+func WithJitter(rate RateFunction, multiple float64, random *rand.Rand) RateFunction {
 	balance := 0.0
 	if multiple == 0 {
 		return rate
 	}
 	return func(now time.Time) int {
-		//nolint:gosec // G404: Use of weak random number generator - doesn't need to be secure
-		variationFactor := 1 + (math.Cos(rand.Float64()*2*math.Pi))*multiple/100
+		variationFactor := 1 + (math.Cos(random.Float64()*2*math.Pi))*multiple/100
 		requestedRate := float64(rate(now)) + balance
 		proposed := requestedRate * variationFactor
 		rounded := math.Max(0, math.Round(proposed))
@@ -12,7 +12,8 @@ import (
 // NewIterationWorker produces a WorkTriggerer which triggers work at fixed intervals.
 func NewIterationWorker(iterationDuration time.Duration, rate RateFunction) WorkTriggerer {
 	return func(ctx context.Context, _ *ui.Output, workers *workers.PoolManager, opts options.RunOptions) {
-		startRate := rate(time.Now())
+		clock := workers.Clock()
+		startRate := rate(clock.Now())
 
 		pool := workers.NewTriggerPool(opts.Concurrency)
 		workerCtx := pool.Start(ctx)
@@ -20,18 +21,17 @@ func NewIterationWorker(iterationDuration time.Duration, rate RateFunction) Work
 		pool.Trigger(workerCtx, startRate)
 
 		// start ticker to trigger subsequent iterations.
-		iterationTicker := time.NewTicker(iterationDuration)
+		iterationTicker := clock.NewTicker(iterationDuration)
 		defer iterationTicker.Stop()
 
 		// run more iterations on every tick, until duration has elapsed.
 		for {
-			select {
-			case <-workerCtx.Done():
+			start, ok := iterationTicker.Wait(workerCtx)
+			if !ok {
 				return
-			case start := <-iterationTicker.C:
-				iterationRate := rate(start)
-				pool.Trigger(workerCtx, iterationRate)
 			}
+			iterationRate := rate(start)
+			pool.Trigger(workerCtx, iterationRate)
 		}
 	}
 }
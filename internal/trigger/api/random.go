@@ -0,0 +1,26 @@
+package api
+
+import (
+	"math/rand/v2"
+	"sync"
+)
+
+// NewRand returns a random number generator seeded with seed, which is safe for concurrent use, so
+// that the random choices of trigger modes, such as jitter and random distribution, are repeated
+// by runs with the same seed.
+func NewRand(seed uint64) *rand.Rand {
+	//nolint:gosec // G404: Use of weak random number generator - doesn't need to be secure
+	return rand.New(&lockedSource{source: rand.NewPCG(seed, seed)})
+}
+
+type lockedSource struct {
+	source rand.Source
+	mu     sync.Mutex
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.source.Uint64()
+}
@@ -2,6 +2,7 @@ package constant
 
 import (
 	"fmt"
+	"math/rand/v2"
 	"time"
 
 	"github.com/spf13/pflag"
@@ -41,7 +42,12 @@ func Rate() api.Builder {
 				return nil, fmt.Errorf("getting flag: %w", err)
 			}
 
-			rates, err := CalculateConstantRate(jitterArg, rateArg, distributionTypeArg)
+			random, err := triggerflags.Rand(params)
+			if err != nil {
+				return nil, fmt.Errorf("seeding trigger: %w", err)
+			}
+
+			rates, err := CalculateConstantRate(jitterArg, rateArg, distributionTypeArg, random)
 			if err != nil {
 				return nil, fmt.Errorf("calculating constant rate: %w", err)
 			}
@@ -56,15 +62,19 @@ func Rate() api.Builder {
 	}
 }
 
-func CalculateConstantRate(jitterArg float64, rateArg, distributionTypeArg string) (*api.Rates, error) {
+func CalculateConstantRate(
+	jitterArg float64,
+	rateArg, distributionTypeArg string,
+	random *rand.Rand,
+) (*api.Rates, error) {
 	rate, iterationDuration, err := rate.ParseRate(rateArg)
 	if err != nil {
 		return nil, fmt.Errorf("unable to parse rate %s: %w", rateArg, err)
 	}
 
-	rateFn := api.WithJitter(func(time.Time) int { return rate }, jitterArg)
+	rateFn := api.WithJitter(func(time.Time) int { return rate }, jitterArg, random)
 	distributedIterationDuration, distributedRateFn, err := api.NewDistribution(
-		api.DistributionType(distributionTypeArg), iterationDuration, rateFn, nil,
+		api.DistributionType(distributionTypeArg), iterationDuration, rateFn, random.IntN,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("new distribution: %w", err)
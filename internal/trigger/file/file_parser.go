@@ -3,6 +3,7 @@ package file
 import (
 	"errors"
 	"fmt"
+	"math/rand/v2"
 	"time"
 
 	"gopkg.in/yaml.v3"
@@ -11,6 +12,7 @@ import (
 	"github.com/form3tech-oss/f1/v2/internal/trigger/gaussian"
 	"github.com/form3tech-oss/f1/v2/internal/trigger/ramp"
 	"github.com/form3tech-oss/f1/v2/internal/trigger/staged"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/users"
 )
 
 type ConfigFile struct {
@@ -52,9 +54,12 @@ type Stage struct {
 	Peak               *time.Duration     `yaml:"peak"`
 	StandardDeviation  *time.Duration     `yaml:"standard-deviation"`
 	Parameters         *map[string]string `yaml:"parameters"`
+	ThinkTime          *string            `yaml:"think-time"`
+	Pacing             *time.Duration     `yaml:"pacing"`
 }
 
-func ParseConfigFile(fileContent []byte, now time.Time) (*RunnableStages, error) {
+// ParseConfigFile parses the stages of a config file, drawing their random choices from random.
+func ParseConfigFile(fileContent []byte, now time.Time, random *rand.Rand) (*RunnableStages, error) {
 	configFile := ConfigFile{}
 	err := yaml.Unmarshal(fileContent, &configFile)
 	if err != nil {
@@ -76,7 +81,7 @@ func ParseConfigFile(fileContent []byte, now time.Time) (*RunnableStages, error)
 
 		stageStart := validatedConfigFile.Schedule.StageStart
 		if stageStart == nil || stageStart.Add(stagesTotalDuration).After(now) {
-			parsedStage, err := validatedStage.parseStage(idx, validatedConfigFile.Default)
+			parsedStage, err := validatedStage.parseStage(idx, validatedConfigFile.Default, random)
 			if err != nil {
 				return nil, err
 			}
@@ -98,7 +103,7 @@ func ParseConfigFile(fileContent []byte, now time.Time) (*RunnableStages, error)
 	}, nil
 }
 
-func (s *Stage) parseStage(stageIdx int, defaults Stage) (*runnableStage, error) {
+func (s *Stage) parseStage(stageIdx int, defaults Stage, random *rand.Rand) (*runnableStage, error) {
 	switch *s.Mode {
 	case "constant":
 		validatedConstantStage, err := s.validateConstantStage(stageIdx, defaults)
@@ -109,12 +114,14 @@ func (s *Stage) parseStage(stageIdx int, defaults Stage) (*runnableStage, error)
 			*validatedConstantStage.Jitter,
 			*validatedConstantStage.Rate,
 			*validatedConstantStage.Distribution,
+			random,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("calculating constant rate: %w", err)
 		}
 
 		return &runnableStage{
+			Mode:              *s.Mode,
 			StageDuration:     *validatedConstantStage.Duration,
 			IterationDuration: rates.IterationDuration,
 			Rate:              rates.Rate,
@@ -131,12 +138,14 @@ func (s *Stage) parseStage(stageIdx int, defaults Stage) (*runnableStage, error)
 			*validatedRampStage.Distribution,
 			*validatedRampStage.Duration,
 			*validatedRampStage.Jitter,
+			random,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("calculating ramp rate: %w", err)
 		}
 
 		return &runnableStage{
+			Mode:              *s.Mode,
 			StageDuration:     *validatedRampStage.Duration,
 			IterationDuration: rates.IterationDuration,
 			Rate:              rates.Rate,
@@ -153,12 +162,14 @@ func (s *Stage) parseStage(stageIdx int, defaults Stage) (*runnableStage, error)
 			*validatedStagedStage.Stages,
 			*validatedStagedStage.Distribution,
 			nil,
+			random,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("calculating staged rate: %w", err)
 		}
 
 		return &runnableStage{
+			Mode:              *s.Mode,
 			StageDuration:     *validatedStagedStage.Duration,
 			IterationDuration: rates.IterationDuration,
 			Rate:              rates.Rate,
@@ -172,13 +183,14 @@ func (s *Stage) parseStage(stageIdx int, defaults Stage) (*runnableStage, error)
 		rates, err := gaussian.CalculateGaussianRate(
 			*validatedGaussianStage.Volume, *validatedGaussianStage.Jitter, *validatedGaussianStage.Repeat,
 			*validatedGaussianStage.IterationFrequency, *validatedGaussianStage.Peak, *validatedGaussianStage.StandardDeviation,
-			*validatedGaussianStage.Weights, *validatedGaussianStage.Distribution,
+			*validatedGaussianStage.Weights, *validatedGaussianStage.Distribution, random,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("calculating gaussian rate: %w", err)
 		}
 
 		return &runnableStage{
+			Mode:              *s.Mode,
 			StageDuration:     *validatedGaussianStage.Duration,
 			IterationDuration: rates.IterationDuration,
 			Rate:              rates.Rate,
@@ -189,10 +201,16 @@ func (s *Stage) parseStage(stageIdx int, defaults Stage) (*runnableStage, error)
 		if err != nil {
 			return nil, err
 		}
+		pacing, err := users.NewPacing(*validatedUsersStage.ThinkTime, *validatedUsersStage.Pacing, random)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pacing at stage %d: %w", stageIdx, err)
+		}
 		return &runnableStage{
+			Mode:             *s.Mode,
 			StageDuration:    *validatedUsersStage.Duration,
 			Params:           *validatedUsersStage.Parameters,
 			UsersConcurrency: *validatedUsersStage.Concurrency,
+			UsersPacing:      pacing,
 		}, nil
 	default:
 		return nil, fmt.Errorf("invalid stage mode at stage %d", stageIdx)
@@ -413,6 +431,20 @@ func (s *Stage) validateUsersStage(idx int, defaults Stage) (*Stage, error) {
 
 		s.Concurrency = defaults.Concurrency
 	}
+	if s.ThinkTime == nil {
+		if defaults.ThinkTime == nil {
+			s.ThinkTime = new(string)
+		} else {
+			s.ThinkTime = defaults.ThinkTime
+		}
+	}
+	if s.Pacing == nil {
+		if defaults.Pacing == nil {
+			s.Pacing = new(time.Duration)
+		} else {
+			s.Pacing = defaults.Pacing
+		}
+	}
 	if s.Parameters == nil {
 		if defaults.Parameters == nil {
 			s.Parameters = &map[string]string{}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
 	"github.com/form3tech-oss/f1/v2/internal/trigger/file"
 )
 
@@ -161,6 +162,34 @@ stages:
 			expectedUsersConcurrency: 100,
 			expectedParameters:       map[string]string{"FOO": "bar"},
 		},
+		{
+			testName: "Users mode with think time and pacing",
+			fileContent: `
+scenario: template
+default:
+  think-time: 2s
+limits:
+  max-duration: 1m
+  concurrency: 50
+  max-iterations: 100
+  ignore-dropped: true
+stages:
+- duration: 10s
+  mode: users
+  concurrency: 10
+  pacing: 5s
+`,
+			expectedScenario:         "template",
+			expectedMaxDuration:      1 * time.Minute,
+			expectedConcurrency:      50,
+			expectedMaxIterations:    100,
+			expectedIgnoreDropped:    true,
+			expectedTotalDuration:    10 * time.Second,
+			expectedUsersConcurrency: 10,
+			expectedUsersThinkTime:   2 * time.Second,
+			expectedUsersPacing:      5 * time.Second,
+			expectedParameters:       map[string]string{},
+		},
 		{
 			testName: "Constant mode using default values",
 			fileContent: `
@@ -417,7 +446,7 @@ stages:
 
 			now, _ := time.Parse(time.RFC3339, "2020-12-10T10:00:00+00:00")
 
-			stagesToRun, err := file.ParseConfigFile([]byte(test.fileContent), now)
+			stagesToRun, err := file.ParseConfigFile([]byte(test.fileContent), now, api.NewRand(1))
 
 			require.NoError(t, err)
 			require.Len(t, stagesToRun.Stages, 1)
@@ -430,6 +459,12 @@ stages:
 			require.Equal(t, test.expectedIterationDuration, stagesToRun.Stages[0].IterationDuration)
 			require.Equal(t, test.expectedParameters, stagesToRun.Stages[0].Params)
 			require.Equal(t, test.expectedUsersConcurrency, stagesToRun.Stages[0].UsersConcurrency)
+			require.Equal(t, test.expectedUsersPacing, stagesToRun.Stages[0].UsersPacing.Cycle)
+			if test.expectedUsersThinkTime > 0 {
+				require.Equal(t, test.expectedUsersThinkTime, stagesToRun.Stages[0].UsersPacing.ThinkTime())
+			} else {
+				require.Nil(t, stagesToRun.Stages[0].UsersPacing.ThinkTime)
+			}
 
 			if len(test.expectedRates) > 0 {
 				rates := make([]int, 0, len(test.expectedRates))
@@ -674,6 +709,22 @@ stages:
 		},
 		{
 			fileContent: `
+scenario: template
+limits:
+  max-duration: 1m
+  concurrency: 50
+  max-iterations: 100
+  ignore-dropped: true
+stages:
+- duration: 10s
+  mode: users
+  concurrency: 10
+  think-time: 3s-1s
+`,
+			expectedError: "invalid pacing at stage 0: think time range 3s-1s ends before it starts",
+		},
+		{
+			fileContent: `
 invalid file content
 `,
 			expectedError: "yaml: unmarshal errors:\n  line 2: cannot unmarshal !!str `invalid...` into file.ConfigFile",
@@ -684,7 +735,7 @@ invalid file content
 
 			now, _ := time.Parse(time.RFC3339, "2020-12-10T10:00:00+00:00")
 
-			runnableStages, err := file.ParseConfigFile([]byte(test.fileContent), now)
+			runnableStages, err := file.ParseConfigFile([]byte(test.fileContent), now, api.NewRand(1))
 
 			require.Nil(t, runnableStages)
 			require.ErrorContains(t, err, test.expectedError)
@@ -706,6 +757,8 @@ type testData struct {
 	expectedMaxFailuresRate          int
 	expectedConcurrency              int
 	expectedUsersConcurrency         int
+	expectedUsersThinkTime           time.Duration
+	expectedUsersPacing              time.Duration
 	expectedRates                    []int
 	expectedParameters               map[string]string
 }
@@ -10,7 +10,9 @@ import (
 	"github.com/spf13/pflag"
 
 	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
+	"github.com/form3tech-oss/f1/v2/internal/triggerflags"
 	"github.com/form3tech-oss/f1/v2/internal/ui"
+	"github.com/form3tech-oss/f1/v2/internal/workers"
 )
 
 type RunnableStages struct {
@@ -28,10 +30,12 @@ type RunnableStages struct {
 
 type runnableStage struct {
 	Rate              api.RateFunction
+	Mode              string
 	Params            map[string]string
 	StageDuration     time.Duration
 	IterationDuration time.Duration
 	UsersConcurrency  int
+	UsersPacing       workers.Pacing
 }
 
 func Rate(output *ui.Output) api.Builder {
@@ -47,7 +51,11 @@ func Rate(output *ui.Output) api.Builder {
 			if err != nil {
 				return nil, err
 			}
-			runnableStages, err := ParseConfigFile(*fileContent, time.Now())
+			random, err := triggerflags.Rand(flags)
+			if err != nil {
+				return nil, fmt.Errorf("seeding trigger: %w", err)
+			}
+			runnableStages, err := ParseConfigFile(*fileContent, time.Now(), random)
 			if err != nil {
 				return nil, err
 			}
@@ -57,6 +65,7 @@ func Rate(output *ui.Output) api.Builder {
 				DryRun:      newDryRun(runnableStages.Stages),
 				Description: fmt.Sprintf("%d different stages", len(runnableStages.Stages)),
 				Duration:    runnableStages.stagesTotalDuration,
+				StageParams: runnableStages.stageParams(),
 				Options: api.Options{
 					Scenario:                 runnableStages.Scenario,
 					MaxDuration:              runnableStages.MaxDuration,
@@ -73,6 +82,16 @@ func Rate(output *ui.Output) api.Builder {
 	}
 }
 
+// stageParams returns the parameter values set by each stage.
+func (r *RunnableStages) stageParams() []map[string]string {
+	stageParams := make([]map[string]string, len(r.Stages))
+	for i, stage := range r.Stages {
+		stageParams[i] = stage.Params
+	}
+
+	return stageParams
+}
+
 func readFile(filename string, output *ui.Output) (*[]byte, error) {
 	file, err := os.Open(filepath.Clean(filename))
 	if err != nil {
@@ -2,7 +2,7 @@ package file
 
 import (
 	"context"
-	"os"
+	"fmt"
 	"time"
 
 	"github.com/form3tech-oss/f1/v2/internal/options"
@@ -10,16 +10,25 @@ import (
 	"github.com/form3tech-oss/f1/v2/internal/trigger/users"
 	"github.com/form3tech-oss/f1/v2/internal/ui"
 	"github.com/form3tech-oss/f1/v2/internal/workers"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/reporter"
 )
 
 const safeDurationBeforeNextStage = 20 * time.Millisecond
 
 func newStagesWorker(stages []runnableStage) api.WorkTriggerer {
 	return func(ctx context.Context, output *ui.Output, workers *workers.PoolManager, options options.RunOptions) {
-		for _, stage := range stages {
+		for i, stage := range stages {
 			if ctx.Err() != nil {
 				return
 			}
+			workers.SetStage(fmt.Sprintf("stage %d/%d (%s)", i+1, len(stages), stage.Mode))
+			workers.ReportStage(reporter.StageStarted{
+				Params:   stage.Params,
+				Mode:     stage.Mode,
+				Duration: stage.StageDuration,
+				Index:    i,
+				Stages:   len(stages),
+			})
 			runStage(ctx, output, workers, stage, options)
 		}
 	}
@@ -32,56 +41,37 @@ func runStage(
 	stage runnableStage,
 	options options.RunOptions,
 ) {
-	setEnvs(stage.Params, output)
-	defer unsetEnvs(stage.Params, output)
+	workers.SetStageParams(stage.Params)
+	defer workers.SetStageParams(nil)
+
+	clock := workers.Clock()
 
 	// stop the stage early to avoid starting a new tick
-	stageCtx, stageCancel := context.WithTimeout(ctx, stage.StageDuration-safeDurationBeforeNextStage)
+	stageCtx, stageCancel := clock.WithTimeout(ctx, stage.StageDuration-safeDurationBeforeNextStage)
 	defer stageCancel()
 
 	stageDone := make(chan struct{})
 
-	go func() {
+	clock.Go(func() {
 		defer close(stageDone)
+		// wake the stage runner, which is parked until the stage is done
+		defer clock.Unpark(1)
 
 		if stage.UsersConcurrency == 0 {
 			doWork := api.NewIterationWorker(stage.IterationDuration, stage.Rate)
 			doWork(stageCtx, output, workers, options)
 		} else {
-			doWork := users.NewWorker(stage.UsersConcurrency)
+			doWork := users.NewWorker(stage.UsersConcurrency, stage.UsersPacing)
 			doWork(stageCtx, output, workers, options)
 		}
-	}()
+	})
 
+	clock.Park()
 	select {
 	case <-ctx.Done():
 		<-stageDone
 		return
 	case <-stageDone:
-		time.Sleep(safeDurationBeforeNextStage)
-	}
-}
-
-func setEnvs(envs map[string]string, output *ui.Output) {
-	for key, value := range envs {
-		err := os.Setenv(key, value)
-		if err != nil {
-			output.Display(ui.ErrorMessage{
-				Message: "unable set environment variables for given scenario",
-				Error:   err,
-			})
-		}
-	}
-}
-
-func unsetEnvs(envs map[string]string, output *ui.Output) {
-	for key := range envs {
-		err := os.Unsetenv(key)
-		if err != nil {
-			output.Display(ui.ErrorMessage{
-				Message: "unable unset environment variables for given scenario",
-				Error:   err,
-			})
-		}
+		clock.Sleep(ctx, safeDurationBeforeNextStage)
 	}
 }
@@ -3,6 +3,7 @@ package gaussian
 import (
 	"fmt"
 	"math"
+	"math/rand/v2"
 	"strconv"
 	"strings"
 	"time"
@@ -106,6 +107,11 @@ func Rate(output *ui.Output) api.Builder {
 				}
 			}
 
+			random, err := triggerflags.Rand(flags)
+			if err != nil {
+				return nil, fmt.Errorf("seeding trigger: %w", err)
+			}
+
 			rates, err := CalculateGaussianRate(
 				volume,
 				jitter,
@@ -115,6 +121,7 @@ func Rate(output *ui.Output) api.Builder {
 				stddevDuration,
 				weights,
 				distributionTypeArg,
+				random,
 			)
 			if err != nil {
 				return nil, err
@@ -198,6 +205,7 @@ func CalculateGaussianRate(
 	volume, jitter float64,
 	repeat, frequency, peak, stddev time.Duration,
 	weightsArg, distributionTypeArg string,
+	random *rand.Rand,
 ) (*api.Rates, error) {
 	weights := strings.Split(weightsArg, ",")
 	weightsSlice := make([]float64, 0, len(weights))
@@ -218,9 +226,9 @@ func CalculateGaussianRate(
 		return nil, fmt.Errorf("calculator: %w", err)
 	}
 
-	rateFn := api.WithJitter(calculator.For, jitter)
+	rateFn := api.WithJitter(calculator.For, jitter, random)
 	distributedIterationDuration, distributedRateFn, err := api.NewDistribution(
-		api.DistributionType(distributionTypeArg), frequency, rateFn, nil,
+		api.DistributionType(distributionTypeArg), frequency, rateFn, random.IntN,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("new distribution: %w", err)
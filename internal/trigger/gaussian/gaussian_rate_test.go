@@ -172,7 +172,7 @@ func TestTotalVolumes(t *testing.T) {
 			current := time.Now().Truncate(test.repeat)
 			end := current.Add(test.repeat)
 
-			calculate := api.WithJitter(c.For, test.jitter)
+			calculate := api.WithJitter(c.For, test.jitter, api.NewRand(1))
 			for ; current.Before(end); current = current.Add(test.frequency) {
 				rate := calculate(current)
 				total += float64(rate)
@@ -3,6 +3,7 @@ package ramp
 import (
 	"errors"
 	"fmt"
+	"math/rand/v2"
 	"time"
 
 	"github.com/spf13/pflag"
@@ -62,7 +63,12 @@ func Rate() api.Builder {
 				return nil, fmt.Errorf("getting flag: %w", err)
 			}
 
-			rates, err := CalculateRampRate(startRateArg, endRateArg, distributionTypeArg, duration, jitterArg)
+			random, err := triggerflags.Rand(flags)
+			if err != nil {
+				return nil, fmt.Errorf("seeding trigger: %w", err)
+			}
+
+			rates, err := CalculateRampRate(startRateArg, endRateArg, distributionTypeArg, duration, jitterArg, random)
 			if err != nil {
 				return nil, fmt.Errorf("calculating ramp rate: %w", err)
 			}
@@ -83,6 +89,7 @@ func CalculateRampRate(
 	distributionTypeArg string,
 	duration time.Duration,
 	jitterArg float64,
+	random *rand.Rand,
 ) (*api.Rates, error) {
 	var startTime *time.Time
 
@@ -121,9 +128,9 @@ func CalculateRampRate(
 		return rate
 	}
 
-	jitterRateFn := api.WithJitter(rateFn, jitterArg)
+	jitterRateFn := api.WithJitter(rateFn, jitterArg, random)
 	distributedIterationDuration, distributedRateFn, err := api.NewDistribution(
-		api.DistributionType(distributionTypeArg), startUnit, jitterRateFn, nil,
+		api.DistributionType(distributionTypeArg), startUnit, jitterRateFn, random.IntN,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("new distribution: %w", err)
@@ -2,6 +2,7 @@ package staged
 
 import (
 	"fmt"
+	"math/rand/v2"
 	"time"
 
 	"github.com/spf13/pflag"
@@ -58,7 +59,12 @@ func Rate() api.Builder {
 				startTime = &parsedStartTime
 			}
 
-			rates, err := CalculateStagedRate(jitterArg, frequency, stg, distributionTypeArg, startTime)
+			random, err := triggerflags.Rand(params)
+			if err != nil {
+				return nil, fmt.Errorf("seeding trigger: %w", err)
+			}
+
+			rates, err := CalculateStagedRate(jitterArg, frequency, stg, distributionTypeArg, startTime, random)
 			if err != nil {
 				return nil, err
 			}
@@ -82,6 +88,7 @@ func CalculateStagedRate(
 	stg string,
 	distributionTypeArg string,
 	startTime *time.Time,
+	random *rand.Rand,
 ) (*api.Rates, error) {
 	stages, err := ParseStages(stg)
 	if err != nil {
@@ -89,9 +96,9 @@ func CalculateStagedRate(
 	}
 
 	calculator := NewRateCalculator(stages, startTime)
-	rateFn := api.WithJitter(calculator.Rate, jitterArg)
+	rateFn := api.WithJitter(calculator.Rate, jitterArg, random)
 	distributedIterationDuration, distributedRateFn, err := api.NewDistribution(
-		api.DistributionType(distributionTypeArg), frequency, rateFn, nil,
+		api.DistributionType(distributionTypeArg), frequency, rateFn, random.IntN,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("new distribution: %w", err)
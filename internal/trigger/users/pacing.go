@@ -0,0 +1,85 @@
+package users
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/workers"
+)
+
+// maxExponentialFactor limits exponential think times to a multiple of their mean
+const maxExponentialFactor = 10
+
+// NewPacing returns the pacing of users from a think time, see ParseThinkTime, and the minimum
+// time between the start of consecutive iterations.
+func NewPacing(thinkTime string, cycle time.Duration, random *rand.Rand) (workers.Pacing, error) {
+	if cycle < 0 {
+		return workers.Pacing{}, fmt.Errorf("pacing %s can't be negative", cycle)
+	}
+
+	thinkTimeFn, err := ParseThinkTime(thinkTime, random)
+	if err != nil {
+		return workers.Pacing{}, err
+	}
+
+	return workers.Pacing{ThinkTime: thinkTimeFn, Cycle: cycle}, nil
+}
+
+// ParseThinkTime parses a fixed duration such as "2s", a uniform range such as "1s-3s", or an
+// exponential distribution with a mean such as "exp:2s". An empty value doesn't wait. Random think
+// times are drawn from random, which must be safe for concurrent use, see api.NewRand.
+func ParseThinkTime(value string, random *rand.Rand) (func() time.Duration, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if mean, ok := strings.CutPrefix(value, "exp:"); ok {
+		meanDuration, err := parseThinkDuration(mean)
+		if err != nil {
+			return nil, err
+		}
+
+		return func() time.Duration {
+			return min(time.Duration(random.ExpFloat64()*float64(meanDuration)), maxExponentialFactor*meanDuration)
+		}, nil
+	}
+
+	if from, to, ok := strings.Cut(value, "-"); ok && from != "" {
+		fromDuration, err := parseThinkDuration(from)
+		if err != nil {
+			return nil, err
+		}
+		toDuration, err := parseThinkDuration(to)
+		if err != nil {
+			return nil, err
+		}
+		if toDuration < fromDuration {
+			return nil, fmt.Errorf("think time range %s ends before it starts", value)
+		}
+
+		return func() time.Duration {
+			return fromDuration + time.Duration(random.Int64N(int64(toDuration-fromDuration+1)))
+		}, nil
+	}
+
+	fixed, err := parseThinkDuration(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return func() time.Duration { return fixed }, nil
+}
+
+func parseThinkDuration(value string) (time.Duration, error) {
+	duration, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("parsing think time: %w", err)
+	}
+	if duration < 0 {
+		return 0, fmt.Errorf("think time %s can't be negative", duration)
+	}
+
+	return duration, nil
+}
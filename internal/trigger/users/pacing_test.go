@@ -0,0 +1,98 @@
+package users_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/users"
+)
+
+func TestParseThinkTime_Fixed(t *testing.T) {
+	t.Parallel()
+
+	thinkTime, err := users.ParseThinkTime("2s", api.NewRand(1))
+	require.NoError(t, err)
+
+	assert.Equal(t, 2*time.Second, thinkTime())
+}
+
+func TestParseThinkTime_UniformRange(t *testing.T) {
+	t.Parallel()
+
+	thinkTime, err := users.ParseThinkTime("1s-3s", api.NewRand(1))
+	require.NoError(t, err)
+
+	for range 100 {
+		wait := thinkTime()
+		assert.GreaterOrEqual(t, wait, time.Second)
+		assert.LessOrEqual(t, wait, 3*time.Second)
+	}
+}
+
+func TestParseThinkTime_Exponential(t *testing.T) {
+	t.Parallel()
+
+	thinkTime, err := users.ParseThinkTime("exp:100ms", api.NewRand(1))
+	require.NoError(t, err)
+
+	var total time.Duration
+	for range 1000 {
+		wait := thinkTime()
+		assert.GreaterOrEqual(t, wait, time.Duration(0))
+		assert.LessOrEqual(t, wait, time.Second)
+		total += wait
+	}
+	assert.InDelta(t, 100*time.Millisecond, total/1000, float64(30*time.Millisecond))
+}
+
+func TestParseThinkTime_RepeatsRandomThinkTimesWithTheSameSeed(t *testing.T) {
+	t.Parallel()
+
+	thinkTimes := func(seed uint64) []time.Duration {
+		thinkTime, err := users.ParseThinkTime("1s-3s", api.NewRand(seed))
+		require.NoError(t, err)
+
+		waits := make([]time.Duration, 10)
+		for i := range waits {
+			waits[i] = thinkTime()
+		}
+		return waits
+	}
+
+	assert.Equal(t, thinkTimes(42), thinkTimes(42))
+	assert.NotEqual(t, thinkTimes(42), thinkTimes(43))
+}
+
+func TestParseThinkTime_EmptyDoesNotWait(t *testing.T) {
+	t.Parallel()
+
+	thinkTime, err := users.ParseThinkTime("", api.NewRand(1))
+	require.NoError(t, err)
+
+	assert.Nil(t, thinkTime)
+}
+
+func TestParseThinkTime_Errors(t *testing.T) {
+	t.Parallel()
+
+	for value, expectedError := range map[string]string{
+		"soon":      "parsing think time: time: invalid duration \"soon\"",
+		"-1s":       "think time -1s can't be negative",
+		"3s-1s":     "think time range 3s-1s ends before it starts",
+		"exp:never": "parsing think time: time: invalid duration \"never\"",
+	} {
+		_, err := users.ParseThinkTime(value, api.NewRand(1))
+		require.EqualError(t, err, expectedError, value)
+	}
+}
+
+func TestNewPacing_FailsForNegativePacing(t *testing.T) {
+	t.Parallel()
+
+	_, err := users.NewPacing("", -time.Second, api.NewRand(1))
+	require.EqualError(t, err, "pacing -1s can't be negative")
+}
@@ -2,31 +2,62 @@ package users
 
 import (
 	"context"
+	"fmt"
 	"time"
 
 	"github.com/spf13/pflag"
 
 	"github.com/form3tech-oss/f1/v2/internal/options"
 	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
+	"github.com/form3tech-oss/f1/v2/internal/triggerflags"
 	"github.com/form3tech-oss/f1/v2/internal/ui"
 	"github.com/form3tech-oss/f1/v2/internal/workers"
 )
 
+// Names of the flags of the users trigger.
+const (
+	FlagThinkTime = "think-time"
+	FlagPacing    = "pacing"
+)
+
 func Rate() api.Builder {
 	flags := pflag.NewFlagSet("users", pflag.ContinueOnError)
+	flags.String(FlagThinkTime, "",
+		"time each user waits after an iteration: a duration (2s), a uniform range (1s-3s) "+
+			"or an exponential distribution with a mean (exp:2s)")
+	flags.Duration(FlagPacing, 0, "minimum time between the start of consecutive iterations of each user")
 
 	return api.Builder{
 		Name:        "users <scenario>",
 		Description: "triggers test iterations from a static set of users controlled by the --concurrency flag",
 		Flags:       flags,
-		New: func(*pflag.FlagSet) (*api.Trigger, error) {
+		New: func(params *pflag.FlagSet) (*api.Trigger, error) {
+			thinkTimeArg, err := params.GetString(FlagThinkTime)
+			if err != nil {
+				return nil, fmt.Errorf("getting flag: %w", err)
+			}
+			pacingArg, err := params.GetDuration(FlagPacing)
+			if err != nil {
+				return nil, fmt.Errorf("getting flag: %w", err)
+			}
+
+			random, err := triggerflags.Rand(params)
+			if err != nil {
+				return nil, fmt.Errorf("seeding trigger: %w", err)
+			}
+
+			pacing, err := NewPacing(thinkTimeArg, pacingArg, random)
+			if err != nil {
+				return nil, err
+			}
+
 			trigger := func(
 				ctx context.Context,
 				output *ui.Output,
 				workers *workers.PoolManager,
 				options options.RunOptions,
 			) {
-				doWork := NewWorker(options.Concurrency)
+				doWork := NewWorker(options.Concurrency, pacing)
 				doWork(ctx, output, workers, options)
 			}
 
@@ -44,10 +75,10 @@ func Rate() api.Builder {
 	}
 }
 
-func NewWorker(concurrency int) api.WorkTriggerer {
+func NewWorker(concurrency int, pacing workers.Pacing) api.WorkTriggerer {
 	return func(ctx context.Context, _ *ui.Output, workers *workers.PoolManager, _ options.RunOptions) {
-		pool := workers.NewContinuousPool(concurrency)
+		pool := workers.NewContinuousPool(concurrency, pacing)
 		pool.Start(ctx)
-		<-workers.WaitForCompletion()
+		workers.WaitForWorkers()
 	}
 }
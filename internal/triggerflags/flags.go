@@ -1,6 +1,9 @@
 package triggerflags
 
 import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/pflag"
@@ -11,6 +14,17 @@ import (
 const (
 	FlagVerbose                  = "verbose"
 	FlagVerboseFail              = "verbose-fail"
+	FlagMaxFailedIterationLogs   = "max-failed-iteration-logs"
+	FlagTUI                      = "tui"
+	FlagEventsFile               = "events-file"
+	FlagParam                    = "param"
+	FlagExec                     = "exec"
+	FlagScenarioFile             = "scenario-file"
+	FlagHAR                      = "har"
+	FlagHARThinkTimeScale        = "har-think-time-scale"
+	FlagHARHost                  = "har-host"
+	FlagHARInclude               = "har-include"
+	FlagHARExclude               = "har-exclude"
 	FlagIgnoreDropped            = "ignore-dropped"
 	FlagMaxDuration              = "max-duration"
 	FlagMaxIterations            = "max-iterations"
@@ -18,6 +32,7 @@ const (
 	FlagMaxFailures              = "max-failures"
 	FlagMaxFailuresRate          = "max-failures-rate"
 	FlagWaitForCompletionTimeout = "wait-for-completion-timeout"
+	FlagSeed                     = "seed"
 )
 
 const FlagDistribution = "distribution"
@@ -40,3 +55,46 @@ func JitterFlag(flagSet *pflag.FlagSet) {
 	flagSet.Float64P(FlagJitter, "j", 0.0,
 		"vary the rate randomly by up to jitter percent")
 }
+
+func SeedFlag(flagSet *pflag.FlagSet) {
+	flagSet.Uint64(FlagSeed, 0,
+		"seeds the random choices of the run, such as jitter, random distribution and t.Rand(), "+
+			"to repeat a previous run, 0 picks a random seed")
+}
+
+// ResolveSeed returns the seed set with --seed. Without one, it picks a random seed and sets the
+// flag to it, so that the trigger mode uses the seed displayed for the run.
+func ResolveSeed(flagSet *pflag.FlagSet) (uint64, error) {
+	seed, err := flagSet.GetUint64(FlagSeed)
+	if err != nil {
+		return 0, fmt.Errorf("getting flag: %w", err)
+	}
+	for seed == 0 {
+		//nolint:gosec // G404: Use of weak random number generator - doesn't need to be secure
+		seed = rand.Uint64()
+	}
+	if err := flagSet.Set(FlagSeed, strconv.FormatUint(seed, 10)); err != nil {
+		return 0, fmt.Errorf("setting flag: %w", err)
+	}
+
+	return seed, nil
+}
+
+// Rand returns the random number generator of a trigger mode, seeded with --seed, or with a
+// random seed if the flag isn't set or defined, such as by `f1 chart`.
+func Rand(flagSet *pflag.FlagSet) (*rand.Rand, error) {
+	var seed uint64
+	if flagSet.Lookup(FlagSeed) != nil {
+		var err error
+		seed, err = flagSet.GetUint64(FlagSeed)
+		if err != nil {
+			return nil, fmt.Errorf("getting flag: %w", err)
+		}
+	}
+	if seed == 0 {
+		//nolint:gosec // G404: Use of weak random number generator - doesn't need to be secure
+		seed = rand.Uint64()
+	}
+
+	return api.NewRand(seed), nil
+}
@@ -0,0 +1,106 @@
+package tui
+
+import (
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Dashboard is a full-screen interactive view of a running load test.
+//
+// Dashboard implements io.Writer, so that messages otherwise printed to the terminal can be
+// displayed within the dashboard.
+type Dashboard struct {
+	program  *tea.Program
+	done     chan struct{}
+	scenario string
+	// pending holds messages written before the dashboard is started
+	pending []string
+	mu      sync.Mutex
+}
+
+func New(scenario string) *Dashboard {
+	return &Dashboard{
+		scenario: scenario,
+		done:     make(chan struct{}),
+	}
+}
+
+// Start displays the dashboard. Key presses are passed on to the controller.
+func (d *Dashboard) Start(controller Controller) {
+	program := tea.NewProgram(
+		NewModel(d.scenario, controller),
+		tea.WithAltScreen(),
+		// signals are handled by f1
+		tea.WithoutSignalHandler(),
+	)
+
+	go func() {
+		defer close(d.done)
+		_, _ = program.Run()
+	}()
+
+	d.mu.Lock()
+	d.program = program
+	pending := d.pending
+	d.pending = nil
+	d.mu.Unlock()
+
+	for _, message := range pending {
+		program.Send(MessageMsg(message))
+	}
+}
+
+// Stop closes the dashboard and restores the terminal.
+func (d *Dashboard) Stop() {
+	d.mu.Lock()
+	program := d.program
+	d.mu.Unlock()
+
+	if program == nil {
+		return
+	}
+
+	program.Quit()
+	<-d.done
+}
+
+// Update refreshes the dashboard with the current status of the run.
+func (d *Dashboard) Update(status Status) {
+	d.mu.Lock()
+	program := d.program
+	d.mu.Unlock()
+
+	if program != nil {
+		program.Send(StatusMsg(status))
+	}
+}
+
+func (d *Dashboard) Write(p []byte) (int, error) {
+	d.mu.Lock()
+	program := d.program
+	if program == nil {
+		d.pending = append(d.pending, lines(p)...)
+	}
+	d.mu.Unlock()
+
+	if program != nil {
+		for _, line := range lines(p) {
+			program.Send(MessageMsg(line))
+		}
+	}
+
+	return len(p), nil
+}
+
+func lines(p []byte) []string {
+	var res []string
+	for line := range strings.SplitSeq(strings.TrimRight(string(p), "\n"), "\n") {
+		if strings.TrimSpace(line) != "" {
+			res = append(res, line)
+		}
+	}
+
+	return res
+}
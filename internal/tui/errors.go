@@ -0,0 +1,7 @@
+package tui
+
+// ErrorCount is the number of iterations which failed with the same message.
+type ErrorCount struct {
+	Message string
+	Count   uint64
+}
@@ -0,0 +1,197 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/form3tech-oss/f1/v2/internal/termcolor"
+)
+
+const (
+	historySize = 60
+	maxMessages = 5
+)
+
+// MaxErrorRows is the number of failure reasons displayed by the dashboard.
+const MaxErrorRows = 5
+
+// Controller is used by the dashboard to control the run in response to key presses.
+type Controller interface {
+	Pause()
+	Resume()
+	Stop()
+}
+
+// Status is a point in time view of the run displayed by the dashboard.
+type Status struct {
+	Stage                    string
+	Errors                   []ErrorCount
+	Duration                 time.Duration
+	Period                   time.Duration
+	PeriodAverage            time.Duration
+	PeriodIterationCount     uint64
+	SuccessfulIterationCount uint64
+	FailedIterationCount     uint64
+	DroppedIterationCount    uint64
+	TriggeredRate            float64
+	Workers                  int
+	BusyWorkers              int
+	HasTriggeredRate         bool
+	Paused                   bool
+}
+
+type (
+	// StatusMsg updates the model with the current status of the run.
+	StatusMsg Status
+	// MessageMsg adds a message, such as a warning, to the bottom of the dashboard.
+	MessageMsg string
+)
+
+// Model is the bubbletea model rendering the dashboard.
+type Model struct {
+	controller Controller
+	throughput *history
+	latency    *history
+	scenario   string
+	messages   []string
+	status     Status
+	stopping   bool
+}
+
+func NewModel(scenario string, controller Controller) *Model {
+	return &Model{
+		scenario:   scenario,
+		controller: controller,
+		throughput: newHistory(historySize),
+		latency:    newHistory(historySize),
+	}
+}
+
+func (*Model) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case StatusMsg:
+		m.status = Status(msg)
+		m.throughput.add(m.throughputPerSecond())
+		m.latency.add(float64(m.status.PeriodAverage))
+	case MessageMsg:
+		m.messages = append(m.messages, string(msg))
+		if len(m.messages) > maxMessages {
+			m.messages = m.messages[len(m.messages)-maxMessages:]
+		}
+	case tea.KeyMsg:
+		return m, m.handleKey(msg)
+	}
+
+	return m, nil
+}
+
+// handleKey returns the controller action as a command, so that it runs outside the
+// bubbletea event loop.
+func (m *Model) handleKey(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "p":
+		m.status.Paused = true
+		return func() tea.Msg {
+			m.controller.Pause()
+			return nil
+		}
+	case "r":
+		m.status.Paused = false
+		return func() tea.Msg {
+			m.controller.Resume()
+			return nil
+		}
+	case "q", "ctrl+c":
+		m.stopping = true
+		return func() tea.Msg {
+			m.controller.Stop()
+			return nil
+		}
+	}
+
+	return nil
+}
+
+func (m *Model) throughputPerSecond() float64 {
+	if m.status.Period <= 0 {
+		return 0
+	}
+
+	return float64(m.status.PeriodIterationCount) / m.status.Period.Seconds()
+}
+
+func (m *Model) View() string {
+	var sb strings.Builder
+
+	state := termcolor.Green + "running" + termcolor.Reset
+	switch {
+	case m.stopping:
+		state = termcolor.Red + "stopping" + termcolor.Reset
+	case m.status.Paused:
+		state = termcolor.Yellow + "paused" + termcolor.Reset
+	}
+
+	fmt.Fprintf(&sb, "%s%s%sF1 Load Tester%s  %s%s%s  [%s]  %s\n\n",
+		termcolor.Underline, termcolor.Bold, termcolor.BrightBlue, termcolor.Reset,
+		termcolor.Yellow, m.scenario, termcolor.Reset,
+		state, m.status.Duration.Round(time.Second))
+
+	fmt.Fprintf(&sb, "%sStage:%s          %s\n", termcolor.Bold, termcolor.Reset, m.status.Stage)
+	if m.status.HasTriggeredRate {
+		fmt.Fprintf(&sb, "%sTriggered rate:%s %.0f/s\n", termcolor.Bold, termcolor.Reset, m.status.TriggeredRate)
+	}
+	sb.WriteString("\n")
+
+	fmt.Fprintf(&sb, "%s✔ %d%s  %s✘ %d%s  %s⦸ %d%s\n\n",
+		termcolor.Green, m.status.SuccessfulIterationCount, termcolor.Reset,
+		termcolor.Red, m.status.FailedIterationCount, termcolor.Reset,
+		termcolor.Yellow, m.status.DroppedIterationCount, termcolor.Reset)
+
+	fmt.Fprintf(&sb, "%sThroughput:%s  %-10s %s%s%s\n",
+		termcolor.Bold, termcolor.Reset,
+		fmt.Sprintf("%.0f/s", m.throughputPerSecond()),
+		termcolor.Cyan, m.throughput.sparkline(), termcolor.Reset)
+	fmt.Fprintf(&sb, "%sLatency:%s     %-10s %s%s%s\n",
+		termcolor.Bold, termcolor.Reset,
+		m.status.PeriodAverage.Round(time.Microsecond).String(),
+		termcolor.Cyan, m.latency.sparkline(), termcolor.Reset)
+	fmt.Fprintf(&sb, "%sWorkers:%s     %d/%d busy (%.0f%%)\n",
+		termcolor.Bold, termcolor.Reset,
+		m.status.BusyWorkers, m.status.Workers, m.utilisation())
+
+	if len(m.status.Errors) > 0 {
+		fmt.Fprintf(&sb, "\n%sTop failure causes:%s\n", termcolor.Bold, termcolor.Reset)
+		for i, e := range m.status.Errors {
+			if i == MaxErrorRows {
+				break
+			}
+			fmt.Fprintf(&sb, "  %s%6d× %s%s\n", termcolor.Red, e.Count, e.Message, termcolor.Reset)
+		}
+	}
+
+	if len(m.messages) > 0 {
+		sb.WriteString("\n")
+		for _, message := range m.messages {
+			sb.WriteString(message + "\n")
+		}
+	}
+
+	fmt.Fprintf(&sb, "\n%s[p] pause  [r] resume  [q] stop%s\n", termcolor.BrightBlack, termcolor.Reset)
+
+	return sb.String()
+}
+
+func (m *Model) utilisation() float64 {
+	if m.status.Workers == 0 {
+		return 0
+	}
+
+	return 100 * float64(m.status.BusyWorkers) / float64(m.status.Workers)
+}
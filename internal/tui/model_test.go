@@ -0,0 +1,98 @@
+package tui_test
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/internal/tui"
+)
+
+type fakeController struct {
+	paused  atomic.Bool
+	resumed atomic.Bool
+	stopped atomic.Bool
+}
+
+func (c *fakeController) Pause()  { c.paused.Store(true) }
+func (c *fakeController) Resume() { c.resumed.Store(true) }
+func (c *fakeController) Stop()   { c.stopped.Store(true) }
+
+func TestModel_ViewShowsStatus(t *testing.T) {
+	t.Parallel()
+
+	model := tui.NewModel("myScenario", &fakeController{})
+	model.Update(tui.StatusMsg{
+		Stage:                    "stage 2/3 (constant)",
+		Duration:                 5 * time.Second,
+		Period:                   time.Second,
+		PeriodAverage:            20 * time.Millisecond,
+		PeriodIterationCount:     50,
+		SuccessfulIterationCount: 200,
+		FailedIterationCount:     7,
+		DroppedIterationCount:    3,
+		TriggeredRate:            60,
+		HasTriggeredRate:         true,
+		Workers:                  10,
+		BusyWorkers:              4,
+		Errors: []tui.ErrorCount{
+			{Message: "connection refused", Count: 5},
+			{Message: "timeout", Count: 2},
+		},
+	})
+
+	view := model.View()
+
+	assert.Contains(t, view, "myScenario")
+	assert.Contains(t, view, "stage 2/3 (constant)")
+	assert.Contains(t, view, "Triggered rate:\x1b[0m 60/s")
+	assert.Contains(t, view, "✔ 200")
+	assert.Contains(t, view, "✘ 7")
+	assert.Contains(t, view, "⦸ 3")
+	assert.Contains(t, view, "50/s")
+	assert.Contains(t, view, "20ms")
+	assert.Contains(t, view, "4/10 busy (40%)")
+	assert.Contains(t, view, "5× connection refused")
+	assert.Contains(t, view, "2× timeout")
+}
+
+func TestModel_ViewHidesTheTriggeredRateOfUsers(t *testing.T) {
+	t.Parallel()
+
+	model := tui.NewModel("myScenario", &fakeController{})
+	model.Update(tui.StatusMsg{Stage: "users", Workers: 10, BusyWorkers: 10})
+
+	assert.NotContains(t, model.View(), "Triggered rate")
+}
+
+func TestModel_KeysControlTheRun(t *testing.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		key      string
+		expected func(c *fakeController) bool
+		state    string
+	}{
+		{key: "p", expected: func(c *fakeController) bool { return c.paused.Load() }, state: "paused"},
+		{key: "r", expected: func(c *fakeController) bool { return c.resumed.Load() }, state: "running"},
+		{key: "q", expected: func(c *fakeController) bool { return c.stopped.Load() }, state: "stopping"},
+	} {
+		t.Run(test.key, func(t *testing.T) {
+			t.Parallel()
+
+			controller := &fakeController{}
+			model := tui.NewModel("myScenario", controller)
+
+			_, cmd := model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(test.key)})
+			require.NotNil(t, cmd)
+			cmd()
+
+			assert.True(t, test.expected(controller))
+			assert.Contains(t, model.View(), test.state)
+		})
+	}
+}
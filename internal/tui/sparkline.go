@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"strings"
+)
+
+//nolint:gochecknoglobals // constant lookup table
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+// history is a fixed size ring of the most recent values.
+type history struct {
+	values []float64
+	size   int
+}
+
+func newHistory(size int) *history {
+	return &history{
+		values: make([]float64, 0, size),
+		size:   size,
+	}
+}
+
+func (h *history) add(value float64) {
+	if len(h.values) == h.size {
+		copy(h.values, h.values[1:])
+		h.values = h.values[:h.size-1]
+	}
+	h.values = append(h.values, value)
+}
+
+// sparkline renders the values scaled between zero and the maximum value.
+func (h *history) sparkline() string {
+	maxValue := 0.0
+	for _, v := range h.values {
+		maxValue = max(maxValue, v)
+	}
+
+	var sb strings.Builder
+	for _, v := range h.values {
+		idx := 0
+		if maxValue > 0 {
+			idx = int(v / maxValue * float64(len(sparkBlocks)-1))
+		}
+		sb.WriteRune(sparkBlocks[max(0, min(idx, len(sparkBlocks)-1))])
+	}
+
+	return sb.String()
+}
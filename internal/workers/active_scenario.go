@@ -1,51 +1,123 @@
 package workers
 
 import (
+	"context"
 	"log/slog"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/form3tech-oss/f1/v2/internal/callbacks"
+	"github.com/form3tech-oss/f1/v2/internal/data"
+	"github.com/form3tech-oss/f1/v2/internal/events"
+	"github.com/form3tech-oss/f1/v2/internal/log"
 	"github.com/form3tech-oss/f1/v2/internal/metrics"
+	"github.com/form3tech-oss/f1/v2/internal/params"
 	"github.com/form3tech-oss/f1/v2/internal/progress"
+	"github.com/form3tech-oss/f1/v2/internal/queues"
+	"github.com/form3tech-oss/f1/v2/internal/toptions"
 	"github.com/form3tech-oss/f1/v2/internal/xtime"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/reporter"
 	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
 	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
 )
 
 type ActiveScenario struct {
 	scenario     *scenarios.Scenario
+	ctx          context.Context //nolint:containedctx // the context of the T of the scenario
+	interrupt    context.CancelFunc
 	m            *metrics.Metrics
 	progress     *progress.Stats
 	t            *testing.T
 	Teardown     func()
 	logger       *slog.Logger
 	logrusLogger *logrus.Logger
+	params       *params.Params
+	// feeder is only set when the scenario declares a data file
+	feeder *data.Feeder
+	// listener is only set when the scenario declares callbacks
+	listener *callbacks.Listener
+	// queues are the queues declared by the scenario, by name
+	queues map[string]queues.Binding
+	// seed is the seed of the run, from which T.Rand is derived
+	seed uint64
+	// failedIterationLogs is only set when the logs of failed iterations are buffered
+	failedIterationLogs *log.FlushLimit
+	// events is only set when iteration events are written to a file
+	events *events.Writer
+	// reporter is only set when reporters are registered
+	reporter reporter.Reporter
 }
 
 const instantDuration = 0
 
+// ActiveScenarioOptions are the features of a run which an ActiveScenario only uses when they are
+// set.
+type ActiveScenarioOptions struct {
+	// Params are the values of the scenario parameters returned by T.Param
+	Params *params.Params
+	// Feeder is set when the scenario declares a data file
+	Feeder *data.Feeder
+	// Listener is set when the scenario declares callbacks
+	Listener *callbacks.Listener
+	// Queues are the queues declared by the scenario, by name
+	Queues map[string]queues.Binding
+	// FailedIterationLogs is set when the logs of failed iterations are buffered
+	FailedIterationLogs *log.FlushLimit
+	// Events is set when iteration events are written to a file
+	Events *events.Writer
+	// Reporter is set when reporters are registered
+	Reporter reporter.Reporter
+	// Seed is the seed of the run, from which T.Rand is derived
+	Seed uint64
+}
+
 func NewActiveScenario(
 	scenario *scenarios.Scenario,
 	metricsInstance *metrics.Metrics,
 	stats *progress.Stats,
 	logger *slog.Logger,
 	logrusLogger *logrus.Logger,
+	opts ActiveScenarioOptions,
 ) *ActiveScenario {
-	t, teardown := testing.NewTWithOptions(scenario.Name,
+	ctx, interrupt := context.WithCancel(context.Background())
+	setupOptions := []testing.TOption{
+		testing.WithContext(ctx),
 		testing.WithIteration("setup"),
 		testing.WithVUID(-1),
 		testing.WithLogger(logger),
 		testing.WithLogrusLogger(logrusLogger),
-	)
+		testing.WithSeed(opts.Seed),
+		tOptions(toptions.Options{
+			Params:   opts.Params,
+			Metrics:  metricsInstance,
+			Listener: opts.Listener,
+			Queues:   opts.Queues,
+		}),
+	}
+	if opts.Reporter != nil {
+		setupOptions = append(setupOptions, testing.WithStageDurations())
+	}
+	t, teardown := testing.NewTWithOptions(scenario.Name, setupOptions...)
 
 	s := &ActiveScenario{
-		scenario:     scenario,
-		m:            metricsInstance,
-		t:            t,
-		Teardown:     teardown,
-		progress:     stats,
-		logger:       logger,
-		logrusLogger: logrusLogger,
+		scenario:            scenario,
+		ctx:                 ctx,
+		interrupt:           interrupt,
+		m:                   metricsInstance,
+		t:                   t,
+		Teardown:            teardown,
+		progress:            stats,
+		logger:              logger,
+		logrusLogger:        logrusLogger,
+		params:              opts.Params,
+		feeder:              opts.Feeder,
+		listener:            opts.Listener,
+		queues:              opts.Queues,
+		seed:                opts.Seed,
+		failedIterationLogs: opts.FailedIterationLogs,
+		events:              opts.Events,
+		reporter:            opts.Reporter,
 	}
 
 	return s
@@ -64,6 +136,11 @@ func (s *ActiveScenario) Setup() {
 	s.m.RecordSetupResult(s.scenario.Name, metrics.Result(s.t.Failed()), duration)
 }
 
+// Interrupt cancels the context returned by T.Context, once the run is interrupted.
+func (s *ActiveScenario) Interrupt() {
+	s.interrupt()
+}
+
 func (s *ActiveScenario) TeardownFailed() bool {
 	return s.t.TeardownFailed()
 }
@@ -72,37 +149,211 @@ func (s *ActiveScenario) Failed() bool {
 	return s.t.Failed()
 }
 
+// FailureReason returns the reason the setup failed, if it did.
+func (s *ActiveScenario) FailureReason() string {
+	return s.t.FailureReason()
+}
+
+// StageDurations returns the durations recorded with T.Time during the setup, which are only kept
+// when a reporter is set.
+func (s *ActiveScenario) StageDurations() []testing.StageDuration {
+	return s.t.StageDurations()
+}
+
+// Tags returns the tags set with T.Tag during the setup.
+func (s *ActiveScenario) Tags() map[string]string {
+	return s.t.Tags()
+}
+
 // Run performs a single iteration of the test.
 func (s *ActiveScenario) Run(state *iterationState) {
-	defer state.teardown()
+	var startedAt int64
+	if s.events != nil || s.reporter != nil {
+		startedAt = time.Now().UnixNano()
+	}
 
 	start := xtime.NanoTime()
 	func() {
 		defer testing.CheckResults(state.t, nil)
+		if state.vuSetupFailure != "" {
+			state.t.Errorf("virtual user setup failed: %s", state.vuSetupFailure)
+			return
+		}
 		s.scenario.RunFn(state.t)
 	}()
 
 	failed := state.t.Failed()
 	duration := xtime.NanoTime() - start
+	result := metrics.Result(failed)
+	tags := state.t.Tags()
 
-	s.m.RecordIterationResult(s.scenario.Name, metrics.Result(failed), duration)
-	s.progress.Record(metrics.Result(failed), duration)
+	reason := state.t.FailureReason()
+	if failed {
+		s.progress.RecordFailure(reason, state.t.Iteration)
+	}
+	s.m.RecordTaggedIteration(s.scenario.Name, result, reason, tags, duration)
+	s.progress.Record(result, duration)
+	if len(tags) > 0 {
+		s.progress.RecordTags(tags, result, duration)
+	}
+
+	if s.events != nil {
+		s.recordEvent(state, startedAt, duration)
+	}
+
+	// reported once the cleanup of the iteration ran, so that failures of the cleanup are reported
+	state.teardown()
+	if s.reporter != nil {
+		s.reportIteration(state, startedAt, duration)
+	}
 }
 
-func (s *ActiveScenario) RecordDroppedIteration() {
+func (s *ActiveScenario) reportIteration(state *iterationState, startedAt int64, duration int64) {
+	scheduledAt := state.scheduledAt
+	if scheduledAt == 0 {
+		scheduledAt = startedAt
+	}
+
+	var stages []reporter.Stage
+	for _, stage := range state.t.StageDurations() {
+		stages = append(stages, reporter.Stage{Name: stage.Name, Duration: stage.Duration})
+	}
+
+	s.reporter.Report(reporter.IterationFinished{
+		ScheduledAt: time.Unix(0, scheduledAt),
+		StartedAt:   time.Unix(0, startedAt),
+		Result:      metrics.Result(state.t.Failed()).String(),
+		Failure:     state.t.FailureReason(),
+		Duration:    time.Duration(duration),
+		Iteration:   state.iteration,
+		VUID:        state.t.VUID,
+		Tags:        state.t.Tags(),
+		Stages:      stages,
+		// the cleanup ran before the iteration is reported
+		CleanupFailed: state.t.TeardownFailed(),
+	})
+}
+
+func (s *ActiveScenario) recordEvent(state *iterationState, startedAt int64, duration int64) {
+	scheduledAt := state.scheduledAt
+	if scheduledAt == 0 {
+		scheduledAt = startedAt
+	}
+
+	var stages []events.Stage
+	for _, stage := range state.t.StageDurations() {
+		stages = append(stages, events.Stage{Name: stage.Name, Duration: stage.Duration.Nanoseconds()})
+	}
+
+	s.events.Record(events.Event{
+		Result:      metrics.Result(state.t.Failed()).String(),
+		Failure:     state.t.FailureReason(),
+		Stages:      stages,
+		Tags:        state.t.Tags(),
+		Iteration:   state.iteration,
+		VUID:        state.t.VUID,
+		ScheduledAt: scheduledAt,
+		StartedAt:   startedAt,
+		Duration:    duration,
+	})
+}
+
+// RecordDroppedIteration records an iteration, triggered at scheduledAt unix nanoseconds,
+// which was dropped because all workers were busy.
+func (s *ActiveScenario) RecordDroppedIteration(scheduledAt int64) {
 	s.m.RecordIterationResult(s.scenario.Name, metrics.DroppedResult, instantDuration)
 	s.progress.Record(metrics.DroppedResult, instantDuration)
+
+	if s.events != nil {
+		s.events.Record(events.Event{
+			Result:      metrics.DroppedResult.String(),
+			Failure:     "",
+			Stages:      nil,
+			Tags:        nil,
+			Iteration:   0,
+			VUID:        -1,
+			ScheduledAt: scheduledAt,
+			StartedAt:   0,
+			Duration:    instantDuration,
+		})
+	}
+
+	if s.reporter != nil {
+		s.reporter.Report(reporter.IterationFinished{
+			ScheduledAt: time.Unix(0, scheduledAt),
+			StartedAt:   time.Time{},
+			Result:      metrics.DroppedResult.String(),
+			Failure:     "",
+			Duration:    instantDuration,
+			Iteration:   0,
+			VUID:        -1,
+			Tags:        nil,
+			Stages:      nil,
+			// dropped iterations have no cleanup
+			CleanupFailed: false,
+		})
+	}
 }
 
 func (s *ActiveScenario) newIterationState(id int) *iterationState {
+	state := &iterationState{}
+	var vu any
+	if s.scenario.VUSetupFn != nil {
+		vu, state.vuTeardown, state.vuSetupFailure = s.setupVU(id)
+	}
+
+	options := []testing.TOption{
+		testing.WithContext(s.ctx),
+		testing.WithVU(vu),
+		testing.WithSeed(s.seed),
+		testing.WithVUID(id),
+		testing.WithLogger(s.logger),
+		testing.WithLogrusLogger(s.logrusLogger),
+		tOptions(toptions.Options{
+			FailedIterationLogs: s.failedIterationLogs,
+			Params:              s.params,
+			Metrics:             s.m,
+			Feeder:              s.feeder,
+			Listener:            s.listener,
+			Queues:              s.queues,
+		}),
+	}
+	if s.events != nil || s.reporter != nil {
+		options = append(options, testing.WithStageDurations())
+	}
+
+	state.t, state.teardown = testing.NewTWithOptions(s.scenario.Name, options...)
+
+	return state
+}
+
+// setupVU runs the setup of the virtual user id, returning its state, its teardown and the
+// reason it failed, if it did.
+func (s *ActiveScenario) setupVU(id int) (any, func(), string) {
 	t, teardown := testing.NewTWithOptions(s.scenario.Name,
+		testing.WithContext(s.ctx),
+		testing.WithIteration("vu setup"),
 		testing.WithVUID(id),
 		testing.WithLogger(s.logger),
 		testing.WithLogrusLogger(s.logrusLogger),
+		testing.WithSeed(s.seed),
+		tOptions(toptions.Options{Params: s.params, Metrics: s.m, Listener: s.listener, Queues: s.queues}),
 	)
 
-	return &iterationState{
-		t:        t,
-		teardown: teardown,
+	var vu any
+	func() {
+		defer testing.CheckResults(t, nil)
+		vu = s.scenario.VUSetupFn(t)
+	}()
+
+	if t.Failed() {
+		return vu, teardown, t.FailureReason()
 	}
+
+	return vu, teardown, ""
+}
+
+// tOptions returns the option of testing.T setting options.
+func tOptions(options toptions.Options) testing.TOption {
+	return toptions.New(options).(testing.TOption) //nolint:forcetypeassert // always a testing.TOption
 }
@@ -2,14 +2,15 @@ package workers
 
 import (
 	"context"
-	"strconv"
 	"sync"
 	"sync/atomic"
+	"time"
 )
 
-func newContinuousPool(m *PoolManager, numWorkers int) *ContinuousPool {
+func newContinuousPool(m *PoolManager, numWorkers int, pacing Pacing) *ContinuousPool {
 	return &ContinuousPool{
 		numWorkers:         numWorkers,
+		pacing:             pacing,
 		iterationStatePool: m.makeIterationStatePool(numWorkers),
 		manager:            m,
 	}
@@ -19,29 +20,33 @@ type ContinuousPool struct {
 	manager            *PoolManager
 	workerCtxCancel    context.CancelFunc
 	iterationStatePool []*iterationState
+	pacing             Pacing
 	numWorkers         int
 	stopWorkers        atomic.Bool
 }
 
 func (p *ContinuousPool) Start(ctx context.Context) {
-	workerCtx, workerCtxCancel := context.WithCancel(ctx)
+	workerCtx, workerCtxCancel := p.manager.clock.WithCancel(ctx)
 	p.workerCtxCancel = workerCtxCancel
 
 	workersStarted := sync.WaitGroup{}
 
 	workersStarted.Add(p.numWorkers)
 	p.manager.runningWorkers.Add(p.numWorkers)
+	p.manager.workers.Add(int64(p.numWorkers))
 	for _, iterationState := range p.iterationStatePool {
-		go p.startWorker(iterationState, &workersStarted)
+		p.manager.clock.Go(func() {
+			p.startWorker(workerCtx, iterationState, &workersStarted)
+		})
 	}
 
 	// context.Done() and context.Err() for context that can be cancelled use a Lock.
 	// To avoid frequent locking - use an atomic.Bool for cancellation instead of checking the
 	// context on each iteration
-	go func() {
-		<-workerCtx.Done()
+	p.manager.clock.Go(func() {
+		p.manager.clock.Wait(workerCtx)
 		p.stopWorkers.Store(true)
-	}()
+	})
 }
 
 func (p *ContinuousPool) maxIterationsReached() {
@@ -49,25 +54,48 @@ func (p *ContinuousPool) maxIterationsReached() {
 }
 
 func (p *ContinuousPool) startWorker(
+	ctx context.Context,
 	iterationState *iterationState,
 	workersStarted *sync.WaitGroup,
 ) {
 	defer p.manager.runningWorkers.Done()
+	defer p.manager.workerStopped()
+	defer iterationState.teardownVU()
 
 	// wait for all workers to start before execution to make sure we're executing at the
 	// concurrency requested
 	workersStarted.Done()
 	workersStarted.Wait()
 
+	// the time to wait before the next iteration, following the pacing
+	var wait time.Duration
+
 	// use and atomic.Bool to control execution to avoid mutex usage in channels and context.Context
 	for !p.stopWorkers.Load() {
+		if !p.manager.waitWhilePaused(ctx) {
+			return
+		}
+
+		if wait > 0 {
+			// don't wait for an iteration which won't run
+			if !p.manager.iterationsRemaining() {
+				p.maxIterationsReached()
+				return
+			}
+			if !p.manager.wait(ctx, wait) {
+				return
+			}
+		}
+
 		iteration, err := p.manager.NextIteration()
 		if err != nil {
 			p.maxIterationsReached()
 			return
 		}
 
-		iterationState.t.Reset(strconv.FormatUint(iteration, 10))
-		p.manager.activeScenario.Run(iterationState)
+		start := p.manager.clock.Now()
+		iterationState.reset(iteration, 0)
+		p.manager.run(iterationState)
+		wait = p.pacing.waitAfter(p.manager.clock.Since(start))
 	}
 }
@@ -0,0 +1,39 @@
+package workers
+
+import (
+	"context"
+	"time"
+)
+
+// Pacing controls the time each virtual user of a ContinuousPool waits between iterations.
+type Pacing struct {
+	// ThinkTime returns the time to wait after each iteration, or is nil to not wait.
+	ThinkTime func() time.Duration
+	// Cycle is the minimum time between the start of consecutive iterations of a virtual user.
+	Cycle time.Duration
+}
+
+// waitAfter returns how long to wait after an iteration which ran for iterationDuration: the
+// think time, or longer to complete the pacing cycle.
+func (p Pacing) waitAfter(iterationDuration time.Duration) time.Duration {
+	var wait time.Duration
+	if p.ThinkTime != nil {
+		wait = p.ThinkTime()
+	}
+
+	return max(wait, p.Cycle-iterationDuration)
+}
+
+// wait sleeps for d between iterations, and records the time waited separately from iteration
+// durations. It returns false if ctx is done before d has passed.
+func (m *PoolManager) wait(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+
+	start := m.clock.Now()
+	m.clock.Sleep(ctx, d)
+	m.activeScenario.progress.RecordWait(m.clock.Since(start).Nanoseconds())
+
+	return ctx.Err() == nil
+}
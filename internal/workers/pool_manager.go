@@ -1,34 +1,75 @@
 package workers
 
 import (
+	"context"
 	"errors"
+	"strconv"
 	"sync"
 	"sync/atomic"
 
+	"github.com/form3tech-oss/f1/v2/internal/clock"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/reporter"
 	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
 )
 
 type iterationState struct {
 	teardown func()
 	t        *testing.T
+	// scheduledAt is when the iteration was triggered in unix nanoseconds, or 0 if it
+	// was started as soon as the previous iteration finished
+	scheduledAt int64
+	iteration   uint64
+	// vuTeardown runs the cleanup of the virtual user, if the scenario sets one up
+	vuTeardown func()
+	// vuSetupFailure is the reason the setup of the virtual user failed
+	vuSetupFailure string
+}
+
+func (s *iterationState) reset(iteration uint64, scheduledAt int64) {
+	s.iteration = iteration
+	s.scheduledAt = scheduledAt
+	s.t.Reset(strconv.FormatUint(iteration, 10))
 }
 
 type PoolManager struct {
 	activeScenario *ActiveScenario
+	clock          clock.Clock
+	// workersStopped is broadcast once the last running worker stopped
+	workersStopped *sync.Cond
+	// resumeCh is non-nil while the pool manager is paused and closed on resume
+	resumeCh       chan struct{}
+	stage          atomic.Pointer[string]
 	runningWorkers sync.WaitGroup
 	iteration      atomic.Uint64
 	maxIterations  uint64
+	triggered      atomic.Uint64
+	workers        atomic.Int64
+	busyWorkers    atomic.Int64
+	pauseMu        sync.Mutex
+	paused         atomic.Bool
+	// continuous is set once iterations are started by a continuous pool rather than triggered
+	continuous atomic.Bool
+	// workerWaiters is the number of goroutines parked in WaitForWorkers
+	workerWaiters int
 }
 
-func New(maxIterations uint64, activeScenario *ActiveScenario) *PoolManager {
+// New returns a pool manager of the workers running activeScenario, which tells the time with clk.
+func New(maxIterations uint64, activeScenario *ActiveScenario, clk clock.Clock) *PoolManager {
 	w := &PoolManager{
 		activeScenario: activeScenario,
+		clock:          clk,
+		workersStopped: sync.NewCond(&sync.Mutex{}),
 		maxIterations:  maxIterations,
 	}
 
 	return w
 }
 
+// Clock returns the clock the trigger and the pools tell the time with.
+func (m *PoolManager) Clock() clock.Clock {
+	return m.clock
+}
+
 func (m *PoolManager) WaitForCompletion() <-chan struct{} {
 	done := make(chan struct{})
 	go func() {
@@ -38,6 +79,31 @@ func (m *PoolManager) WaitForCompletion() <-chan struct{} {
 	return done
 }
 
+// WaitForWorkers blocks until all the workers of the pools stopped. Unlike WaitForCompletion, the
+// goroutine is parked on the clock while it waits, so triggers can wait for their pools with it.
+func (m *PoolManager) WaitForWorkers() {
+	m.workersStopped.L.Lock()
+	defer m.workersStopped.L.Unlock()
+
+	for m.workers.Load() > 0 {
+		m.workerWaiters++
+		m.clock.Park()
+		m.workersStopped.Wait()
+	}
+}
+
+// workerStopped records that a worker stopped, and wakes the goroutines waiting for the last one.
+func (m *PoolManager) workerStopped() {
+	m.workersStopped.L.Lock()
+	defer m.workersStopped.L.Unlock()
+
+	if m.workers.Add(-1) == 0 {
+		m.clock.Unpark(m.workerWaiters)
+		m.workerWaiters = 0
+		m.workersStopped.Broadcast()
+	}
+}
+
 func (m *PoolManager) MaxIterationsReached() bool {
 	if m.maxIterations > 0 && m.iteration.Load() > m.maxIterations {
 		return true
@@ -46,6 +112,11 @@ func (m *PoolManager) MaxIterationsReached() bool {
 	return false
 }
 
+// iterationsRemaining returns false once all iterations allowed by max iterations have started.
+func (m *PoolManager) iterationsRemaining() bool {
+	return m.maxIterations == 0 || m.iteration.Load() < m.maxIterations
+}
+
 var errMaxIterationsReached = errors.New("max iterations reached")
 
 func (m *PoolManager) NextIteration() (uint64, error) {
@@ -61,15 +132,142 @@ func (m *PoolManager) NewTriggerPool(numWorkers int) *TriggerPool {
 	return newTriggerPool(m, numWorkers)
 }
 
-func (m *PoolManager) NewContinuousPool(numWorkers int) *ContinuousPool {
-	return newContinuousPool(m, numWorkers)
+// NewContinuousPool returns a pool of numWorkers virtual users, which start an iteration as soon as
+// the previous one finished and the pacing allows.
+func (m *PoolManager) NewContinuousPool(numWorkers int, pacing Pacing) *ContinuousPool {
+	m.continuous.Store(true)
+	return newContinuousPool(m, numWorkers, pacing)
+}
+
+// Continuous reports whether iterations are started by the virtual users of a continuous pool, as
+// in the users trigger mode, rather than triggered at a rate.
+func (m *PoolManager) Continuous() bool {
+	return m.continuous.Load()
 }
 
+// Pause stops new iterations from being started until Resume is called.
+// Iterations that are already running are not affected.
+func (m *PoolManager) Pause() {
+	m.pauseMu.Lock()
+	defer m.pauseMu.Unlock()
+
+	if m.resumeCh == nil {
+		m.resumeCh = make(chan struct{})
+	}
+	m.paused.Store(true)
+}
+
+// Resume allows new iterations to be started after a call to Pause.
+func (m *PoolManager) Resume() {
+	m.pauseMu.Lock()
+	defer m.pauseMu.Unlock()
+
+	if m.resumeCh != nil {
+		close(m.resumeCh)
+		m.resumeCh = nil
+	}
+	m.paused.Store(false)
+}
+
+func (m *PoolManager) Paused() bool {
+	return m.paused.Load()
+}
+
+// waitWhilePaused blocks while the pool manager is paused. It returns false if ctx is done
+// before the pool manager is resumed.
+func (m *PoolManager) waitWhilePaused(ctx context.Context) bool {
+	if !m.paused.Load() {
+		return true
+	}
+
+	m.pauseMu.Lock()
+	resumeCh := m.resumeCh
+	m.pauseMu.Unlock()
+
+	if resumeCh == nil {
+		return true
+	}
+
+	select {
+	case <-resumeCh:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// SetStageParams sets scenario parameter values for the current stage, replacing the values of
+// any previous stage.
+func (m *PoolManager) SetStageParams(values map[string]string) {
+	m.activeScenario.params.SetStage(values)
+}
+
+// SetStage records a description of the stage the trigger is currently running.
+func (m *PoolManager) SetStage(stage string) {
+	m.stage.Store(&stage)
+}
+
+// ReportStage reports the start of a stage of the trigger to the reporters of the run.
+func (m *PoolManager) ReportStage(stage reporter.StageStarted) {
+	if m.activeScenario.reporter != nil {
+		m.activeScenario.reporter.Report(stage)
+	}
+}
+
+// Stage returns the description of the current trigger stage, or an empty string if
+// the trigger didn't set one.
+func (m *PoolManager) Stage() string {
+	stage := m.stage.Load()
+	if stage == nil {
+		return ""
+	}
+
+	return *stage
+}
+
+// TriggeredIterations returns the total number of iterations requested by the trigger.
+func (m *PoolManager) TriggeredIterations() uint64 {
+	return m.triggered.Load()
+}
+
+// Workers returns the number of running workers.
+func (m *PoolManager) Workers() int {
+	return int(m.workers.Load())
+}
+
+// BusyWorkers returns the number of workers currently running an iteration.
+func (m *PoolManager) BusyWorkers() int {
+	return int(m.busyWorkers.Load())
+}
+
+func (m *PoolManager) run(state *iterationState) {
+	m.busyWorkers.Add(1)
+	defer m.busyWorkers.Add(-1)
+
+	m.activeScenario.Run(state)
+}
+
+// teardownVU runs the cleanup of the virtual user, once the worker stops.
+func (s *iterationState) teardownVU() {
+	if s.vuTeardown != nil {
+		s.vuTeardown()
+		s.vuTeardown = nil
+	}
+}
+
+// makeIterationStatePool creates the state of each worker, setting up the virtual users concurrently.
 func (m *PoolManager) makeIterationStatePool(numWorkers int) []*iterationState {
 	statePool := make([]*iterationState, numWorkers)
+
+	var wg sync.WaitGroup
+	wg.Add(numWorkers)
 	for i := range numWorkers {
-		statePool[i] = m.activeScenario.newIterationState(i)
+		go func() {
+			defer wg.Done()
+			statePool[i] = m.activeScenario.newIterationState(i)
+		}()
 	}
+	wg.Wait()
 
 	return statePool
 }
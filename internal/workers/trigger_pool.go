@@ -2,7 +2,6 @@ package workers
 
 import (
 	"context"
-	"strconv"
 	"sync"
 	"sync/atomic"
 )
@@ -23,31 +22,44 @@ type TriggerPool struct {
 	jobsAvailableCond  *sync.Cond
 	iterationStatePool []*iterationState
 	numWorkers         int
+	// parkedWorkers is the number of workers waiting on jobsAvailableCond
+	parkedWorkers int
 	// jobsToExecute holds a number of pending work to execute
 	jobsToExecute jobCounter
-	stopWorkers   atomic.Bool
+	// scheduledAt is when the pending jobs were triggered, in unix nanoseconds
+	scheduledAt atomic.Int64
+	stopWorkers atomic.Bool
 }
 
 // Trigger will trigger the execution of a numJobs in the worker pool,
 // discarding anything that is currently scheduled for execution.
+//
+// Nothing is triggered while the pool manager is paused.
 func (p *TriggerPool) Trigger(ctx context.Context, numJobs int) {
-	if ctx.Err() != nil {
+	if ctx.Err() != nil || p.manager.Paused() {
 		return
 	}
-	p.sendJobsForExecution(numJobs)
+	if numJobs > 0 {
+		p.manager.triggered.Add(uint64(numJobs))
+	}
+	previouslyScheduledAt := p.scheduledAt.Swap(p.manager.clock.Now().UnixNano())
+	p.sendJobsForExecution(numJobs, previouslyScheduledAt)
 }
 
 func (p *TriggerPool) Start(ctx context.Context) context.Context {
 	p.manager.runningWorkers.Add(p.numWorkers)
+	p.manager.workers.Add(int64(p.numWorkers))
 
 	startedWg := sync.WaitGroup{}
 	startedWg.Add(p.numWorkers)
 
-	workerCtx, cancel := context.WithCancel(ctx)
+	workerCtx, cancel := p.manager.clock.WithCancel(ctx)
 	p.workerCtxCancel = cancel
 
 	for _, statePool := range p.iterationStatePool {
-		go p.run(statePool, &startedWg)
+		p.manager.clock.Go(func() {
+			p.run(statePool, &startedWg)
+		})
 	}
 
 	// wait for all workers to start, to make sure we have the concurrency requested,
@@ -57,10 +69,10 @@ func (p *TriggerPool) Start(ctx context.Context) context.Context {
 	// context.Done() and context.Err() for context that can be cancelled use a Lock.
 	// To avoid frequent locking - use an atomic.Bool for cancellation instead of checking the
 	// context on each iteration
-	go func() {
-		<-workerCtx.Done()
+	p.manager.clock.Go(func() {
+		p.manager.clock.Wait(workerCtx)
 		p.stop()
-	}()
+	})
 
 	return workerCtx
 }
@@ -71,7 +83,7 @@ func (p *TriggerPool) running() bool {
 
 func (p *TriggerPool) stop() {
 	p.stopWorkers.Store(true)
-	p.sendJobsForExecution(0)
+	p.sendJobsForExecution(0, p.scheduledAt.Load())
 }
 
 func (p *TriggerPool) maxIterationsReached() {
@@ -79,16 +91,20 @@ func (p *TriggerPool) maxIterationsReached() {
 	p.workerCtxCancel()
 }
 
-func (p *TriggerPool) sendJobsForExecution(numJobs int) {
+// sendJobsForExecution replaces the pending jobs with numJobs, recording the discarded jobs,
+// which were scheduled at discardedScheduledAt, as dropped.
+func (p *TriggerPool) sendJobsForExecution(numJobs int, discardedScheduledAt int64) {
 	p.jobsAvailableCond.L.Lock()
 
 	jobsDiscarded := p.jobsToExecute.set(numJobs)
+	p.manager.clock.Unpark(p.parkedWorkers)
+	p.parkedWorkers = 0
 	p.jobsAvailableCond.Broadcast()
 
 	p.jobsAvailableCond.L.Unlock()
 
 	for range jobsDiscarded {
-		p.manager.activeScenario.RecordDroppedIteration()
+		p.manager.activeScenario.RecordDroppedIteration(discardedScheduledAt)
 	}
 }
 
@@ -96,6 +112,8 @@ func (p *TriggerPool) waitForNewJobs() {
 	p.jobsAvailableCond.L.Lock()
 
 	for p.jobsToExecute.none() && p.running() {
+		p.parkedWorkers++
+		p.manager.clock.Park()
 		p.jobsAvailableCond.Wait()
 	}
 	p.jobsAvailableCond.L.Unlock()
@@ -106,6 +124,8 @@ func (p *TriggerPool) run(
 	startWg *sync.WaitGroup,
 ) {
 	defer p.manager.runningWorkers.Done()
+	defer p.manager.workerStopped()
+	defer iterationState.teardownVU()
 	startWg.Done()
 
 	for p.running() {
@@ -120,8 +140,8 @@ func (p *TriggerPool) run(
 				return
 			}
 
-			iterationState.t.Reset(strconv.FormatUint(iteration, 10))
-			p.manager.activeScenario.Run(iterationState)
+			iterationState.reset(iteration, p.scheduledAt.Load())
+			p.manager.run(iterationState)
 		}
 	}
 }
@@ -9,10 +9,16 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/prometheus/client_golang/prometheus"
+
 	"github.com/form3tech-oss/f1/v2/internal/envsettings"
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+	"github.com/form3tech-oss/f1/v2/internal/queues"
 	"github.com/form3tech-oss/f1/v2/internal/ui"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/reporter"
 	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
 	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+	f1trigger "github.com/form3tech-oss/f1/v2/pkg/f1/trigger"
 )
 
 const (
@@ -36,6 +42,10 @@ type F1 struct {
 type f1Options struct {
 	output        *ui.Output
 	staticMetrics map[string]string
+	forwarder     *metrics.Forwarder
+	queues        *queues.Registry
+	triggers      []f1trigger.Builder
+	reporters     []reporter.Reporter
 }
 
 // New instantiates a new instance of an F1 CLI.
@@ -48,6 +58,7 @@ func New() *F1 {
 		settings:  settings,
 		options: &f1Options{
 			output: ui.NewDefaultOutput(settings.Log.SlogLevel(), settings.Log.IsFormatJSON()),
+			queues: queues.NewRegistry(),
 		},
 	}
 }
@@ -69,6 +80,42 @@ func (f *F1) WithStaticMetrics(labels map[string]string) *F1 {
 	return f
 }
 
+// WithMetricsRegisterer registers the f1 metrics of the most recent run on registerer, such as
+// prometheus.DefaultRegisterer, which only holds them when they are registered this way, as each
+// run has its own registry. It panics if they can't be registered, like prometheus.MustRegister.
+func (f *F1) WithMetricsRegisterer(registerer prometheus.Registerer) *F1 {
+	f.options.forwarder = &metrics.Forwarder{}
+	registerer.MustRegister(f.options.forwarder)
+	return f
+}
+
+// WithTrigger registers a custom trigger mode, which is available under `run` and `chart` like the
+// built-in trigger modes.
+func (f *F1) WithTrigger(builder f1trigger.Builder) *F1 {
+	f.options.triggers = append(f.options.triggers, builder)
+	return f
+}
+
+// WithReporter registers a reporter, which receives the events of every run: its start, the setup,
+// progress, every iteration, the stages of file triggers, interruption, the teardown and the result.
+// Use reporter.SampleIterations to receive only some of the iterations.
+func (f *F1) WithReporter(r reporter.Reporter) *F1 {
+	f.options.reporters = append(f.options.reporters, r)
+	return f
+}
+
+// reporter returns a reporter of the registered reporters, or nil if there are none.
+func (o *f1Options) reporter() reporter.Reporter {
+	switch len(o.reporters) {
+	case 0:
+		return nil
+	case 1:
+		return o.reporters[0]
+	default:
+		return reporter.Multi(o.reporters...)
+	}
+}
+
 // Add registers a new test scenario with the given name. This is the name used when running
 // load test scenarios. For example, calling the function with the following arguments:
 //
@@ -145,7 +192,7 @@ func (f *F1) GetScenarios() *scenarios.Scenarios {
 }
 
 func (f *F1) execute(args []string) error {
-	rootCmd, err := buildRootCmd(f.scenarios, f.settings, f.profiling, f.options.output, f.options.staticMetrics)
+	rootCmd, err := buildRootCmd(f.scenarios, f.settings, f.profiling, f.options)
 	if err != nil {
 		return fmt.Errorf("building root command: %w", err)
 	}
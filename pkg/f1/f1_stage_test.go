@@ -2,33 +2,48 @@ package f1_test
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"syscall"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/pflag"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/goleak"
 
 	"github.com/form3tech-oss/f1/v2/internal/log"
 	"github.com/form3tech-oss/f1/v2/pkg/f1"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
 	f1_testing "github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/trigger"
 )
 
 type f1Stage struct {
 	executeErr error
+	runResult  *f1.RunResult
 	t          *testing.T
 	assert     *assert.Assertions
 	require    *require.Assertions
 	f1         *f1.F1
 	errCh      chan error
+	registry   *prometheus.Registry
 	scenario   string
+	outputFile string
+	serverURL  string
+	consumed   []string
 	logOutput  bytes.Buffer
 	runCount   atomic.Uint32
+	consumedMu sync.Mutex
 }
 
 func newF1Stage(t *testing.T) (*f1Stage, *f1Stage, *f1Stage) {
@@ -84,6 +99,43 @@ func (s *f1Stage) a_scenario_where_each_iteration_takes(duration time.Duration)
 	return s
 }
 
+func (s *f1Stage) a_custom_trigger_mode(name string) *f1Stage {
+	s.f1.WithTrigger(burstsTrigger(name))
+
+	return s
+}
+
+// burstsTrigger starts --iterations every 100ms
+func burstsTrigger(name string) trigger.Builder {
+	return trigger.Builder{
+		Name:        name,
+		Description: "starts --iterations every 100ms",
+		Flags: func(flags *pflag.FlagSet) {
+			flags.Int("iterations", 1, "iterations to start every 100ms")
+			trigger.JitterFlag(flags)
+			trigger.DistributionFlag(flags)
+		},
+		New: func(flags *pflag.FlagSet) (*trigger.Trigger, error) {
+			iterations, err := flags.GetInt("iterations")
+			if err != nil {
+				return nil, err
+			}
+
+			iterationDuration, rate, err := trigger.ApplyFlags(flags, 100*time.Millisecond,
+				func(time.Time) int { return iterations })
+			if err != nil {
+				return nil, err
+			}
+
+			return &trigger.Trigger{
+				Rate:              rate,
+				IterationDuration: iterationDuration,
+				Description:       fmt.Sprintf("%d iterations every 100ms", iterations),
+			}, nil
+		},
+	}
+}
+
 func (s *f1Stage) a_scenario_that_logs() *f1Stage {
 	s.scenario = "logging_scenario"
 	s.f1.Add(s.scenario, func(sceanrioT *f1_testing.T) f1_testing.RunFn {
@@ -98,6 +150,97 @@ func (s *f1Stage) a_scenario_that_logs() *f1Stage {
 	return s
 }
 
+func (s *f1Stage) a_scenario_where_every_other_iteration_fails() *f1Stage {
+	s.scenario = "scenario_where_every_other_iteration_fails"
+	s.f1.Add(s.scenario, func(*f1_testing.T) f1_testing.RunFn {
+		return func(t *f1_testing.T) {
+			if s.runCount.Add(1)%2 == 0 {
+				t.Errorf("boom")
+			}
+		}
+	})
+
+	return s
+}
+
+// a_producer_and_a_consumer_scenario_sharing_a_queue adds a scenario putting the accounts it
+// creates in a queue, and a scenario taking them from the queue.
+func (s *f1Stage) a_producer_and_a_consumer_scenario_sharing_a_queue() *f1Stage {
+	s.f1.Add("create_account", func(*f1_testing.T) f1_testing.RunFn {
+		return func(t *f1_testing.T) {
+			time.Sleep(10 * time.Millisecond)
+			t.Queue("accounts").Put("acc-" + t.Iteration)
+		}
+	}, scenarios.WithQueue(scenarios.Queue{Name: "accounts", Capacity: 5}))
+	s.f1.Add("make_payment", func(*f1_testing.T) f1_testing.RunFn {
+		return func(t *f1_testing.T) {
+			ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+			defer cancel()
+			account, _ := t.Queue("accounts").Take(ctx)
+
+			s.consumedMu.Lock()
+			s.consumed = append(s.consumed, fmt.Sprint(account))
+			s.consumedMu.Unlock()
+		}
+	}, scenarios.WithQueue(scenarios.Queue{Name: "accounts", WhenEmpty: scenarios.QueueBlock}))
+
+	return s
+}
+
+func (s *f1Stage) a_consumer_scenario_skipping_when_the_queue_is_empty() *f1Stage {
+	s.f1.Add("check_account", func(*f1_testing.T) f1_testing.RunFn {
+		return func(t *f1_testing.T) {
+			account, _ := t.Queue("accounts").Take(t.Context())
+
+			s.consumedMu.Lock()
+			s.consumed = append(s.consumed, fmt.Sprint(account))
+			s.consumedMu.Unlock()
+		}
+	}, scenarios.WithQueue(scenarios.Queue{Name: "accounts", WhenEmpty: scenarios.QueueSkip}))
+
+	return s
+}
+
+func (s *f1Stage) the_scenarios_are_run_at_the_same_time_with(config f1.RunConfig, names ...string) *f1Stage {
+	var wg sync.WaitGroup
+	results := make([]*f1.RunResult, len(names))
+	errs := make([]error, len(names))
+	for i, name := range names {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			config := config
+			config.Scenario = name
+			results[i], errs[i] = s.f1.Run(context.Background(), config)
+		}()
+	}
+	wg.Wait()
+
+	for i, name := range names {
+		s.require.NoError(errs[i], name)
+		s.assert.True(results[i].Passed, name)
+	}
+
+	return s
+}
+
+func (s *f1Stage) the_consumer_should_have_taken_each_item_once(expected ...string) *f1Stage {
+	s.consumedMu.Lock()
+	defer s.consumedMu.Unlock()
+
+	s.assert.ElementsMatch(expected, s.consumed)
+
+	return s
+}
+
+func (s *f1Stage) the_f1_scenario_is_run_with(config f1.RunConfig) *f1Stage {
+	config.Scenario = s.scenario
+	s.runResult, s.executeErr = s.f1.Run(context.Background(), config)
+
+	return s
+}
+
 func (s *f1Stage) the_f1_scenario_is_executed_with_constant_rate_and_args(args ...string) *f1Stage {
 	err := s.f1.ExecuteWithArgs(append([]string{
 		"run", "constant", s.scenario,
@@ -107,6 +250,71 @@ func (s *f1Stage) the_f1_scenario_is_executed_with_constant_rate_and_args(args .
 	return s
 }
 
+func (s *f1Stage) the_f1_command_is_executed_with_args(args ...string) *f1Stage {
+	s.executeErr = s.f1.ExecuteWithArgs(args)
+
+	return s
+}
+
+func (s *f1Stage) a_file_written_by_iterations() *f1Stage {
+	s.outputFile = filepath.Join(s.t.TempDir(), "iterations")
+
+	return s
+}
+
+// a_server_counting_pings starts a server which counts the requests to /ping as iterations.
+func (s *f1Stage) a_server_counting_pings() *f1Stage {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/ping" {
+			s.runCount.Add(1)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	s.t.Cleanup(server.Close)
+	s.serverURL = server.URL
+
+	return s
+}
+
+func (s *f1Stage) a_scenario_file_pinging_the_server() *f1Stage {
+	s.outputFile = filepath.Join(s.t.TempDir(), "ping.yaml")
+	content := "base-url: " + s.serverURL + "\nsteps:\n  - url: /ping\n    expect:\n      status: 204\n"
+	s.require.NoError(os.WriteFile(s.outputFile, []byte(content), 0o600))
+
+	return s
+}
+
+// a_har_file_pinging_www_example_com writes a HAR file recording a request to www.example.com/ping.
+func (s *f1Stage) a_har_file_pinging_www_example_com() *f1Stage {
+	s.outputFile = filepath.Join(s.t.TempDir(), "ping.har")
+	content := `{"log": {"entries": [{"startedDateTime": "2026-01-02T10:00:00Z", "time": 10,
+		"request": {"method": "GET", "url": "https://www.example.com/ping", "headers": []},
+		"response": {"status": 204}}]}}`
+	s.require.NoError(os.WriteFile(s.outputFile, []byte(content), 0o600))
+
+	return s
+}
+
+func (s *f1Stage) the_written_file_should_contain(expected string) *f1Stage {
+	written, err := os.ReadFile(s.outputFile)
+	s.require.NoError(err)
+	s.assert.Equal(expected, string(written))
+
+	return s
+}
+
+func (s *f1Stage) the_execute_command_succeeds() *f1Stage {
+	s.require.NoError(s.executeErr)
+
+	return s
+}
+
+func (s *f1Stage) expect_the_scenario_iterations_to_have_run(count uint32) *f1Stage {
+	s.assert.Equal(count, s.runCount.Load())
+
+	return s
+}
+
 func (s *f1Stage) an_unknown_f1_scenario_is_executed() *f1Stage {
 	s.executeErr = s.f1.ExecuteWithArgs([]string{
 		"run", "constant", "unknownScenario",
@@ -121,6 +329,64 @@ func (s *f1Stage) the_execute_command_returns_an_error(message string) *f1Stage
 	return s
 }
 
+func (s *f1Stage) the_run_result_should_have_iterations(successful, failed uint64) *f1Stage {
+	s.require.NoError(s.executeErr)
+	s.assert.Equal(successful, s.runResult.SuccessfulIterations, "successful iterations")
+	s.assert.Equal(failed, s.runResult.FailedIterations, "failed iterations")
+	s.assert.Zero(s.runResult.DroppedIterations, "dropped iterations")
+
+	return s
+}
+
+func (s *f1Stage) the_metrics_are_registered_on_a_registry() *f1Stage {
+	s.registry = prometheus.NewRegistry()
+	s.f1.WithMetricsRegisterer(s.registry)
+
+	return s
+}
+
+func (s *f1Stage) the_registry_should_hold_the_setup_of_one_run() *f1Stage {
+	families, err := s.registry.Gather()
+	s.require.NoError(err)
+
+	for _, family := range families {
+		if family.GetName() == "form3_loadtest_setup" {
+			s.require.Len(family.GetMetric(), 1)
+			s.assert.Equal(uint64(1), family.GetMetric()[0].GetSummary().GetSampleCount())
+			return s
+		}
+	}
+	s.t.Fatal("the setup metric isn't registered")
+
+	return s
+}
+
+func (s *f1Stage) the_run_result_should_have_passed() *f1Stage {
+	s.require.NoError(s.executeErr)
+	s.assert.True(s.runResult.Passed)
+	s.assert.NoError(s.runResult.Error)
+	s.assert.Positive(s.runResult.Duration)
+
+	return s
+}
+
+func (s *f1Stage) the_run_result_should_have_the_seed(seed uint64) *f1Stage {
+	s.require.NoError(s.executeErr)
+	s.assert.Equal(seed, s.runResult.Seed)
+
+	return s
+}
+
+func (s *f1Stage) the_run_result_should_have_failed_with(reason string, count uint64) *f1Stage {
+	s.require.NoError(s.executeErr)
+	s.assert.False(s.runResult.Passed)
+	s.require.NotEmpty(s.runResult.TopFailures)
+	s.assert.Equal(reason, s.runResult.TopFailures[0].Reason)
+	s.assert.Equal(count, s.runResult.TopFailures[0].Count)
+
+	return s
+}
+
 func (s *f1Stage) expect_the_scenario_iterations_to_have_run_no_more_than(count uint32) *f1Stage {
 	s.assert.Less(s.runCount.Load(), count)
 
@@ -5,6 +5,8 @@ import (
 	"syscall"
 	"testing"
 	"time"
+
+	"github.com/form3tech-oss/f1/v2/pkg/f1"
 )
 
 func TestSignalHandling(t *testing.T) {
@@ -62,3 +64,280 @@ func TestWithCustomLogger(t *testing.T) {
 	then.
 		expect_all_log_lines_to_contain_attr("custom", "value")
 }
+
+func TestRun(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	given.
+		a_scenario_where_each_iteration_takes(0)
+
+	when.
+		the_f1_scenario_is_run_with(f1.RunConfig{
+			Trigger:     f1.Constant("10/1s", f1.WithDistribution("none")),
+			MaxDuration: 2 * time.Second,
+		})
+
+	then.
+		the_run_result_should_have_iterations(20, 0).and().
+		the_run_result_should_have_passed().and().
+		expect_no_goroutines_to_run()
+}
+
+func TestRunWithSeed(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	given.
+		a_scenario_where_each_iteration_takes(0)
+
+	when.
+		the_f1_scenario_is_run_with(f1.RunConfig{
+			Trigger:     f1.Constant("10/1s", f1.WithJitter(50), f1.WithDistribution("random")),
+			MaxDuration: time.Second,
+			Seed:        42,
+		})
+
+	then.
+		the_run_result_should_have_passed().and().
+		the_run_result_should_have_the_seed(42)
+}
+
+func TestRunWithMetricsRegisterer(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	config := f1.RunConfig{Trigger: f1.Users(), Concurrency: 1, MaxIterations: 1, MaxDuration: time.Second}
+
+	given.
+		a_scenario_where_each_iteration_takes(0).and().
+		the_metrics_are_registered_on_a_registry()
+
+	when.
+		the_f1_scenario_is_run_with(config).and().
+		the_f1_scenario_is_run_with(config)
+
+	then.
+		the_run_result_should_have_passed().and().
+		the_registry_should_hold_the_setup_of_one_run()
+}
+
+func TestRunWithFailedIterations(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	given.
+		a_scenario_where_every_other_iteration_fails()
+
+	when.
+		the_f1_scenario_is_run_with(f1.RunConfig{
+			Trigger:       f1.Users(),
+			Concurrency:   1,
+			MaxIterations: 10,
+			MaxDuration:   10 * time.Second,
+		})
+
+	then.
+		the_run_result_should_have_iterations(5, 5).and().
+		the_run_result_should_have_failed_with("boom", 5)
+}
+
+func TestScenariosSharingAQueue(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	given.
+		a_producer_and_a_consumer_scenario_sharing_a_queue()
+
+	when.
+		the_scenarios_are_run_at_the_same_time_with(f1.RunConfig{
+			Trigger:       f1.Users(),
+			Concurrency:   2,
+			MaxIterations: 6,
+			MaxDuration:   10 * time.Second,
+		}, "create_account", "make_payment")
+
+	then.
+		the_consumer_should_have_taken_each_item_once("acc-1", "acc-2", "acc-3", "acc-4", "acc-5", "acc-6")
+}
+
+func TestQueuesAreEmptiedOnceTheirRunsEnd(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	config := f1.RunConfig{Trigger: f1.Users(), Concurrency: 1, MaxIterations: 3, MaxDuration: 10 * time.Second}
+
+	given.
+		a_producer_and_a_consumer_scenario_sharing_a_queue().and().
+		a_consumer_scenario_skipping_when_the_queue_is_empty()
+
+	when.
+		the_scenarios_are_run_at_the_same_time_with(config, "create_account").and().
+		the_scenarios_are_run_at_the_same_time_with(config, "check_account")
+
+	then.
+		the_consumer_should_have_taken_each_item_once("<nil>", "<nil>", "<nil>")
+}
+
+func TestRunMissingScenario(t *testing.T) {
+	_, when, then := newF1Stage(t)
+
+	when.
+		the_f1_scenario_is_run_with(f1.RunConfig{Trigger: f1.Constant("1/s")})
+
+	then.
+		the_execute_command_returns_an_error("scenario not defined: ")
+}
+
+func TestExecScenario(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	given.
+		a_file_written_by_iterations()
+
+	when.
+		the_f1_command_is_executed_with_args("run", "users",
+			"--exec", "echo {{.Iteration}} $F1_PARAM_REGION >> "+given.outputFile,
+			"--param", "region=eu",
+			"--concurrency", "1",
+			"--max-iterations", "3",
+		)
+
+	then.
+		the_execute_command_succeeds().and().
+		the_written_file_should_contain("1 eu\n2 eu\n3 eu\n")
+}
+
+func TestExecScenarioCanBeRunAgain(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	given.
+		a_file_written_by_iterations()
+
+	when.
+		the_f1_command_is_executed_with_args("run", "users",
+			"--exec", "echo {{.Iteration}} >> "+given.outputFile,
+			"--concurrency", "1", "--max-iterations", "1",
+		).and().
+		the_f1_command_is_executed_with_args("run", "users",
+			"--exec", "echo again >> "+given.outputFile,
+			"--concurrency", "1", "--max-iterations", "1",
+		)
+
+	then.
+		the_execute_command_succeeds().and().
+		the_written_file_should_contain("1\nagain\n")
+}
+
+func TestExecScenarioFails(t *testing.T) {
+	_, when, then := newF1Stage(t)
+
+	when.
+		the_f1_command_is_executed_with_args("run", "users", "--exec", "exit 1",
+			"--concurrency", "1", "--max-iterations", "1")
+
+	then.
+		the_execute_command_returns_an_error("load test failed")
+}
+
+func TestScenarioFile(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	given.
+		a_server_counting_pings().and().
+		a_scenario_file_pinging_the_server()
+
+	when.
+		the_f1_command_is_executed_with_args("run", "users", "--scenario-file", given.outputFile,
+			"--concurrency", "1", "--max-iterations", "3")
+
+	then.
+		the_execute_command_succeeds().and().
+		expect_the_scenario_iterations_to_have_run(3)
+}
+
+func TestScenarioFileCantBeUsedWithExec(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	given.
+		a_server_counting_pings().and().
+		a_scenario_file_pinging_the_server()
+
+	when.
+		the_f1_command_is_executed_with_args("run", "users", "--scenario-file", given.outputFile,
+			"--exec", "true", "--max-iterations", "1")
+
+	then.
+		the_execute_command_returns_an_error("--exec and --scenario-file can't be used together")
+}
+
+func TestHARReplay(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	given.
+		a_server_counting_pings().and().
+		a_har_file_pinging_www_example_com()
+
+	when.
+		the_f1_command_is_executed_with_args("run", "users", "--har", given.outputFile,
+			"--har-host", "www.example.com="+given.serverURL,
+			"--concurrency", "1", "--max-iterations", "3")
+
+	then.
+		the_execute_command_succeeds().and().
+		expect_the_scenario_iterations_to_have_run(3)
+}
+
+func TestCustomTrigger(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	given.
+		a_custom_trigger_mode("bursts").and().
+		a_scenario_where_each_iteration_takes(0)
+
+	when.
+		the_f1_command_is_executed_with_args("run", "bursts", "scenario_where_each_iteration_takes_0s",
+			"--iterations", "5", "--distribution", "none", "--max-iterations", "10", "--max-duration", "10s")
+
+	then.
+		the_execute_command_succeeds().and().
+		expect_the_scenario_iterations_to_have_run(10)
+}
+
+func TestCustomTriggerChart(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	given.
+		a_custom_trigger_mode("bursts")
+
+	when.
+		the_f1_command_is_executed_with_args("chart", "bursts", "--iterations", "5", "--chart-duration", "1s")
+
+	then.
+		the_execute_command_succeeds()
+}
+
+func TestCustomTriggerWithRun(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	given.
+		a_scenario_where_each_iteration_takes(0)
+
+	when.
+		the_f1_scenario_is_run_with(f1.RunConfig{
+			Trigger: f1.Custom(burstsTrigger("bursts"), f1.WithTriggerFlag("iterations", "5"),
+				f1.WithDistribution("none")),
+			MaxIterations: 10,
+			MaxDuration:   10 * time.Second,
+		})
+
+	then.
+		the_run_result_should_have_iterations(10, 0)
+}
+
+func TestCustomTriggerNamedAsABuiltInTrigger(t *testing.T) {
+	given, when, then := newF1Stage(t)
+
+	given.
+		a_custom_trigger_mode("constant")
+
+	when.
+		the_f1_command_is_executed_with_args("run", "constant", "scenario")
+
+	then.
+		the_execute_command_returns_an_error("trigger mode constant is already defined")
+}
@@ -0,0 +1,527 @@
+// Package f1test runs scenarios in go tests, to check that they work before running them as load
+// tests. Scenarios are run in memory: no log files are written, and metrics are recorded on a
+// registry of the run.
+//
+//	func TestMyScenario(t *testing.T) {
+//		result := f1test.RunScenario(t, setupMyScenario, f1test.Iterations(5), f1test.Concurrency(2))
+//
+//		assert.Len(t, result.StageDurations("call"), 5)
+//	}
+package f1test
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"sync"
+	gotesting "testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/form3tech-oss/f1/v2/internal/callbacks"
+	"github.com/form3tech-oss/f1/v2/internal/clock"
+	"github.com/form3tech-oss/f1/v2/internal/data"
+	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+	"github.com/form3tech-oss/f1/v2/internal/params"
+	"github.com/form3tech-oss/f1/v2/internal/progress"
+	"github.com/form3tech-oss/f1/v2/internal/queues"
+	"github.com/form3tech-oss/f1/v2/internal/workers"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/reporter"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+const scenarioName = "f1test"
+
+type options struct {
+	params         map[string]string
+	declared       []scenarios.ScenarioParameter
+	vuSetup        testing.VUSetupFn
+	data           *scenarios.DataFile
+	callbacks      *scenarios.Callbacks
+	queues         []scenarios.Queue
+	tagLabels      []string
+	iterations     int
+	concurrency    int
+	timeout        time.Duration
+	seed           uint64
+	expectFailures bool
+}
+
+// Option configures RunScenario.
+type Option func(*options)
+
+// Iterations sets the number of iterations to run, 1 by default.
+func Iterations(n int) Option {
+	return func(o *options) {
+		o.iterations = n
+	}
+}
+
+// Concurrency sets the number of virtual users running iterations at the same time, 1 by default.
+func Concurrency(n int) Option {
+	return func(o *options) {
+		o.concurrency = n
+	}
+}
+
+// Param sets the value of a scenario parameter, returned by T.Param.
+func Param(name, value string) Option {
+	return func(o *options) {
+		o.params[name] = value
+	}
+}
+
+// Parameter declares a parameter of the scenario with its default, as scenarios.Parameter does.
+// The default is returned by T.Param unless the value is set with Param.
+func Parameter(parameter scenarios.ScenarioParameter) Option {
+	return func(o *options) {
+		o.declared = append(o.declared, parameter)
+	}
+}
+
+// Timeout interrupts the run after d, as ctrl-c does: iterations stop being started, and the
+// context returned by T.Context is cancelled.
+func Timeout(d time.Duration) Option {
+	return func(o *options) {
+		o.timeout = d
+	}
+}
+
+// VUSetup runs fn once for each virtual user, before its iterations, as scenarios.WithVUSetup does.
+func VUSetup(fn testing.VUSetupFn) Option {
+	return func(o *options) {
+		o.vuSetup = fn
+	}
+}
+
+// Data sets the data file whose rows are returned by T.Data, as scenarios.WithData does.
+func Data(file scenarios.DataFile) Option {
+	return func(o *options) {
+		o.data = &file
+	}
+}
+
+// Callbacks starts a listener receiving the callbacks awaited with T.AwaitCallback, as
+// scenarios.WithCallbacks does. An Address of :0 binds a free port, returned by T.CallbackAddress.
+func Callbacks(config scenarios.Callbacks) Option {
+	return func(o *options) {
+		o.callbacks = &config
+	}
+}
+
+// Queue declares a queue returned by T.Queue, as scenarios.WithQueue does. Queues are only shared
+// by the setup and iterations of the scenario run.
+func Queue(queue scenarios.Queue) Option {
+	return func(o *options) {
+		o.queues = append(o.queues, queue)
+	}
+}
+
+// Seed sets the seed of the run, from which T.Rand is derived, as --seed does. It is 0 by default, so
+// that T.Rand makes the same choices in every go test run.
+func Seed(seed uint64) Option {
+	return func(o *options) {
+		o.seed = seed
+	}
+}
+
+// TagLabels adds the keys of tags as labels of the iteration metric, as scenarios.WithTagLabels does.
+func TagLabels(keys ...string) Option {
+	return func(o *options) {
+		o.tagLabels = append(o.tagLabels, keys...)
+	}
+}
+
+// ExpectFailures doesn't fail the go test when the setup, iterations or cleanup of the scenario
+// fail, so that failures can be asserted on the Result.
+func ExpectFailures() Option {
+	return func(o *options) {
+		o.expectFailures = true
+	}
+}
+
+// Result is the outcome of RunScenario.
+type Result struct {
+	// Metrics gathers the metrics recorded during the run, including the stages timed with T.Time.
+	Metrics prometheus.Gatherer
+	// Iterations are ordered by iteration number, and are empty if the setup failed. Iterations
+	// which weren't started before the Timeout are left out.
+	Iterations []Iteration
+	Setup      Iteration
+	// Callbacks counts the callbacks received, missing and unexpected, if Callbacks is set.
+	Callbacks CallbackCounts
+}
+
+// CallbackCounts are the number of callbacks received, missing and unexpected.
+type CallbackCounts = callbacks.Counts
+
+// Iteration is the outcome of an iteration, or of the setup.
+type Iteration struct {
+	FailureReason string
+	// Logs are the logs written during the iteration, including recovered panics. The logs of the
+	// setup include the logs of the setups of the virtual users.
+	Logs string
+	// Tags are the tags set with T.Tag.
+	Tags map[string]string
+	// Stages are the durations recorded with T.Time.
+	Stages   []testing.StageDuration
+	Duration time.Duration
+	// Iteration is the iteration number, starting at 1, or 0 for the setup.
+	Iteration uint64
+	// VUID is the virtual user which ran the iteration, or -1 for the setup.
+	VUID   int
+	Failed bool
+}
+
+// Failed returns the iterations which failed.
+func (r *Result) Failed() []Iteration {
+	var failed []Iteration
+	for _, iteration := range r.Iterations {
+		if iteration.Failed {
+			failed = append(failed, iteration)
+		}
+	}
+
+	return failed
+}
+
+// StageDurations returns the durations of the stage name timed with T.Time, in all iterations.
+func (r *Result) StageDurations(name string) []time.Duration {
+	var durations []time.Duration
+	for _, iteration := range r.Iterations {
+		for _, stage := range iteration.Stages {
+			if stage.Name == name {
+				durations = append(durations, stage.Duration)
+			}
+		}
+	}
+
+	return durations
+}
+
+// RunScenario runs the setup of the scenario, its iterations and its cleanup, as a run with the
+// users trigger mode does. Failures and panics are reported as errors of the go test with the logs
+// of the failed iteration, unless ExpectFailures is set.
+func RunScenario(tb gotesting.TB, scenarioFn testing.ScenarioFn, opts ...Option) *Result {
+	tb.Helper()
+
+	o := &options{params: map[string]string{}, iterations: 1, concurrency: 1}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	scenarioParams, err := params.New(o.declaredParams(), o.params)
+	if err != nil {
+		tb.Fatalf("scenario parameters: %v", err)
+	}
+
+	var feeder *data.Feeder
+	if o.data != nil {
+		feeder, err = data.Load(
+			o.data.Path, data.Mode(o.data.Mode), o.data.StopWhenExhausted, max(1, o.concurrency), o.seed,
+		)
+		if err != nil {
+			tb.Fatalf("scenario data: %v", err)
+		}
+		if limit := feeder.Limit(); limit > 0 && uint64(o.iterations) > limit {
+			o.iterations = int(limit)
+		}
+	}
+
+	if err := metrics.ValidateTagLabels(o.tagLabels); err != nil {
+		tb.Fatalf("scenario tag labels: %v", err)
+	}
+
+	registry := queues.NewRegistry()
+	queueBindings := make(map[string]queues.Binding, len(o.queues))
+	for _, queue := range o.queues {
+		queueBindings[queue.Name], err = registry.Bind(queue.Name, queue.Capacity, queues.EmptyPolicy(queue.WhenEmpty))
+		if err != nil {
+			tb.Fatalf("scenario queues: %v", err)
+		}
+	}
+
+	m := metrics.NewInstance(prometheus.NewRegistry(), true, nil, metrics.WithTagLabels(o.tagLabels...))
+
+	var listener *callbacks.Listener
+	if o.callbacks != nil {
+		listener, err = callbacks.Listen(callbacks.Config{
+			Address:  o.callbacks.Address,
+			IDHeader: o.callbacks.IDHeader,
+			IDPath:   o.callbacks.IDPath,
+		}, func(result callbacks.Result) {
+			m.RecordCallback(scenarioName, string(result))
+		})
+		if err != nil {
+			tb.Fatalf("scenario callbacks: %v", err)
+		}
+	}
+
+	logs := newIterationLogs()
+	r := newRecorder(o.iterations, logs)
+	logger := logs.logger()
+	scenario := &scenarios.Scenario{
+		Name:       scenarioName,
+		ScenarioFn: scenarioFn,
+		VUSetupFn:  o.vuSetup,
+	}
+	activeScenario := workers.NewActiveScenario(
+		scenario,
+		m,
+		&progress.Stats{},
+		logger,
+		log.NewSlogLogrusLogger(logger),
+		workers.ActiveScenarioOptions{
+			Params:   scenarioParams,
+			Feeder:   feeder,
+			Listener: listener,
+			Queues:   queueBindings,
+			// the logs of every iteration are flushed with its number, once its cleanup ran
+			FailedIterationLogs: log.NewFlushAll(),
+			Reporter:            r,
+			Seed:                o.seed,
+		},
+	)
+
+	setupStart := time.Now()
+	activeScenario.Setup()
+
+	result := &Result{Metrics: m.Registry}
+	result.Setup = Iteration{
+		FailureReason: activeScenario.FailureReason(),
+		Tags:          activeScenario.Tags(),
+		Stages:        activeScenario.StageDurations(),
+		Duration:      time.Since(setupStart),
+		VUID:          -1,
+	}
+
+	if !activeScenario.Failed() && scenario.RunFn != nil && o.iterations > 0 {
+		runIterations(activeScenario, o)
+		result.Iterations = r.iterations()
+	}
+
+	activeScenario.Teardown()
+	// failures of the cleanup functions are logged, and reported with the setup
+	result.Setup.Logs = logs.setupLogs()
+	result.Setup.Failed = activeScenario.Failed() || activeScenario.TeardownFailed()
+
+	if listener != nil {
+		if err := listener.Close(); err != nil {
+			tb.Errorf("%v", err)
+		}
+		result.Callbacks = listener.Counts()
+	}
+
+	if !o.expectFailures {
+		report(tb, result)
+	}
+
+	return result
+}
+
+// declaredParams returns the parameters declared with Parameter, and the ones set with Param
+// which aren't declared.
+func (o *options) declaredParams() []params.Declared {
+	declared := make([]params.Declared, 0, len(o.declared)+len(o.params))
+	for _, parameter := range o.declared {
+		declared = append(declared, params.Declared{Name: parameter.Name, Default: parameter.Default})
+	}
+	for name := range o.params {
+		if !slices.ContainsFunc(o.declared, func(p scenarios.ScenarioParameter) bool { return p.Name == name }) {
+			declared = append(declared, params.Declared{Name: name, Default: ""})
+		}
+	}
+
+	return declared
+}
+
+// runIterations runs the iterations with a pool of virtual users, until they all ran or the
+// timeout interrupted the run.
+func runIterations(activeScenario *workers.ActiveScenario, o *options) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if o.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, o.timeout)
+		defer cancel()
+	}
+	// iterations waiting on T.Context stop once the run is interrupted
+	stopInterrupt := context.AfterFunc(ctx, activeScenario.Interrupt)
+	defer stopInterrupt()
+
+	poolManager := workers.New(uint64(o.iterations), activeScenario, clock.Real{})
+	poolManager.NewContinuousPool(max(1, o.concurrency), workers.Pacing{}).Start(ctx)
+	<-poolManager.WaitForCompletion()
+}
+
+// recorder keeps the outcome and the logs of the iterations, which are reported once their cleanup
+// ran.
+type recorder struct {
+	logs    *iterationLogs
+	results []Iteration
+	mu      sync.Mutex
+}
+
+func newRecorder(iterations int, logs *iterationLogs) *recorder {
+	return &recorder{
+		logs:    logs,
+		results: make([]Iteration, iterations),
+	}
+}
+
+func (r *recorder) Report(event reporter.Event) {
+	finished, ok := event.(reporter.IterationFinished)
+	if !ok || finished.Result == reporter.ResultDropped {
+		return
+	}
+
+	var stages []testing.StageDuration
+	for _, stage := range finished.Stages {
+		stages = append(stages, testing.StageDuration{Name: stage.Name, Duration: stage.Duration})
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.results[finished.Iteration-1] = Iteration{
+		FailureReason: finished.Failure,
+		Logs:          r.logs.take(finished.Iteration),
+		Tags:          finished.Tags,
+		Stages:        stages,
+		Duration:      finished.Duration,
+		Iteration:     finished.Iteration,
+		VUID:          finished.VUID,
+		Failed:        finished.Result == reporter.ResultFailed || finished.CleanupFailed,
+	}
+}
+
+// iterations returns the outcome of the iterations which ran, in order.
+func (r *recorder) iterations() []Iteration {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ran := make([]Iteration, 0, len(r.results))
+	for _, iteration := range r.results {
+		if iteration.Iteration != 0 {
+			ran = append(ran, iteration)
+		}
+	}
+
+	return ran
+}
+
+func report(tb gotesting.TB, result *Result) {
+	tb.Helper()
+
+	if result.Setup.Failed {
+		tb.Errorf("setup failed: %s\n%s", result.Setup.FailureReason, result.Setup.Logs)
+	}
+
+	for _, iteration := range result.Failed() {
+		tb.Errorf("iteration %d (vuid %d) failed: %s\n%s",
+			iteration.Iteration, iteration.VUID, iteration.FailureReason, iteration.Logs)
+	}
+}
+
+// iterationKey is the key of log.IterationAttr, which the logs of an iteration are flushed with
+// once its cleanup ran.
+const iterationKey = "iteration"
+
+// iterationLogs keeps the logs of each iteration apart, and the logs of the setups together.
+type iterationLogs struct {
+	iterations map[uint64]*bytes.Buffer
+	// target is the buffer the record being handled is written to
+	target *bytes.Buffer
+	setup  bytes.Buffer
+	mu     sync.Mutex
+}
+
+func newIterationLogs() *iterationLogs {
+	return &iterationLogs{iterations: map[uint64]*bytes.Buffer{}}
+}
+
+// logger returns a logger writing the records with an iteration number to the logs of the
+// iteration, and the other records to the logs of the setup.
+func (l *iterationLogs) logger() *slog.Logger {
+	return slog.New(&iterationHandler{next: log.NewTestLogger(l).Handler(), logs: l})
+}
+
+// Write writes to the target buffer, with mu held by iterationHandler.Handle.
+func (l *iterationLogs) Write(p []byte) (int, error) {
+	n, err := l.target.Write(p)
+	if err != nil {
+		return n, fmt.Errorf("writing logs: %w", err)
+	}
+
+	return n, nil
+}
+
+// take returns the logs of iteration, and forgets them.
+func (l *iterationLogs) take(iteration uint64) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	buf, ok := l.iterations[iteration]
+	if !ok {
+		return ""
+	}
+	delete(l.iterations, iteration)
+
+	return buf.String()
+}
+
+func (l *iterationLogs) setupLogs() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.setup.String()
+}
+
+// iterationHandler routes records to the buffer of the iteration they belong to.
+type iterationHandler struct {
+	next slog.Handler
+	logs *iterationLogs
+}
+
+func (h *iterationHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *iterationHandler) Handle(ctx context.Context, record slog.Record) error {
+	h.logs.mu.Lock()
+	defer h.logs.mu.Unlock()
+
+	h.logs.target = &h.logs.setup
+	record.Attrs(func(a slog.Attr) bool {
+		if a.Key != iterationKey {
+			return true
+		}
+		// the setups are logged with the iterations "setup" and "vu setup"
+		if iteration, err := strconv.ParseUint(a.Value.String(), 10, 64); err == nil {
+			if _, ok := h.logs.iterations[iteration]; !ok {
+				h.logs.iterations[iteration] = &bytes.Buffer{}
+			}
+			h.logs.target = h.logs.iterations[iteration]
+		}
+		return false
+	})
+
+	if err := h.next.Handle(ctx, record); err != nil {
+		return fmt.Errorf("handling log record: %w", err)
+	}
+
+	return nil
+}
+
+func (h *iterationHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &iterationHandler{next: h.next.WithAttrs(attrs), logs: h.logs}
+}
+
+func (h *iterationHandler) WithGroup(name string) slog.Handler {
+	return &iterationHandler{next: h.next.WithGroup(name), logs: h.logs}
+}
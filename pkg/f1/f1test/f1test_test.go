@@ -0,0 +1,322 @@
+package f1test_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	gotesting "testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/pkg/f1/f1test"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+func TestRunScenario_RunsSetupIterationsAndCleanup(t *gotesting.T) {
+	t.Parallel()
+
+	var setups, iterations, cleanups atomic.Int32
+	result := f1test.RunScenario(t, func(t *testing.T) testing.RunFn {
+		setups.Add(1)
+		t.Cleanup(func() { cleanups.Add(1) })
+
+		return func(t *testing.T) {
+			iterations.Add(1)
+			t.Cleanup(func() { cleanups.Add(1) })
+			t.Time("call", func() {})
+		}
+	}, f1test.Iterations(5), f1test.Concurrency(2))
+
+	assert.Equal(t, int32(1), setups.Load())
+	assert.Equal(t, int32(5), iterations.Load())
+	assert.Equal(t, int32(6), cleanups.Load())
+
+	require.Len(t, result.Iterations, 5)
+	assert.Empty(t, result.Failed())
+	assert.Len(t, result.StageDurations("call"), 5)
+	for i, iteration := range result.Iterations {
+		assert.Equal(t, uint64(i+1), iteration.Iteration)
+		assert.Contains(t, []int{0, 1}, iteration.VUID)
+	}
+
+	families, err := result.Metrics.Gather()
+	require.NoError(t, err)
+	assert.NotEmpty(t, families)
+}
+
+func TestRunScenario_SetsParams(t *gotesting.T) {
+	t.Parallel()
+
+	var value atomic.Value
+	f1test.RunScenario(t, func(*testing.T) testing.RunFn {
+		return func(t *testing.T) {
+			value.Store(t.Param("region"))
+		}
+	}, f1test.Param("region", "eu"))
+
+	assert.Equal(t, "eu", value.Load())
+}
+
+func TestRunScenario_AppliesParameterDefaults(t *gotesting.T) {
+	t.Parallel()
+
+	var region, currency atomic.Value
+	f1test.RunScenario(t, func(*testing.T) testing.RunFn {
+		return func(t *testing.T) {
+			region.Store(t.Param("region"))
+			currency.Store(t.Param("currency"))
+		}
+	},
+		f1test.Parameter(scenarios.ScenarioParameter{Name: "region", Default: "us"}),
+		f1test.Parameter(scenarios.ScenarioParameter{Name: "currency", Default: "USD"}),
+		f1test.Param("region", "eu"),
+	)
+
+	assert.Equal(t, "eu", region.Load())
+	assert.Equal(t, "USD", currency.Load())
+}
+
+func TestRunScenario_CancelsTheContextOnTimeout(t *gotesting.T) {
+	t.Parallel()
+
+	start := time.Now()
+	result := f1test.RunScenario(t, func(*testing.T) testing.RunFn {
+		return func(t *testing.T) {
+			<-t.Context().Done()
+			t.Errorf("interrupted: %v", t.Context().Err())
+		}
+	}, f1test.Iterations(10), f1test.Concurrency(2), f1test.Timeout(50*time.Millisecond), f1test.ExpectFailures())
+
+	assert.Less(t, time.Since(start), 5*time.Second)
+	require.NotEmpty(t, result.Iterations)
+	for _, iteration := range result.Iterations {
+		assert.Equal(t, "interrupted: context canceled", iteration.FailureReason)
+	}
+}
+
+func TestRunScenario_SetsUpVirtualUsers(t *gotesting.T) {
+	t.Parallel()
+
+	var setups, teardowns, mismatches atomic.Int32
+	f1test.RunScenario(t, func(*testing.T) testing.RunFn {
+		return func(t *testing.T) {
+			if t.VU() != t.VUID {
+				mismatches.Add(1)
+			}
+		}
+	}, f1test.Iterations(6), f1test.Concurrency(2), f1test.VUSetup(func(t *testing.T) any {
+		setups.Add(1)
+		t.Cleanup(func() { teardowns.Add(1) })
+		return t.VUID
+	}))
+
+	assert.Equal(t, int32(2), setups.Load())
+	assert.Equal(t, int32(2), teardowns.Load())
+	assert.Zero(t, mismatches.Load())
+}
+
+func TestRunScenario_FeedsData(t *gotesting.T) {
+	t.Parallel()
+
+	var accounts sync.Map
+	result := f1test.RunScenario(t, func(*testing.T) testing.RunFn {
+		return func(t *testing.T) {
+			accounts.Store(t.Data()["account_id"], true)
+		}
+	}, f1test.Iterations(10), f1test.Data(scenarios.DataFile{
+		Path:              "../../../internal/testdata/accounts.csv",
+		Mode:              scenarios.DataUnique,
+		StopWhenExhausted: true,
+	}))
+
+	assert.Len(t, result.Iterations, 4)
+	for _, account := range []string{"acc-1", "acc-2", "acc-3", "acc-4"} {
+		_, ok := accounts.Load(account)
+		assert.True(t, ok, "account %s was not used", account)
+	}
+}
+
+func TestRunScenario_RecordsTags(t *gotesting.T) {
+	t.Parallel()
+
+	result := f1test.RunScenario(t, func(*testing.T) testing.RunFn {
+		return func(t *testing.T) {
+			t.Tag("tenant", "tenant-"+t.Iteration)
+			t.Tag("scheme", "sepa")
+			t.Log("tagged")
+		}
+	}, f1test.Iterations(2), f1test.TagLabels("tenant"))
+
+	require.Len(t, result.Iterations, 2)
+	assert.Equal(t, map[string]string{"tenant": "tenant-1", "scheme": "sepa"}, result.Iterations[0].Tags)
+	assert.Contains(t, result.Iterations[1].Logs, "tags.tenant=tenant-2")
+
+	families, err := result.Metrics.Gather()
+	require.NoError(t, err)
+	var tenants []string
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "tenant" {
+					tenants = append(tenants, label.GetValue())
+				}
+			}
+		}
+	}
+	assert.ElementsMatch(t, []string{"tenant-1", "tenant-2"}, tenants)
+}
+
+func TestRunScenario_AwaitsCallbacks(t *gotesting.T) {
+	t.Parallel()
+
+	result := f1test.RunScenario(t, func(t *testing.T) testing.RunFn {
+		url := "http://" + t.CallbackAddress()
+		send(t, url, `{"data": {"id": "unknown"}}`)
+
+		return func(t *testing.T) {
+			id := "payment-" + t.Iteration
+			t.ExpectCallback(id)
+			// the third iteration's callback is never sent
+			if t.Iteration != "3" {
+				go send(t, url, `{"data": {"id": "`+id+`"}}`)
+			}
+
+			callback := t.AwaitCallback(id, time.Second)
+			t.Logf("received %s", callback.Body)
+		}
+	}, f1test.Iterations(3), f1test.ExpectFailures(), f1test.Callbacks(scenarios.Callbacks{
+		Address: "127.0.0.1:0",
+		IDPath:  "data.id",
+	}))
+
+	assert.Equal(t, f1test.CallbackCounts{Received: 2, Missing: 1, Unexpected: 1}, result.Callbacks)
+	assert.Len(t, result.StageDurations(testing.CallbackStage), 2)
+	assert.Contains(t, result.Iterations[0].Logs, `received {\"data\": {\"id\": \"payment-1\"}}`)
+	require.Len(t, result.Failed(), 1)
+	assert.Equal(t, "callback payment-3 not received within 1s", result.Failed()[0].FailureReason)
+}
+
+// send posts a callback to url.
+func send(t *testing.T, url, body string) {
+	req, err := http.NewRequestWithContext(t.Context(), http.MethodPost, url, strings.NewReader(body))
+	if err != nil {
+		t.Errorf("creating callback: %v", err)
+		return
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Errorf("sending callback: %v", err)
+		return
+	}
+	_ = res.Body.Close()
+}
+
+func TestRunScenario_SharesQueues(t *gotesting.T) {
+	t.Parallel()
+
+	for _, test := range []struct {
+		whenEmpty scenarios.QueueEmptyPolicy
+		taken     int32
+		failed    int
+	}{
+		{whenEmpty: scenarios.QueueSkip, taken: 3, failed: 0},
+		{whenEmpty: scenarios.QueueFail, taken: 3, failed: 2},
+	} {
+		t.Run(string(test.whenEmpty), func(t *gotesting.T) {
+			t.Parallel()
+
+			var taken atomic.Int32
+			result := f1test.RunScenario(t, func(t *testing.T) testing.RunFn {
+				for i := range 3 {
+					t.Queue("accounts").Put(fmt.Sprintf("acc-%d", i))
+				}
+
+				return func(t *testing.T) {
+					if _, ok := t.Queue("accounts").Take(t.Context()); ok {
+						taken.Add(1)
+					}
+				}
+			}, f1test.Iterations(5), f1test.ExpectFailures(), f1test.Queue(scenarios.Queue{
+				Name:      "accounts",
+				Capacity:  3,
+				WhenEmpty: test.whenEmpty,
+			}))
+
+			assert.Equal(t, test.taken, taken.Load())
+			require.Len(t, result.Failed(), test.failed)
+			for _, failed := range result.Failed() {
+				assert.Equal(t, "queue accounts is empty", failed.FailureReason)
+			}
+		})
+	}
+}
+
+func TestRunScenario_ReturnsFailedIterations(t *gotesting.T) {
+	t.Parallel()
+
+	result := f1test.RunScenario(t, func(*testing.T) testing.RunFn {
+		return func(t *testing.T) {
+			if t.Iteration == "2" {
+				t.Logger().Info("about to fail")
+				panic(errors.New("boom"))
+			}
+		}
+	}, f1test.Iterations(3), f1test.ExpectFailures())
+
+	failed := result.Failed()
+	require.Len(t, failed, 1)
+	assert.Equal(t, uint64(2), failed[0].Iteration)
+	assert.Equal(t, "panic: boom", failed[0].FailureReason)
+	assert.Contains(t, failed[0].Logs, "about to fail")
+	assert.Contains(t, failed[0].Logs, "recovered panic in scenario")
+}
+
+func TestRunScenario_ReportsFailuresAsTestErrors(t *gotesting.T) {
+	t.Parallel()
+
+	tb := &recordingTB{TB: t}
+	f1test.RunScenario(tb, func(*testing.T) testing.RunFn {
+		return func(t *testing.T) {
+			t.Logger().Info("calling the service")
+			t.Errorf("unexpected status %d", 500)
+		}
+	}, f1test.Iterations(2))
+
+	require.Len(t, tb.errors, 2)
+	assert.Contains(t, tb.errors[0], "failed: unexpected status 500")
+	assert.Contains(t, tb.errors[0], "calling the service")
+}
+
+func TestRunScenario_DoesNotRunIterationsWhenSetupFails(t *gotesting.T) {
+	t.Parallel()
+
+	var iterations atomic.Int32
+	result := f1test.RunScenario(t, func(t *testing.T) testing.RunFn {
+		t.FailNow()
+		return func(*testing.T) {
+			iterations.Add(1)
+		}
+	}, f1test.ExpectFailures())
+
+	assert.True(t, result.Setup.Failed)
+	assert.Empty(t, result.Iterations)
+	assert.Zero(t, iterations.Load())
+}
+
+// recordingTB records errors instead of failing the test
+type recordingTB struct {
+	gotesting.TB
+	errors []string
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
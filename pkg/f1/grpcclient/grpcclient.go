@@ -0,0 +1,200 @@
+// Package grpcclient records the latency of gRPC calls made by scenarios as stages of the
+// iteration, as T.Time does, with client interceptors which take the T from the call context.
+//
+//	conn, err := grpc.NewClient(target,
+//		grpc.WithUnaryInterceptor(grpcclient.UnaryClientInterceptor(grpcclient.ErrorOnNonOK())),
+//		grpc.WithStreamInterceptor(grpcclient.StreamClientInterceptor(grpcclient.ErrorOnNonOK())),
+//	)
+//	...
+//	return func(t *testing.T) {
+//		res, err := client.GetAccount(grpcclient.WithT(context.Background(), t), req)
+//		...
+//	}
+//
+// The call above records the stage "grpc /accounts.v1.Accounts/GetAccount OK", with the status
+// code of the call, so that the iteration metric also counts the calls with each code. Calls whose
+// context doesn't have a T aren't recorded.
+package grpcclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+const defaultName = "grpc"
+
+type failMode int
+
+const (
+	ignoreFailures failMode = iota
+	errorOnFailure
+	fatalOnFailure
+)
+
+type options struct {
+	name    string
+	allowed []codes.Code
+	fail    failMode
+}
+
+// Option configures the interceptors.
+type Option func(*options)
+
+// WithName sets the prefix of the stage names, "grpc" by default.
+func WithName(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+// ErrorOnNonOK fails the iteration when a call returns a code other than OK, and continues the
+// iteration, as T.Error does.
+func ErrorOnNonOK() Option {
+	return func(o *options) {
+		o.fail = errorOnFailure
+	}
+}
+
+// FatalOnNonOK fails the iteration when a call returns a code other than OK, and stops the
+// iteration, as T.Fatal does. Calls must be made from the goroutine running the iteration.
+func FatalOnNonOK() Option {
+	return func(o *options) {
+		o.fail = fatalOnFailure
+	}
+}
+
+// AllowCodes doesn't fail the iteration for the codes, such as NotFound, with ErrorOnNonOK or
+// FatalOnNonOK.
+func AllowCodes(allowed ...codes.Code) Option {
+	return func(o *options) {
+		o.allowed = append(o.allowed, allowed...)
+	}
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{name: defaultName}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+type tKey struct{}
+
+// WithT returns a context for calls which are recorded on t.
+func WithT(ctx context.Context, t *testing.T) context.Context {
+	return context.WithValue(ctx, tKey{}, t)
+}
+
+func fromContext(ctx context.Context) *testing.T {
+	t, _ := ctx.Value(tKey{}).(*testing.T)
+	return t
+}
+
+// UnaryClientInterceptor records the latency and status code of unary calls.
+func UnaryClientInterceptor(opts ...Option) grpc.UnaryClientInterceptor {
+	o := newOptions(opts)
+
+	return func(
+		ctx context.Context,
+		method string,
+		req, reply any,
+		cc *grpc.ClientConn,
+		invoker grpc.UnaryInvoker,
+		callOpts ...grpc.CallOption,
+	) error {
+		t := fromContext(ctx)
+		if t == nil {
+			return invoker(ctx, method, req, reply, cc, callOpts...)
+		}
+
+		start := time.Now()
+		err := invoker(ctx, method, req, reply, cc, callOpts...)
+		o.record(t, method, start, err)
+
+		return err
+	}
+}
+
+// StreamClientInterceptor records the latency and status code of streaming calls, from when the
+// stream is opened until it ends. Streams are recorded once a receive returns an error or
+// io.EOF, so streams which aren't received until the end aren't recorded.
+func StreamClientInterceptor(opts ...Option) grpc.StreamClientInterceptor {
+	o := newOptions(opts)
+
+	return func(
+		ctx context.Context,
+		desc *grpc.StreamDesc,
+		cc *grpc.ClientConn,
+		method string,
+		streamer grpc.Streamer,
+		callOpts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		t := fromContext(ctx)
+		if t == nil {
+			return streamer(ctx, desc, cc, method, callOpts...)
+		}
+
+		start := time.Now()
+		stream, err := streamer(ctx, desc, cc, method, callOpts...)
+		if err != nil {
+			o.record(t, method, start, err)
+			return nil, err
+		}
+
+		return &recordedStream{ClientStream: stream, options: o, t: t, method: method, start: start}, nil
+	}
+}
+
+type recordedStream struct {
+	grpc.ClientStream
+	options  *options
+	t        *testing.T
+	start    time.Time
+	method   string
+	recorded sync.Once
+}
+
+func (s *recordedStream) RecvMsg(m any) error {
+	err := s.ClientStream.RecvMsg(m)
+	if err != nil {
+		s.recorded.Do(func() {
+			if errors.Is(err, io.EOF) {
+				s.options.record(s.t, s.method, s.start, nil)
+			} else {
+				s.options.record(s.t, s.method, s.start, err)
+			}
+		})
+	}
+
+	return err
+}
+
+func (o *options) record(t *testing.T, method string, start time.Time, err error) {
+	code := status.Code(err)
+	stage := o.name + " " + method
+	t.RecordStage(stage+" "+code.String(), time.Since(start))
+
+	if code == codes.OK || slices.Contains(o.allowed, code) {
+		return
+	}
+
+	switch o.fail {
+	case errorOnFailure:
+		t.Errorf("%s returned %s: %s", stage, code, status.Convert(err).Message())
+	case fatalOnFailure:
+		t.Fatalf("%s returned %s: %s", stage, code, status.Convert(err).Message())
+	case ignoreFailures:
+	}
+}
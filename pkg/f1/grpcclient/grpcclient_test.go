@@ -0,0 +1,172 @@
+package grpcclient_test
+
+import (
+	"context"
+	"net"
+	gotesting "testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/form3tech-oss/f1/v2/pkg/f1/f1test"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/grpcclient"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+const (
+	checkMethod = "/grpc.health.v1.Health/Check"
+	watchMethod = "/grpc.health.v1.Health/Watch"
+)
+
+func TestUnaryClientInterceptor_RecordsCallsByCode(t *gotesting.T) {
+	t.Parallel()
+
+	client := newHealthClient(t, grpcclient.WithName("health"))
+
+	result := f1test.RunScenario(t, func(*testing.T) testing.RunFn {
+		return func(t *testing.T) {
+			ctx := grpcclient.WithT(context.Background(), t)
+
+			_, err := client.Check(ctx, &healthpb.HealthCheckRequest{Service: "accounts"})
+			require.NoError(t, err)
+			_, err = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "unknown"})
+			assert.Equal(t, codes.NotFound, status.Code(err))
+		}
+	})
+
+	assert.Len(t, result.StageDurations("health "+checkMethod+" OK"), 1)
+	assert.Len(t, result.StageDurations("health "+checkMethod+" NotFound"), 1)
+	assert.False(t, result.Iterations[0].Failed)
+}
+
+func TestUnaryClientInterceptor_DoesNotRecordCallsWithoutT(t *gotesting.T) {
+	t.Parallel()
+
+	client := newHealthClient(t)
+
+	result := f1test.RunScenario(t, func(*testing.T) testing.RunFn {
+		return func(t *testing.T) {
+			_, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: "accounts"})
+			require.NoError(t, err)
+		}
+	})
+
+	assert.Empty(t, result.Iterations[0].Stages)
+}
+
+func TestUnaryClientInterceptor_ErrorOnNonOK(t *gotesting.T) {
+	t.Parallel()
+
+	client := newHealthClient(t, grpcclient.ErrorOnNonOK())
+
+	result := f1test.RunScenario(t, func(*testing.T) testing.RunFn {
+		return func(t *testing.T) {
+			ctx := grpcclient.WithT(context.Background(), t)
+			_, _ = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "unknown"})
+			_, _ = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "accounts"})
+		}
+	}, f1test.ExpectFailures())
+
+	require.Len(t, result.Failed(), 1)
+	assert.Equal(t, "grpc "+checkMethod+" returned NotFound: unknown service", result.Iterations[0].FailureReason)
+	// the iteration continued after the failure
+	assert.Len(t, result.StageDurations("grpc "+checkMethod+" OK"), 1)
+}
+
+func TestUnaryClientInterceptor_FatalOnNonOK(t *gotesting.T) {
+	t.Parallel()
+
+	client := newHealthClient(t, grpcclient.FatalOnNonOK())
+
+	result := f1test.RunScenario(t, func(*testing.T) testing.RunFn {
+		return func(t *testing.T) {
+			ctx := grpcclient.WithT(context.Background(), t)
+			_, _ = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "unknown"})
+			_, _ = client.Check(ctx, &healthpb.HealthCheckRequest{Service: "accounts"})
+		}
+	}, f1test.ExpectFailures())
+
+	require.Len(t, result.Failed(), 1)
+	assert.Empty(t, result.StageDurations("grpc "+checkMethod+" OK"))
+}
+
+func TestUnaryClientInterceptor_AllowCodes(t *gotesting.T) {
+	t.Parallel()
+
+	client := newHealthClient(t, grpcclient.ErrorOnNonOK(), grpcclient.AllowCodes(codes.NotFound))
+
+	result := f1test.RunScenario(t, func(*testing.T) testing.RunFn {
+		return func(t *testing.T) {
+			_, _ = client.Check(grpcclient.WithT(context.Background(), t),
+				&healthpb.HealthCheckRequest{Service: "unknown"})
+		}
+	})
+
+	assert.False(t, result.Iterations[0].Failed)
+	assert.Len(t, result.StageDurations("grpc "+checkMethod+" NotFound"), 1)
+}
+
+func TestStreamClientInterceptor_RecordsStreamsWhenTheyEnd(t *gotesting.T) {
+	t.Parallel()
+
+	client := newHealthClient(t)
+
+	result := f1test.RunScenario(t, func(*testing.T) testing.RunFn {
+		return func(t *testing.T) {
+			ctx, cancel := context.WithCancel(grpcclient.WithT(context.Background(), t))
+			defer cancel()
+
+			stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{Service: "accounts"})
+			require.NoError(t, err)
+			res, err := stream.Recv()
+			require.NoError(t, err)
+			assert.Equal(t, healthpb.HealthCheckResponse_SERVING, res.GetStatus())
+
+			cancel()
+			_, err = stream.Recv()
+			assert.Equal(t, codes.Canceled, status.Code(err))
+			_, err = stream.Recv()
+			assert.Error(t, err)
+		}
+	})
+
+	assert.Len(t, result.StageDurations("grpc "+watchMethod+" Canceled"), 1)
+}
+
+// newHealthClient returns a client of an in-process health server, where the service "accounts"
+// is serving.
+func newHealthClient(t *gotesting.T, opts ...grpcclient.Option) healthpb.HealthClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := grpc.NewServer()
+	healthServer := health.NewServer()
+	healthServer.SetServingStatus("accounts", healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(server, healthServer)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithUnaryInterceptor(grpcclient.UnaryClientInterceptor(opts...)),
+		grpc.WithStreamInterceptor(grpcclient.StreamClientInterceptor(opts...)),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return healthpb.NewHealthClient(conn)
+}
@@ -0,0 +1,207 @@
+// Package httpclient records the phases of HTTP requests made by scenarios as stages of the
+// iteration, as T.Time does, so that DNS, connect, TLS and time to first byte can be told apart.
+//
+//	func(t *testing.T) {
+//		client := httpclient.New(t, httpclient.FailOnServerErrors())
+//
+//		ctx := httpclient.WithRoute(context.Background(), "/accounts/{id}")
+//		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/accounts/"+id, nil)
+//		res, err := client.Do(req)
+//		...
+//	}
+//
+// The request above records the stages "http GET /accounts/{id} dns", "... connect", "... tls",
+// "... ttfb", and "... 2xx" with the duration until the response headers were received. Phases
+// which didn't happen, such as connecting on a reused connection, aren't recorded.
+package httpclient
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptrace"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+const (
+	defaultName = "http"
+
+	// ErrorStage is the status class recorded for requests which failed without a response
+	ErrorStage = "error"
+)
+
+// Option configures a Transport.
+type Option func(*Transport)
+
+// WithBase sets the transport which sends requests, http.DefaultTransport by default. The base
+// transport should be shared between iterations, so that connections are reused.
+func WithBase(base http.RoundTripper) Option {
+	return func(tr *Transport) {
+		tr.base = base
+	}
+}
+
+// WithName sets the prefix of the stage names, "http" by default.
+func WithName(name string) Option {
+	return func(tr *Transport) {
+		tr.name = name
+	}
+}
+
+// FailOnServerErrors fails the iteration when a response has a 5xx status code.
+func FailOnServerErrors() Option {
+	return func(tr *Transport) {
+		tr.failOnServerErrors = true
+	}
+}
+
+type routeKey struct{}
+
+// WithRoute returns a context for requests whose stages are named after route, a template such
+// as "/accounts/{id}". Without a route, stages are only named after the request method, as paths
+// with ids would create a metric series for each request.
+func WithRoute(ctx context.Context, route string) context.Context {
+	return context.WithValue(ctx, routeKey{}, route)
+}
+
+// Transport is an http.RoundTripper which records the phases of requests on the T of an iteration.
+type Transport struct {
+	t                  *testing.T
+	base               http.RoundTripper
+	name               string
+	failOnServerErrors bool
+}
+
+var _ http.RoundTripper = (*Transport)(nil)
+
+// NewTransport returns a transport which records the phases of requests on t.
+func NewTransport(t *testing.T, opts ...Option) *Transport {
+	tr := &Transport{t: t, base: http.DefaultTransport, name: defaultName}
+	for _, opt := range opts {
+		opt(tr)
+	}
+
+	return tr
+}
+
+// New returns a client whose requests record their phases on t. Clients are cheap to create for
+// each iteration, as connections are pooled by the base transport.
+func New(t *testing.T, opts ...Option) *http.Client {
+	return &http.Client{Transport: NewTransport(t, opts...)}
+}
+
+func (tr *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	stage := tr.name + " " + req.Method
+	if route, ok := req.Context().Value(routeKey{}).(string); ok && route != "" {
+		stage += " " + route
+	}
+
+	phases := &phases{}
+	start := time.Now()
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), phases.trace(start)))
+
+	res, err := tr.base.RoundTrip(req)
+	duration := time.Since(start)
+
+	for _, phase := range phases.recorded() {
+		tr.t.RecordStage(stage+" "+phase.name, phase.duration)
+	}
+
+	if err != nil {
+		tr.t.RecordStage(stage+" "+ErrorStage, duration)
+		return nil, err
+	}
+	tr.t.RecordStage(stage+" "+StatusClass(res.StatusCode), duration)
+
+	if tr.failOnServerErrors && res.StatusCode >= http.StatusInternalServerError {
+		tr.t.Errorf("%s returned %s", stage, res.Status)
+	}
+
+	return res, nil
+}
+
+// StatusClass returns the class of a status code, such as "2xx".
+func StatusClass(code int) string {
+	return strconv.Itoa(code/100) + "xx"
+}
+
+type phase struct {
+	name     string
+	duration time.Duration
+}
+
+// phases are the durations of the phases of a request. Dials may be traced concurrently.
+type phases struct {
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	measured     []phase
+	mu           sync.Mutex
+}
+
+func (p *phases) trace(start time.Time) *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			p.dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			p.since("dns", &p.dnsStart)
+		},
+		ConnectStart: func(string, string) {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			if p.connectStart.IsZero() {
+				p.connectStart = time.Now()
+			}
+		},
+		ConnectDone: func(_, _ string, err error) {
+			if err == nil {
+				p.since("connect", &p.connectStart)
+			}
+		},
+		TLSHandshakeStart: func() {
+			p.mu.Lock()
+			defer p.mu.Unlock()
+			p.tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			if err == nil {
+				p.since("tls", &p.tlsStart)
+			}
+		},
+		GotFirstResponseByte: func() {
+			p.since("ttfb", &start)
+		},
+	}
+}
+
+// since measures the phase name from start, which is read with the lock held. Only the first
+// measurement of each phase is kept.
+func (p *phases) since(name string, start *time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if start.IsZero() {
+		return
+	}
+	for _, measured := range p.measured {
+		if measured.name == name {
+			return
+		}
+	}
+	p.measured = append(p.measured, phase{name: name, duration: time.Since(*start)})
+}
+
+func (p *phases) recorded() []phase {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return slices.Clone(p.measured)
+}
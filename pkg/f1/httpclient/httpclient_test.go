@@ -0,0 +1,109 @@
+package httpclient_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	gotesting "testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/pkg/f1/f1test"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/httpclient"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/testing"
+)
+
+func TestTransport_RecordsThePhasesOfRequests(t *gotesting.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	t.Cleanup(server.Close)
+	base := server.Client().Transport
+
+	result := f1test.RunScenario(t, func(*testing.T) testing.RunFn {
+		return func(t *testing.T) {
+			client := httpclient.New(t, httpclient.WithBase(base))
+			ctx := httpclient.WithRoute(context.Background(), "/accounts/{id}")
+
+			for _, id := range []string{"1", "2"} {
+				req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/accounts/"+id, nil)
+				require.NoError(t, err)
+				res, err := client.Do(req)
+				require.NoError(t, err)
+				require.NoError(t, res.Body.Close())
+			}
+		}
+	})
+
+	// the second request reuses the connection of the first
+	assert.Len(t, result.StageDurations("http POST /accounts/{id} connect"), 1)
+	assert.Len(t, result.StageDurations("http POST /accounts/{id} tls"), 1)
+	assert.Len(t, result.StageDurations("http POST /accounts/{id} ttfb"), 2)
+	assert.Len(t, result.StageDurations("http POST /accounts/{id} 2xx"), 2)
+}
+
+func TestTransport_CountsStatusClasses(t *gotesting.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	result := f1test.RunScenario(t, func(*testing.T) testing.RunFn {
+		return func(t *testing.T) {
+			client := httpclient.New(t, httpclient.WithName("accounts"))
+			for _, path := range []string{"/", "/missing", "/missing"} {
+				res, err := client.Get(server.URL + path)
+				require.NoError(t, err)
+				require.NoError(t, res.Body.Close())
+			}
+		}
+	})
+
+	assert.Len(t, result.StageDurations("accounts GET 2xx"), 1)
+	assert.Len(t, result.StageDurations("accounts GET 4xx"), 2)
+	assert.False(t, result.Iterations[0].Failed)
+}
+
+func TestTransport_FailsOnServerErrors(t *gotesting.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+
+	result := f1test.RunScenario(t, func(*testing.T) testing.RunFn {
+		return func(t *testing.T) {
+			res, err := httpclient.New(t, httpclient.FailOnServerErrors()).Get(server.URL)
+			require.NoError(t, err)
+			require.NoError(t, res.Body.Close())
+		}
+	}, f1test.ExpectFailures())
+
+	require.Len(t, result.Failed(), 1)
+	assert.Equal(t, "http GET returned 503 Service Unavailable", result.Iterations[0].FailureReason)
+	assert.Len(t, result.StageDurations("http GET 5xx"), 1)
+}
+
+func TestTransport_RecordsRequestErrors(t *gotesting.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+
+	result := f1test.RunScenario(t, func(*testing.T) testing.RunFn {
+		return func(t *testing.T) {
+			_, err := httpclient.New(t).Get(server.URL)
+			assert.Error(t, err)
+		}
+	})
+
+	assert.Len(t, result.StageDurations("http GET "+httpclient.ErrorStage), 1)
+}
@@ -4,7 +4,14 @@ import (
 	internal_metrics "github.com/form3tech-oss/f1/v2/internal/metrics"
 )
 
+// GetMetrics returns the most recently created metrics, as each run has its own metrics, or new
+// metrics if no run has created any yet.
+//
 // Deprecated: internal metrics will not be exposed in future versions
 func GetMetrics() *internal_metrics.Metrics {
-	return internal_metrics.Instance()
+	if latest := internal_metrics.Latest(); latest != nil {
+		return latest
+	}
+
+	return internal_metrics.New(false, nil)
 }
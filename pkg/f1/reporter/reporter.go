@@ -0,0 +1,184 @@
+// Package reporter is the API for receiving the events of a run, which are registered with
+// F1.WithReporter. Reporters can, for example, stream the progress and results of runs to other systems.
+package reporter
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Reporter receives the events of a run. Report is called from the goroutines running iterations,
+// so it must be safe for concurrent use, and should return quickly.
+type Reporter interface {
+	Report(event Event)
+}
+
+// Func is a function used as a Reporter.
+type Func func(event Event)
+
+func (f Func) Report(event Event) {
+	f(event)
+}
+
+// Event is one of RunStarted, SetupFinished, Progress, IterationFinished, StageStarted,
+// Interrupted, TeardownFinished or RunFinished.
+type Event interface {
+	event()
+}
+
+// RunStarted is reported before the setup of the scenario.
+type RunStarted struct {
+	Time     time.Time
+	Scenario string
+	// Trigger is the description of the trigger mode
+	Trigger       string
+	MaxDuration   time.Duration
+	MaxIterations uint64
+	Concurrency   int
+	Seed          uint64
+}
+
+// SetupFinished is reported once the setup of the scenario has run.
+type SetupFinished struct {
+	Duration time.Duration
+	Failed   bool
+}
+
+// Progress is reported at the same intervals as the progress output.
+type Progress struct {
+	// Elapsed is the time since the iterations started
+	Elapsed time.Duration
+	// Period is the time since the previous Progress
+	Period time.Duration
+	// PeriodAverage is the average duration of the iterations which succeeded during Period
+	PeriodAverage        time.Duration
+	PeriodIterations     uint64
+	SuccessfulIterations uint64
+	FailedIterations     uint64
+	DroppedIterations    uint64
+}
+
+// Iteration results
+const (
+	ResultSuccess = "success"
+	ResultFailed  = "fail"
+	ResultDropped = "dropped"
+)
+
+// IterationFinished is reported for every iteration, including dropped iterations.
+type IterationFinished struct {
+	ScheduledAt time.Time
+	// StartedAt is zero for dropped iterations
+	StartedAt time.Time
+	// Result is one of ResultSuccess, ResultFailed or ResultDropped
+	Result string
+	// Failure is the reason the iteration failed
+	Failure   string
+	Duration  time.Duration
+	Iteration uint64
+	// VUID is the virtual user which ran the iteration, or -1 if it was dropped
+	VUID int
+	// Tags are the tags set with T.Tag
+	Tags map[string]string
+	// Stages are the durations recorded with T.Time
+	Stages []Stage
+	// CleanupFailed is set when a function registered with T.Cleanup failed, which doesn't
+	// change Result
+	CleanupFailed bool
+}
+
+// Stage is the duration of a stage of an iteration, timed with T.Time.
+type Stage struct {
+	Name     string
+	Duration time.Duration
+}
+
+// StageStarted is reported at the start of each stage of a file trigger.
+type StageStarted struct {
+	// Params are the scenario parameters set by the stage
+	Params   map[string]string
+	Mode     string
+	Duration time.Duration
+	// Index is the position of the stage, starting at 0
+	Index  int
+	Stages int
+}
+
+// Interrupted is reported when the run is interrupted, before waiting for running iterations.
+type Interrupted struct {
+	Elapsed time.Duration
+}
+
+// TeardownFinished is reported once the teardown of the scenario has run.
+type TeardownFinished struct {
+	Failed bool
+}
+
+// RunFinished is the last event of a run.
+type RunFinished struct {
+	Result Result
+}
+
+// Result is the outcome of a run.
+type Result struct {
+	// Error is set when the run failed for a reason other than its iterations, such as the setup
+	// of the scenario failing.
+	Error                error
+	TopFailures          []FailureReason
+	SuccessfulDurations  IterationDurations
+	FailedDurations      IterationDurations
+	WaitDurations        IterationDurations
+	Duration             time.Duration
+	SuccessfulIterations uint64
+	FailedIterations     uint64
+	DroppedIterations    uint64
+	// Seed repeats the random choices of the run, when set with --seed or RunConfig.Seed.
+	Seed uint64
+	// Passed is false if the run failed, according to its failure limits.
+	Passed bool
+}
+
+// IterationDurations summarises the durations of iterations.
+type IterationDurations struct {
+	Average time.Duration
+	Min     time.Duration
+	Max     time.Duration
+}
+
+// FailureReason is a reason iterations failed with, and how many did.
+type FailureReason struct {
+	Reason string
+	// FirstIteration is the first iteration which failed with this reason
+	FirstIteration string
+	Count          uint64
+}
+
+func (RunStarted) event()        {}
+func (SetupFinished) event()     {}
+func (Progress) event()          {}
+func (IterationFinished) event() {}
+func (StageStarted) event()      {}
+func (Interrupted) event()       {}
+func (TeardownFinished) event()  {}
+func (RunFinished) event()       {}
+
+// Multi reports events to all of the reporters.
+func Multi(reporters ...Reporter) Reporter {
+	return Func(func(event Event) {
+		for _, r := range reporters {
+			r.Report(event)
+		}
+	})
+}
+
+// SampleIterations reports only one in every n IterationFinished events to r, and all other events.
+func SampleIterations(r Reporter, n uint64) Reporter {
+	var count atomic.Uint64
+
+	return Func(func(event Event) {
+		if _, ok := event.(IterationFinished); ok && n > 1 && (count.Add(1)-1)%n != 0 {
+			return
+		}
+		r.Report(event)
+	})
+}
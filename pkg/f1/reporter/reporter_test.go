@@ -0,0 +1,46 @@
+package reporter_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/form3tech-oss/f1/v2/pkg/f1/reporter"
+)
+
+func TestSampleIterations_ReportsOneInEveryNIterations(t *testing.T) {
+	t.Parallel()
+
+	var iterations, others int
+	r := reporter.SampleIterations(reporter.Func(func(event reporter.Event) {
+		if _, ok := event.(reporter.IterationFinished); ok {
+			iterations++
+		} else {
+			others++
+		}
+	}), 10)
+
+	r.Report(reporter.RunStarted{})
+	for range 100 {
+		r.Report(reporter.IterationFinished{})
+	}
+	r.Report(reporter.RunFinished{})
+
+	assert.Equal(t, 10, iterations)
+	assert.Equal(t, 2, others)
+}
+
+func TestMulti_ReportsToAllReporters(t *testing.T) {
+	t.Parallel()
+
+	var first, second []reporter.Event
+	r := reporter.Multi(
+		reporter.Func(func(event reporter.Event) { first = append(first, event) }),
+		reporter.Func(func(event reporter.Event) { second = append(second, event) }),
+	)
+
+	r.Report(reporter.Interrupted{})
+
+	assert.Equal(t, []reporter.Event{reporter.Interrupted{}}, first)
+	assert.Equal(t, []reporter.Event{reporter.Interrupted{}}, second)
+}
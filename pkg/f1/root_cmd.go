@@ -7,12 +7,13 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/form3tech-oss/f1/v2/internal/analyze"
 	"github.com/form3tech-oss/f1/v2/internal/chart"
 	"github.com/form3tech-oss/f1/v2/internal/envsettings"
 	"github.com/form3tech-oss/f1/v2/internal/metrics"
 	"github.com/form3tech-oss/f1/v2/internal/run"
+	"github.com/form3tech-oss/f1/v2/internal/simulate"
 	"github.com/form3tech-oss/f1/v2/internal/trigger"
-	"github.com/form3tech-oss/f1/v2/internal/ui"
 	"github.com/form3tech-oss/f1/v2/pkg/f1/scenarios"
 )
 
@@ -25,9 +26,10 @@ func buildRootCmd(
 	scenarioList *scenarios.Scenarios,
 	settings envsettings.Settings,
 	p *profiling,
-	output *ui.Output,
-	staticMetrics map[string]string,
+	options *f1Options,
 ) (*cobra.Command, error) {
+	output := options.output
+
 	rootCmd := &cobra.Command{
 		Use:               getCmdName(),
 		Short:             "F1 load testing tool",
@@ -44,24 +46,49 @@ func buildRootCmd(
 		return nil, fmt.Errorf("marking flag as filename: %w", err)
 	}
 
-	metrics.InitWithStaticMetrics(settings.PrometheusEnabled(), staticMetrics)
-	metricsInstance := metrics.Instance()
-
-	builders := trigger.GetBuilders(output)
+	builders, err := withCustomTriggers(trigger.GetBuilders(output), options.triggers)
+	if err != nil {
+		return nil, err
+	}
 
 	rootCmd.AddCommand(run.Cmd(
 		scenarioList,
+		options.queues,
 		builders,
 		settings,
-		metricsInstance,
+		newMetricsFactory(settings, options.staticMetrics, options.forwarder),
+		options.reporter(),
 		output,
 	))
 	rootCmd.AddCommand(chart.Cmd(builders, output))
+	rootCmd.AddCommand(simulate.Cmd(builders, output))
+	rootCmd.AddCommand(analyze.Cmd(output))
 	rootCmd.AddCommand(scenarios.Cmd(scenarioList))
 	rootCmd.AddCommand(completionsCmd(rootCmd))
 	return rootCmd, nil
 }
 
+// newMetricsFactory returns a factory of the metrics of each run, which adds the options required
+// by the scenario to those from the settings, and forwards the metrics when forwarder isn't nil.
+func newMetricsFactory(
+	settings envsettings.Settings,
+	staticMetrics map[string]string,
+	forwarder *metrics.Forwarder,
+) metrics.Factory {
+	return func(opts ...metrics.Option) *metrics.Metrics {
+		m := metrics.New(
+			settings.PrometheusEnabled(),
+			staticMetrics,
+			append([]metrics.Option{metrics.WithErrorLabel(settings.Prometheus.ErrorLabel)}, opts...)...,
+		)
+		if forwarder != nil {
+			forwarder.Forward(m)
+		}
+
+		return m
+	}
+}
+
 func startProfiling(p *profiling) func(*cobra.Command, []string) error {
 	return func(cmd *cobra.Command, _ []string) error {
 		var err error
@@ -0,0 +1,148 @@
+package f1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/options"
+	"github.com/form3tech-oss/f1/v2/internal/run"
+	"github.com/form3tech-oss/f1/v2/internal/triggerflags"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+	"github.com/form3tech-oss/f1/v2/pkg/f1/reporter"
+)
+
+const (
+	defaultMaxDuration              = time.Second
+	defaultConcurrency              = 100
+	defaultWaitForCompletionTimeout = 10 * time.Second
+)
+
+// RunConfig configures a load test started with Run. Zero values use the defaults of the run command.
+type RunConfig struct {
+	// Trigger starts the iterations, see Constant, Staged, Ramp, Gaussian, Users and File.
+	Trigger Trigger
+	// Params are the values of the parameters declared by the scenario.
+	Params map[string]string
+	// Scenario is the name of a scenario registered with Add.
+	Scenario string
+	// EventsFile is the path of a file where an event is written for each iteration.
+	EventsFile string
+	// MaxDuration defaults to 1 second.
+	MaxDuration time.Duration
+	// Concurrency is the maximum number of iterations running at once, defaults to 100.
+	Concurrency int
+	// MaxIterations stops the run after that many iterations, 0 is unlimited.
+	MaxIterations uint64
+	// MaxFailures fails the run if more iterations failed, 0 fails the run on any failure.
+	MaxFailures uint64
+	// MaxFailuresRate fails the run if more than that percentage of iterations failed.
+	MaxFailuresRate int
+	// WaitForCompletionTimeout is how long to wait for running iterations at the end of the run,
+	// defaults to 10 seconds.
+	WaitForCompletionTimeout time.Duration
+	// Seed seeds the random choices of the trigger and of T.Rand, to repeat a previous run. 0 picks
+	// a random seed, which is returned in the result.
+	Seed uint64
+	// IgnoreDropped does not fail the run when iterations are dropped.
+	IgnoreDropped bool
+}
+
+// RunResult is the outcome of a load test started with Run.
+type RunResult = reporter.Result
+
+// IterationDurations summarises the durations of iterations.
+type IterationDurations = reporter.IterationDurations
+
+// FailureReason is a reason iterations failed with, and how many did.
+type FailureReason = reporter.FailureReason
+
+// Run runs a scenario registered with Add, and returns once it is complete or ctx is cancelled.
+//
+// Unlike Execute, Run doesn't handle signals, exit the process or print the progress and summary:
+// its output and the scenario logs are written to the logger set with WithLogger. The default
+// logger still writes them to the console.
+func (f *F1) Run(ctx context.Context, config RunConfig) (*RunResult, error) {
+	if config.Trigger.builder == nil {
+		return nil, errors.New("no trigger configured")
+	}
+
+	output := ui.NewOutput(f.options.output.Logger, ui.NewDiscardPrinter(), false, false)
+
+	builder := config.Trigger.builder(output)
+	flags := builder.Flags
+	// the ramp trigger falls back to the max duration, which is a flag of the run command
+	if flags.Lookup(triggerflags.FlagMaxDuration) == nil {
+		flags.Duration(triggerflags.FlagMaxDuration, orDefault(config.MaxDuration, defaultMaxDuration), "")
+	}
+	if flags.Lookup(triggerflags.FlagSeed) == nil {
+		flags.Uint64(triggerflags.FlagSeed, config.Seed, "")
+	}
+	if err := flags.Parse(config.Trigger.args); err != nil {
+		return nil, fmt.Errorf("parsing trigger options: %w", err)
+	}
+	seed, err := triggerflags.ResolveSeed(flags)
+	if err != nil {
+		return nil, fmt.Errorf("resolving seed: %w", err)
+	}
+
+	trig, err := builder.New(flags)
+	if err != nil {
+		return nil, fmt.Errorf("creating trigger: %w", err)
+	}
+
+	runOptions := options.RunOptions{
+		Scenario:                 config.Scenario,
+		MaxDuration:              orDefault(config.MaxDuration, defaultMaxDuration),
+		Concurrency:              orDefault(config.Concurrency, defaultConcurrency),
+		MaxIterations:            config.MaxIterations,
+		MaxFailures:              config.MaxFailures,
+		MaxFailuresRate:          config.MaxFailuresRate,
+		IgnoreDropped:            config.IgnoreDropped,
+		WaitForCompletionTimeout: orDefault(config.WaitForCompletionTimeout, defaultWaitForCompletionTimeout),
+		Params:                   config.Params,
+		EventsFile:               config.EventsFile,
+		Reporter:                 f.options.reporter(),
+		Seed:                     seed,
+		Queues:                   f.options.queues,
+		// scenario logs are written to the logger, rather than a log file
+		Verbose: true,
+	}
+	if builder.IgnoreCommonFlags {
+		runOptions.Scenario = trig.Options.Scenario
+		runOptions.MaxDuration = trig.Options.MaxDuration
+		runOptions.Concurrency = trig.Options.Concurrency
+		runOptions.MaxIterations = trig.Options.MaxIterations
+		runOptions.MaxFailures = trig.Options.MaxFailures
+		runOptions.MaxFailuresRate = trig.Options.MaxFailuresRate
+		runOptions.IgnoreDropped = trig.Options.IgnoreDropped
+		runOptions.WaitForCompletionTimeout = trig.Options.WaitForCompletionTimeout
+	}
+	if runOptions.Concurrency < 1 {
+		return nil, fmt.Errorf("concurrency %d can't be less than 1", runOptions.Concurrency)
+	}
+
+	newMetrics := newMetricsFactory(f.settings, f.options.staticMetrics, f.options.forwarder)
+	r, err := run.NewRun(runOptions, f.scenarios, trig, f.settings, newMetrics, output)
+	if err != nil {
+		return nil, fmt.Errorf("new run: %w", err)
+	}
+
+	result, err := r.Do(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("internal error on run: %w", err)
+	}
+
+	runResult := result.Report()
+	return &runResult, nil
+}
+
+func orDefault[T comparable](value, defaultValue T) T {
+	var zero T
+	if value == zero {
+		return defaultValue
+	}
+
+	return value
+}
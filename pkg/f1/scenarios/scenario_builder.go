@@ -18,6 +18,16 @@ type Scenario struct {
 	Description string
 	Parameters  []ScenarioParameter
 	ScenarioFn  testing.ScenarioFn
+	// Data is the file whose rows are returned by T.Data.
+	Data *DataFile
+	// Callbacks is the listener receiving the callbacks awaited with T.AwaitCallback.
+	Callbacks *Callbacks
+	// Queues are the queues returned by T.Queue.
+	Queues []Queue
+	// VUSetupFn is invoked once for each worker, before it runs iterations.
+	VUSetupFn testing.VUSetupFn
+	// TagLabels are the keys of the tags set with T.Tag which are labels of the iteration metric.
+	TagLabels []string
 	// The function that is invoked on each iteration of the test scenario.
 	RunFn testing.RunFn
 }
@@ -28,6 +38,66 @@ type ScenarioParameter struct {
 	Default     string
 }
 
+// DataMode is how the rows of a DataFile are handed to iterations.
+type DataMode string
+
+const (
+	// DataSequential hands rows in order, starting again from the first row once all have been used.
+	DataSequential DataMode = "sequential"
+	// DataRandom hands a random row to each iteration.
+	DataRandom DataMode = "random"
+	// DataUnique hands each row to a single iteration.
+	DataUnique DataMode = "unique"
+	// DataPerVU partitions the rows between --concurrency virtual users, by VUID, which each use their
+	// rows in order. Iterations of virtual users with a higher VUID fail.
+	DataPerVU DataMode = "per-vu"
+)
+
+// DataFile is a .csv file, whose first line is a header, or a .jsonl file with an object on each
+// line, whose rows are returned by T.Data.
+type DataFile struct {
+	Path string
+	Mode DataMode
+	// StopWhenExhausted stops the run once all rows have been used in DataUnique mode, rather than
+	// failing the following iterations.
+	StopWhenExhausted bool
+}
+
+// Callbacks configures a listener, started before the setup of the scenario, which receives the
+// callbacks, such as webhooks, awaited with T.AwaitCallback. Callbacks are matched to iterations
+// by a correlation id, read from IDHeader, or else from IDPath in their json body.
+type Callbacks struct {
+	// Address is the address the listener binds, such as :8080.
+	Address string
+	// IDHeader is the header holding the correlation id of callbacks.
+	IDHeader string
+	// IDPath is the path of the correlation id in the json body of callbacks, such as data.id.
+	IDPath string
+}
+
+// QueueEmptyPolicy is what T.Queue(name).Take does when the queue is empty.
+type QueueEmptyPolicy string
+
+const (
+	// QueueBlock waits for an item to be put in the queue.
+	QueueBlock QueueEmptyPolicy = "block"
+	// QueueSkip returns without an item, so that the iteration can skip its work.
+	QueueSkip QueueEmptyPolicy = "skip"
+	// QueueFail fails the iteration.
+	QueueFail QueueEmptyPolicy = "fail"
+)
+
+// Queue is a named, bounded, in-process queue, shared by the scenarios which declare it, through
+// which they hand each other items, such as the accounts created by one scenario and used by another.
+type Queue struct {
+	Name string
+	// Capacity is the number of items the queue holds before T.Queue(name).Put waits, 1000 by
+	// default. Scenarios declaring the same queue must declare the same capacity, or 0.
+	Capacity int
+	// WhenEmpty is what the scenario does when it takes from the empty queue, QueueBlock by default.
+	WhenEmpty QueueEmptyPolicy
+}
+
 type ScenarioOption func(info *Scenario)
 
 func Description(d string) ScenarioOption {
@@ -42,6 +112,45 @@ func Parameter(parameter ScenarioParameter) ScenarioOption {
 	}
 }
 
+// WithVUSetup sets a function which initialises each virtual user, such as a session or a client,
+// before it runs iterations. Its result is returned by T.VU in the iterations of the virtual user.
+func WithVUSetup(fn testing.VUSetupFn) ScenarioOption {
+	return func(i *Scenario) {
+		i.VUSetupFn = fn
+	}
+}
+
+// WithData declares a data file, whose rows are returned by T.Data in each iteration.
+func WithData(file DataFile) ScenarioOption {
+	return func(i *Scenario) {
+		i.Data = &file
+	}
+}
+
+// WithCallbacks starts a listener receiving the callbacks awaited with T.AwaitCallback.
+func WithCallbacks(callbacks Callbacks) ScenarioOption {
+	return func(i *Scenario) {
+		i.Callbacks = &callbacks
+	}
+}
+
+// WithQueue declares a queue, returned by T.Queue, which is shared with the other scenarios
+// declaring it, including those running at the same time with F1.Run.
+func WithQueue(queue Queue) ScenarioOption {
+	return func(i *Scenario) {
+		i.Queues = append(i.Queues, queue)
+	}
+}
+
+// WithTagLabels declares the keys of the tags, set with T.Tag, which are added as labels to the
+// iteration metric. Other tags are only added to logs, results and the summary, so that tags
+// with many values don't create many metric series.
+func WithTagLabels(keys ...string) ScenarioOption {
+	return func(i *Scenario) {
+		i.TagLabels = append(i.TagLabels, keys...)
+	}
+}
+
 func New() *Scenarios {
 	return &Scenarios{
 		scenarios: make(map[string]*Scenario),
@@ -53,6 +162,19 @@ func (s *Scenarios) Add(scenario *Scenario) *Scenarios {
 	return s
 }
 
+// With returns a copy of the scenarios with scenario added, leaving s unchanged.
+func (s *Scenarios) With(scenario *Scenario) *Scenarios {
+	with := &Scenarios{
+		scenarios: make(map[string]*Scenario, len(s.scenarios)+1),
+	}
+	for name, existing := range s.scenarios {
+		with.scenarios[name] = existing
+	}
+	with.scenarios[scenario.Name] = scenario
+
+	return with
+}
+
 func (s *Scenarios) GetScenario(scenarioName string) *Scenario {
 	return s.scenarios[scenarioName]
 }
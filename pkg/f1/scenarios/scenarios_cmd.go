@@ -32,6 +32,9 @@ func lsCmdExecute(s *Scenarios) func(*cobra.Command, []string) {
 		sort.Strings(scenarios)
 		for _, scenario := range scenarios {
 			fmt.Fprintln(os.Stdout, scenario)
+			for _, parameter := range s.GetScenario(scenario).Parameters {
+				fmt.Fprintf(os.Stdout, "  --param %s=%q\t%s\n", parameter.Name, parameter.Default, parameter.Description)
+			}
 		}
 	}
 }
@@ -7,3 +7,8 @@ type ScenarioFn func(t *T) RunFn
 // RunFn performs a single iteration of the scenario. 't' may be used for asserting
 // results or failing the scenario.
 type RunFn func(t *T)
+
+// VUSetupFn initialises a virtual user, once for each worker running iterations, and returns its
+// state, which is returned by T.VU in the iterations of the virtual user. Cleanup functions
+// registered on 't' run when the worker stops.
+type VUSetupFn func(t *T) any
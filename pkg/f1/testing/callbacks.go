@@ -0,0 +1,60 @@
+package testing
+
+import (
+	"errors"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/callbacks"
+)
+
+// CallbackStage is the stage recording the latency of the callbacks awaited with AwaitCallback.
+const CallbackStage = "callback"
+
+// Callback is a callback received by the listener declared with scenarios.WithCallbacks.
+type Callback = callbacks.Callback
+
+// CallbackAddress returns the address of the listener declared with scenarios.WithCallbacks, such
+// as 127.0.0.1:8080, to which the system under test should send callbacks.
+func (t *T) CallbackAddress() string {
+	t.requireCallbacks()
+
+	return t.listener.Addr()
+}
+
+// ExpectCallback registers the correlation id of a callback, before the request triggering it is
+// sent, so that the latency recorded by AwaitCallback includes the request. The id is
+// unregistered at the end of the iteration if it wasn't awaited.
+func (t *T) ExpectCallback(id string) {
+	t.requireCallbacks()
+
+	t.listener.Expect(id)
+	t.Cleanup(func() {
+		t.listener.Forget(id)
+	})
+}
+
+// AwaitCallback waits for the callback with the correlation id, and records its latency, from
+// ExpectCallback or else from the call to AwaitCallback, as the stage "callback". Callbacks which
+// aren't received are counted as missing, rather than recorded as the stage. The iteration
+// fails and stops, as with FailNow, when the callback isn't received within timeout or the run is
+// interrupted. AwaitCallback must be called from the goroutine running the iteration.
+func (t *T) AwaitCallback(id string, timeout time.Duration) Callback {
+	t.requireCallbacks()
+
+	callback, latency, err := t.listener.Await(t.ctx, id, timeout)
+	if errors.Is(err, callbacks.ErrTimeout) {
+		t.Fatalf("callback %s not received within %s", id, timeout)
+	}
+	if err != nil {
+		t.Fatalf("callback %s not received, the run was interrupted", id)
+	}
+	t.RecordStage(CallbackStage, latency)
+
+	return callback
+}
+
+func (t *T) requireCallbacks() {
+	if t.listener == nil {
+		t.Fatalf("the scenario doesn't declare callbacks, see scenarios.WithCallbacks")
+	}
+}
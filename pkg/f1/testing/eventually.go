@@ -0,0 +1,59 @@
+package testing
+
+import (
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+)
+
+const (
+	// maxEventuallyBackoff caps the interval between the attempts of Eventually, as a multiple of
+	// the initial interval.
+	maxEventuallyBackoff = 8
+	// minEventuallyInterval keeps Eventually from polling in a busy loop.
+	minEventuallyInterval = time.Millisecond
+)
+
+// Eventually calls condition until it returns true, and records the time it took as the stage
+// stageName, as Time does. It returns the number of attempts, which are recorded by the retry
+// attempts metric with the result met or not_met.
+//
+// The first attempt is immediate, and the interval between the following attempts starts at
+// interval and doubles after each one, up to 8 times interval. The iteration fails and stops, as
+// with FailNow, when the condition isn't met within timeout or the run is interrupted. Eventually
+// must be called from the goroutine running the iteration.
+func (t *T) Eventually(stageName string, condition func() bool, timeout, interval time.Duration) int {
+	start := time.Now()
+	deadline := start.Add(timeout)
+	interval = max(interval, minEventuallyInterval)
+	wait := interval
+
+	for attempts := 1; ; attempts++ {
+		if condition() {
+			t.RecordStage(stageName, time.Since(start))
+			t.recordRetry(stageName, metrics.MetResult, attempts)
+			return attempts
+		}
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			t.Errorf("%s: condition not met after %d attempts in %s", stageName, attempts, timeout)
+			t.RecordStage(stageName, time.Since(start))
+			t.recordRetry(stageName, metrics.NotMetResult, attempts)
+			t.FailNow()
+		}
+
+		timer := time.NewTimer(min(wait, remaining))
+		select {
+		case <-timer.C:
+		case <-t.ctx.Done():
+			timer.Stop()
+			t.Errorf("%s: condition not met after %d attempts, the run was interrupted", stageName, attempts)
+			t.RecordStage(stageName, time.Since(start))
+			t.recordRetry(stageName, metrics.NotMetResult, attempts)
+			t.FailNow()
+		}
+
+		wait = min(wait*2, interval*maxEventuallyBackoff)
+	}
+}
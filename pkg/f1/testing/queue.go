@@ -0,0 +1,98 @@
+package testing
+
+import (
+	"context"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+	"github.com/form3tech-oss/f1/v2/internal/queues"
+)
+
+const (
+	queuePut  = "put"
+	queueTake = "take"
+)
+
+// Queue is a queue declared with scenarios.WithQueue, through which scenarios hand each other
+// items. Its methods must be called from the goroutine running the iteration.
+type Queue struct {
+	t       *T
+	binding queues.Binding
+}
+
+// Queue returns the queue name, declared by the scenario with scenarios.WithQueue.
+func (t *T) Queue(name string) *Queue {
+	binding, ok := t.queues[name]
+	if !ok {
+		t.Fatalf("the scenario doesn't declare the queue %s, see scenarios.WithQueue", name)
+	}
+
+	return &Queue{t: t, binding: binding}
+}
+
+// Len returns the number of items in the queue.
+func (q *Queue) Len() int {
+	return q.binding.Queue.Len()
+}
+
+// Put adds item to the queue. It waits while the queue is full, and fails the iteration, as
+// FailNow does, if the run is interrupted first.
+func (q *Queue) Put(item any) {
+	start := time.Now()
+	err := q.binding.Queue.Put(q.t.ctx, item)
+	q.record(queuePut, metrics.Result(err != nil), start)
+	if err != nil {
+		q.t.Fatalf("%v, the run was interrupted", err)
+	}
+}
+
+// Take removes the oldest item from the queue. When the queue is empty, it waits for an item
+// until ctx is done or the run is interrupted, returns false without an item, or fails the
+// iteration, as the scenario declared with scenarios.Queue.WhenEmpty. Failures stop the iteration,
+// as FailNow does.
+func (q *Queue) Take(ctx context.Context) (any, bool) {
+	start := time.Now()
+	queue := q.binding.Queue
+
+	if q.binding.WhenEmpty != queues.Block {
+		item, ok := queue.TryTake()
+		switch {
+		case ok:
+			q.record(queueTake, metrics.SuccessResult, start)
+			return item, true
+		case q.binding.WhenEmpty == queues.Skip:
+			q.record(queueTake, metrics.SkippedResult, start)
+			return nil, false
+		default:
+			q.record(queueTake, metrics.FailedResult, start)
+			q.t.Fatalf("queue %s is empty", queue.Name())
+		}
+	}
+
+	// the wait stops when either ctx is done or the run is interrupted
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stop := context.AfterFunc(q.t.ctx, cancel)
+	defer stop()
+
+	item, err := queue.Take(ctx)
+	q.record(queueTake, metrics.Result(err != nil), start)
+	if err != nil {
+		if q.t.ctx.Err() != nil {
+			q.t.Fatalf("queue %s is empty, the run was interrupted", queue.Name())
+		}
+		q.t.Fatalf("%v", err)
+	}
+
+	return item, true
+}
+
+func (q *Queue) record(operation string, result metrics.ResultType, start time.Time) {
+	if q.t.metrics == nil {
+		return
+	}
+
+	name := q.binding.Queue.Name()
+	q.t.metrics.RecordQueueWait(q.t.Scenario, name, operation, result, time.Since(start).Nanoseconds())
+	q.t.metrics.RecordQueueDepth(q.t.Scenario, name, q.binding.Queue.Len())
+}
@@ -0,0 +1,22 @@
+package testing
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+)
+
+// Rand returns a random number generator for the iteration, derived from the seed of the run, the
+// iteration and the VUID, so that a run repeated with --seed makes the same random choices in the
+// iterations with the same number and VUID. It must be called only from the goroutine running the
+// Scenario function.
+func (t *T) Rand() *rand.Rand {
+	if t.random == nil {
+		stream := fnv.New64a()
+		_, _ = fmt.Fprintf(stream, "%s/%d", t.Iteration, t.VUID)
+		//nolint:gosec // G404: Use of weak random number generator - doesn't need to be secure
+		t.random = rand.New(rand.NewPCG(t.seed, stream.Sum64()))
+	}
+
+	return t.random
+}
@@ -0,0 +1,107 @@
+package testing
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+)
+
+const (
+	defaultRetryAttempts = 3
+	defaultRetryBackoff  = 100 * time.Millisecond
+)
+
+// RetryPolicy configures the attempts of Retry.
+type RetryPolicy struct {
+	// Retryable returns whether an error is retried. All errors are retried if it isn't set.
+	Retryable func(err error) bool
+	// MaxAttempts is the number of attempts, including the first one, 3 by default.
+	MaxAttempts int
+	// InitialBackoff is the wait before the second attempt, which doubles after each attempt,
+	// 100ms by default.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between attempts, if set.
+	MaxBackoff time.Duration
+	// Jitter is the fraction of each wait which is random, from 0 to 1, so that virtual users
+	// failing at the same time don't retry at the same time.
+	Jitter float64
+}
+
+// backoff returns the wait before the attempt after attempt, with a jitter drawn from random.
+func (p RetryPolicy) backoff(attempt int, random *rand.Rand) time.Duration {
+	initial := p.InitialBackoff
+	if initial <= 0 {
+		initial = defaultRetryBackoff
+	}
+
+	wait := initial
+	for range attempt - 1 {
+		wait *= 2
+		if p.MaxBackoff > 0 && wait >= p.MaxBackoff {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 {
+		wait = min(wait, p.MaxBackoff)
+	}
+
+	jitter := min(max(p.Jitter, 0), 1)
+	return wait - time.Duration(jitter*random.Float64()*float64(wait))
+}
+
+// Retry calls fn until it succeeds, and returns the number of attempts. Each attempt is recorded
+// as the stage stageName, as Time does, with the result of the attempt, and the number of attempts
+// is recorded by the retry attempts metric.
+//
+// The iteration fails and stops, as with FailNow, when the attempts are exhausted, fn returns an
+// error which isn't retryable, or the run is interrupted, which also stops any wait between
+// attempts. A context.Canceled error returned by fn while the run goes on is left to Retryable.
+// Retry must be called from the goroutine running the iteration.
+func (t *T) Retry(stageName string, policy RetryPolicy, fn func() error) int {
+	maxAttempts := policy.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultRetryAttempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		start := time.Now()
+		err := fn()
+		t.recordStage(stageName, metrics.Result(err != nil), time.Since(start))
+
+		switch {
+		case err == nil:
+			t.recordRetry(stageName, metrics.SuccessResult, attempt)
+			return attempt
+		case t.ctx.Err() != nil:
+			t.recordRetry(stageName, metrics.FailedResult, attempt)
+			t.Errorf("%s: attempt %d of %d failed, the run was interrupted: %v", stageName, attempt, maxAttempts, err)
+			t.FailNow()
+		case policy.Retryable != nil && !policy.Retryable(err):
+			t.recordRetry(stageName, metrics.FailedResult, attempt)
+			t.Errorf("%s: attempt %d of %d failed with an error which isn't retryable: %v",
+				stageName, attempt, maxAttempts, err)
+			t.FailNow()
+		case attempt >= maxAttempts:
+			t.recordRetry(stageName, metrics.ExhaustedResult, attempt)
+			t.Errorf("%s: all %d attempts failed, the last with: %v", stageName, attempt, err)
+			t.FailNow()
+		}
+
+		timer := time.NewTimer(policy.backoff(attempt, t.Rand()))
+		select {
+		case <-timer.C:
+		case <-t.ctx.Done():
+			timer.Stop()
+			t.recordRetry(stageName, metrics.FailedResult, attempt)
+			t.Errorf("%s: attempt %d of %d failed, the run was interrupted: %v", stageName, attempt, maxAttempts, err)
+			t.FailNow()
+		}
+	}
+}
+
+func (t *T) recordRetry(stageName string, result metrics.ResultType, attempts int) {
+	if t.metrics != nil {
+		t.metrics.RecordRetry(t.Scenario, stageName, result, attempts)
+	}
+}
@@ -1,22 +1,42 @@
 package testing
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"math/rand/v2"
 	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
 
+	"github.com/form3tech-oss/f1/v2/internal/callbacks"
+	"github.com/form3tech-oss/f1/v2/internal/data"
 	"github.com/form3tech-oss/f1/v2/internal/log"
 	"github.com/form3tech-oss/f1/v2/internal/metrics"
+	"github.com/form3tech-oss/f1/v2/internal/params"
+	"github.com/form3tech-oss/f1/v2/internal/queues"
+	"github.com/form3tech-oss/f1/v2/internal/toptions"
 )
 
 var errFailNow = errors.New("FailNow")
 
+const (
+	// UnknownFailureReason is reported for iterations that failed without an error message,
+	// for example by calling FailNow directly.
+	UnknownFailureReason = "iteration failed without an error message"
+
+	maxFailureReasonLength = 200
+)
+
 // T is a type passed to Scenario functions to manage test state and support formatted test logs. A
 // test ends when its Scenario function returns or calls any of the methods FailNow, Fatal, Fatalf.
 // Those methods must be called only from the goroutine running the Scenario function. The other
@@ -32,10 +52,36 @@ type T struct {
 	// Useful for correlating iterations with user-specific test data (e.g. in the "users" trigger mode).
 	// VUID is -1 for setup; 0-based for pool workers.
 	VUID           int
+	ctx            context.Context //nolint:containedctx // cancelled with the run, returned by Context
+	logBuffer      *log.BufferHandler
+	logFlushLimit  *log.FlushLimit
+	params         *params.Params
+	metrics        *metrics.Metrics
+	vu             any
+	feeder         *data.Feeder
+	listener       *callbacks.Listener
+	queues         map[string]queues.Binding
+	random         *rand.Rand
+	row            map[string]string
+	tags           map[string]string
+	untaggedLogger *slog.Logger
+	untaggedLogrus *logrus.Logger
 	teardownStack  []func()
+	stages         []StageDuration
+	// mu guards tags and stages, which stages recorded from other goroutines read and write
+	mu             sync.Mutex
+	failureReason  atomic.Pointer[string]
+	seed           uint64
 	failed         atomic.Bool
 	teardownFailed atomic.Bool
 	tearingDown    bool
+	recordStages   bool
+}
+
+// StageDuration is the duration of a stage of the function, recorded with T.Time.
+type StageDuration struct {
+	Name     string
+	Duration time.Duration
 }
 
 type TOption func(*T)
@@ -69,6 +115,53 @@ func WithVUID(id int) TOption {
 	}
 }
 
+// WithVU sets the state of the virtual user, returned by VU.
+func WithVU(state any) TOption {
+	return func(t *T) {
+		t.vu = state
+	}
+}
+
+// WithSeed sets the seed of the run, from which Rand is derived.
+func WithSeed(seed uint64) TOption {
+	return func(t *T) {
+		t.seed = seed
+	}
+}
+
+// WithContext sets the context returned by Context, which is cancelled when the run is interrupted.
+func WithContext(ctx context.Context) TOption {
+	return func(t *T) {
+		t.ctx = ctx
+	}
+}
+
+// WithStageDurations keeps the durations recorded with Time, which are returned by StageDurations.
+func WithStageDurations() TOption {
+	return func(t *T) {
+		t.recordStages = true
+	}
+}
+
+//nolint:gochecknoinits // registers the constructor of the options only f1 can set
+func init() {
+	toptions.New = func(options toptions.Options) any {
+		return withOptions(options)
+	}
+}
+
+// withOptions sets the resources of the run, which are set by f1 through package toptions.
+func withOptions(options toptions.Options) TOption {
+	return func(t *T) {
+		t.logFlushLimit = options.FailedIterationLogs
+		t.params = options.Params
+		t.metrics = options.Metrics
+		t.feeder = options.Feeder
+		t.listener = options.Listener
+		t.queues = options.Queues
+	}
+}
+
 // NewT returns a new T state
 //
 // Deprecated: Will be removed in favour of NewTWithOptions
@@ -87,6 +180,7 @@ func NewT(iter, scenarioName string) (*T, func()) {
 func NewTWithOptions(scenarioName string, options ...TOption) (*T, func()) {
 	t := &T{
 		Scenario:      scenarioName,
+		ctx:           context.Background(),
 		teardownStack: []func(){},
 	}
 	t.require = require.New(t)
@@ -95,15 +189,44 @@ func NewTWithOptions(scenarioName string, options ...TOption) (*T, func()) {
 		opt(t)
 	}
 
+	if t.logFlushLimit != nil && t.logger != nil {
+		t.logBuffer = log.NewBufferHandler(t.logger.Handler())
+		t.logger = slog.New(t.logBuffer)
+		t.logrusLogger = log.NewSlogLogrusLogger(t.logger)
+	}
+	t.untaggedLogger = t.logger
+	t.untaggedLogrus = t.logrusLogger
+
 	return t, t.teardown
 }
 
 func (t *T) Reset(iter string) {
 	t.Iteration = iter
+	t.row = nil
+	t.random = nil
+	t.mu.Lock()
+	tagged := t.tags != nil
+	t.tags = nil
+	t.mu.Unlock()
+	if tagged {
+		t.logger = t.untaggedLogger
+		t.logrusLogger = t.untaggedLogrus
+	}
 	t.failed.Store(false)
+	t.failureReason.Store(nil)
 	t.teardownFailed.Store(false)
 	t.tearingDown = false
 	t.teardownStack = []func(){}
+
+	if t.recordStages {
+		t.mu.Lock()
+		t.stages = t.stages[:0]
+		t.mu.Unlock()
+	}
+
+	if t.logBuffer != nil {
+		t.logBuffer.Discard()
+	}
 }
 
 // Logger returns a logrus logger, needed for backwards compatibility. Use StandardLogger
@@ -124,11 +247,108 @@ func (t *T) Require() *require.Assertions {
 	return t.require
 }
 
+// Context returns a context which is cancelled when the run is interrupted, such as by ctrl-c,
+// for requests and waits which should stop with the run.
+func (t *T) Context() context.Context {
+	return t.ctx
+}
+
 // Name returns the name of the running Scenario.
 func (t *T) Name() string {
 	return t.Scenario
 }
 
+// VU returns the state of the virtual user running the iteration, returned by the function set with
+// scenarios.WithVUSetup, or nil if the scenario doesn't set one.
+func (t *T) VU() any {
+	return t.vu
+}
+
+// Data returns the row of the data file declared with scenarios.WithData for the iteration. The
+// test fails if the scenario doesn't declare a data file, or if all its rows have been used in
+// unique mode. The row is shared between iterations and must not be modified. Data must be called
+// from the goroutine running the iteration.
+func (t *T) Data() map[string]string {
+	if t.row != nil {
+		return t.row
+	}
+	if t.feeder == nil {
+		t.Fatalf("the scenario doesn't declare a data file, see scenarios.WithData")
+	}
+
+	iteration, err := strconv.ParseUint(t.Iteration, 10, 64)
+	if err != nil {
+		iteration = 0
+	}
+
+	row, err := t.feeder.Row(t.VUID, iteration)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.row = row
+
+	return row
+}
+
+// Tag attaches a tag to the iteration, such as the tenant or the payment scheme it used. Tags are
+// added to the logs of the iteration and to its result, and the summary breaks iterations down by
+// tag value. Tags declared with scenarios.WithTagLabels are also labels of the iteration metric.
+// Tag must be called from the goroutine running the iteration.
+func (t *T) Tag(key, value string) {
+	t.mu.Lock()
+	// the tags are replaced rather than updated, so that stages recorded meanwhile keep theirs
+	tags := maps.Clone(t.tags)
+	if tags == nil {
+		tags = make(map[string]string)
+	}
+	tags[key] = value
+	t.tags = tags
+	t.mu.Unlock()
+
+	if t.untaggedLogger == nil {
+		return
+	}
+
+	attrs := make([]any, 0, len(tags))
+	for _, k := range slices.Sorted(maps.Keys(tags)) {
+		attrs = append(attrs, slog.String(k, tags[k]))
+	}
+	t.logger = t.untaggedLogger.With(slog.Group("tags", attrs...))
+	if t.untaggedLogrus != nil {
+		t.logrusLogger = log.NewSlogLogrusLogger(t.logger)
+	}
+}
+
+// Tags returns the tags attached to the iteration with Tag.
+func (t *T) Tags() map[string]string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return maps.Clone(t.tags)
+}
+
+// Param returns the value of the scenario parameter name, set with --param, by the current
+// stage of a file trigger, or the default declared with scenarios.Parameter.
+func (t *T) Param(name string) string {
+	if t.params == nil {
+		return ""
+	}
+
+	value, _ := t.params.Get(name)
+	return value
+}
+
+// ParamInt returns the value of the scenario parameter name as an int. The test fails if the
+// value isn't an integer.
+func (t *T) ParamInt(name string) int {
+	value, err := strconv.Atoi(t.Param(name))
+	if err != nil {
+		t.Fatalf("parameter '%s' is not an integer: %v", name, err)
+	}
+
+	return value
+}
+
 // FailNow marks the function as having failed and stops its execution.
 // Execution will continue at the next Scenario iteration. FailNow must be called from
 // the goroutine running the Scenario, not from other goroutines created during the Scenario.
@@ -154,25 +374,31 @@ func (t *T) Fail() {
 
 // Errorf is equivalent to Logf followed by Fail.
 func (t *T) Errorf(format string, args ...any) {
-	t.logger.Error(fmt.Sprintf(format, args...))
+	message := fmt.Sprintf(format, args...)
+	t.logger.Error(message)
+	t.recordFailureReason(message)
 	t.Fail()
 }
 
 // Error is equivalent to Log followed by Fail.
 func (t *T) Error(err error) {
 	t.logger.Error("iteration failed", log.IterationAttr(t.Iteration), log.VUIDAttr(t.VUID), log.ErrorAttr(err))
+	t.recordFailureReason(err.Error())
 	t.Fail()
 }
 
 // Fatalf is equivalent to Logf followed by FailNow.
 func (t *T) Fatalf(format string, args ...any) {
-	t.logger.Error(fmt.Sprintf(format, args...))
+	message := fmt.Sprintf(format, args...)
+	t.logger.Error(message)
+	t.recordFailureReason(message)
 	t.FailNow()
 }
 
 // Fatal is equivalent to Log followed by FailNow.
 func (t *T) Fatal(err error) {
 	t.logger.Error("iteration failed", log.IterationAttr(t.Iteration), log.VUIDAttr(t.VUID), log.ErrorAttr(err))
+	t.recordFailureReason(err.Error())
 	t.FailNow()
 }
 
@@ -197,6 +423,56 @@ func (t *T) TeardownFailed() bool {
 	return t.teardownFailed.Load()
 }
 
+// FailureReason returns a normalised message describing the first reported failure,
+// or an empty string if the function has not failed.
+func (t *T) FailureReason() string {
+	if !t.Failed() {
+		return ""
+	}
+
+	reason := t.failureReason.Load()
+	if reason == nil {
+		return UnknownFailureReason
+	}
+
+	return *reason
+}
+
+// recordFailureReason keeps the first failure reason reported. Failures during teardown
+// are not recorded, as they don't fail the iteration.
+func (t *T) recordFailureReason(message string) {
+	if t.tearingDown {
+		return
+	}
+
+	reason := normaliseFailureReason(message)
+	t.failureReason.CompareAndSwap(nil, &reason)
+}
+
+// normaliseFailureReason reduces a failure message to a single line, so that failures can be
+// grouped by their cause. Assertion failures from testify are reduced to their "Error:" section.
+func normaliseFailureReason(message string) string {
+	if _, errorSection, found := strings.Cut(message, "\tError:"); found {
+		message = errorSection
+		for _, label := range []string{"\n\tTest:", "\n\tMessages:"} {
+			if before, _, found := strings.Cut(message, label); found {
+				message = before
+			}
+		}
+	}
+
+	message = strings.Join(strings.Fields(message), " ")
+	if message == "" {
+		return UnknownFailureReason
+	}
+
+	if runes := []rune(message); len(runes) > maxFailureReasonLength {
+		message = string(runes[:maxFailureReasonLength]) + "…"
+	}
+
+	return message
+}
+
 // Time records a metric for the duration of the given function
 func (t *T) Time(stageName string, f func()) {
 	start := time.Now()
@@ -204,6 +480,40 @@ func (t *T) Time(stageName string, f func()) {
 	f()
 }
 
+// RecordStage records the duration of a stage measured by the caller, such as a phase of a request,
+// in the same way as Time does.
+func (t *T) RecordStage(stageName string, duration time.Duration) {
+	t.recordStage(stageName, metrics.Result(t.Failed()), duration)
+}
+
+func (t *T) recordStage(stageName string, result metrics.ResultType, duration time.Duration) {
+	t.mu.Lock()
+	tags := t.tags
+	if t.recordStages {
+		t.stages = append(t.stages, StageDuration{Name: stageName, Duration: duration})
+	}
+	t.mu.Unlock()
+
+	if t.metrics != nil {
+		t.metrics.RecordTaggedIterationStage(
+			t.Scenario,
+			stageName,
+			result,
+			tags,
+			duration.Nanoseconds(),
+		)
+	}
+}
+
+// StageDurations returns the durations recorded with Time and RecordStage, if T was created
+// WithStageDurations.
+func (t *T) StageDurations() []StageDuration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return slices.Clone(t.stages)
+}
+
 // Cleanup registers a function to be called when the scenario or the iteration completes.
 // Cleanup functions will be called in last added, first called order.
 func (t *T) Cleanup(f func()) {
@@ -235,6 +545,7 @@ func handlePanic(t *T, recovered any) {
 			log.VUIDAttr(t.VUID),
 			log.ErrorAttr(err),
 		)
+		t.recordFailureReason("panic: " + err.Error())
 		t.Fail()
 	default:
 		stack := debug.Stack()
@@ -244,6 +555,7 @@ func handlePanic(t *T, recovered any) {
 			log.VUIDAttr(t.VUID),
 			log.ErrorAnyAttr(recovered),
 		)
+		t.recordFailureReason(fmt.Sprintf("panic: %v", recovered))
 		t.Fail()
 	}
 }
@@ -257,13 +569,38 @@ func (t *T) teardown() {
 			t.teardownStack[i]()
 		}()
 	}
+
+	t.flushLogs()
+}
+
+// flushLogs writes the buffered logs of a failed iteration, or of any iteration if the limit flushes
+// the ones which passed, and discards them otherwise.
+func (t *T) flushLogs() {
+	if t.logBuffer == nil {
+		return
+	}
+
+	if !t.Failed() && !t.TeardownFailed() && !t.logFlushLimit.Passed() {
+		t.logBuffer.Discard()
+		return
+	}
+
+	allowed, last := t.logFlushLimit.Allow()
+	if !allowed {
+		t.logBuffer.Discard()
+		return
+	}
+
+	ctx := context.Background()
+	if err := t.logBuffer.Flush(ctx, log.IterationAttr(t.Iteration), log.VUIDAttr(t.VUID)); err != nil {
+		slog.Default().Error("writing failed iteration logs", log.ErrorAttr(err))
+	}
+
+	if last {
+		slog.New(t.logBuffer.Next()).Warn("failed iteration log limit reached, logs of further failed iterations are discarded")
+	}
 }
 
 func recordTime(t *T, stageName string, start time.Time) {
-	metrics.Instance().RecordIterationStage(
-		t.Scenario,
-		stageName,
-		metrics.Result(t.Failed()),
-		time.Since(start).Nanoseconds(),
-	)
+	t.RecordStage(stageName, time.Since(start))
 }
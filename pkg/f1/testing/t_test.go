@@ -2,13 +2,22 @@ package testing_test
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"log/slog"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/form3tech-oss/f1/v2/internal/data"
 	"github.com/form3tech-oss/f1/v2/internal/log"
+	"github.com/form3tech-oss/f1/v2/internal/metrics"
+	"github.com/form3tech-oss/f1/v2/internal/params"
+	"github.com/form3tech-oss/f1/v2/internal/toptions"
 	f1testing "github.com/form3tech-oss/f1/v2/pkg/f1/testing"
 )
 
@@ -196,6 +205,675 @@ func TestWithVUIDIncludedInErrorLogs(t *testing.T) {
 	require.Contains(t, logs, "test error")
 }
 
+func TestFailureReasonIsEmptyWhenNotFailed(t *testing.T) {
+	t.Parallel()
+
+	newT, teardown := newT()
+	defer teardown()
+
+	require.Empty(t, newT.FailureReason())
+}
+
+func TestFailureReasonIsTheFirstError(t *testing.T) {
+	t.Parallel()
+
+	newT, teardown := newT()
+	defer teardown()
+
+	newT.Errorf("first  error\non two lines")
+	newT.Error(errors.New("second error"))
+	require.Equal(t, "first error on two lines", newT.FailureReason())
+}
+
+func TestFailureReasonUsesTheAssertionError(t *testing.T) {
+	t.Parallel()
+
+	newT, teardown := newT()
+	defer teardown()
+
+	assert.Equal(newT, 1, 2, "some message")
+	require.Equal(t, "Not equal: expected: 1 actual : 2", newT.FailureReason())
+}
+
+func TestFailureReasonIsUnknownWithoutAMessage(t *testing.T) {
+	t.Parallel()
+
+	newT, teardown := newT()
+	defer teardown()
+
+	newT.Fail()
+	require.Equal(t, f1testing.UnknownFailureReason, newT.FailureReason())
+}
+
+func TestFailureReasonIsTruncated(t *testing.T) {
+	t.Parallel()
+
+	newT, teardown := newT()
+	defer teardown()
+
+	newT.Errorf("%s", strings.Repeat("a", 300))
+	require.Equal(t, strings.Repeat("a", 200)+"…", newT.FailureReason())
+}
+
+func TestFailedIterationLogsAreDiscardedWhenNotFailed(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	newT, teardown := f1testing.NewTWithOptions("test",
+		f1testing.WithLogger(log.NewTestLogger(&buf)),
+		withOptions(toptions.Options{FailedIterationLogs: log.NewFlushLimit(0)}),
+	)
+
+	newT.Log("passing iteration")
+	teardown()
+
+	require.Empty(t, buf.String())
+}
+
+func TestFailedIterationLogsAreWrittenWhenFailed(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	newT, teardown := f1testing.NewTWithOptions("test",
+		f1testing.WithLogger(log.NewTestLogger(&buf)),
+		f1testing.WithIteration("3"),
+		f1testing.WithVUID(1),
+		withOptions(toptions.Options{FailedIterationLogs: log.NewFlushLimit(0)}),
+	)
+
+	newT.Log("request sent")
+	newT.Errorf("unexpected response")
+	require.Empty(t, buf.String())
+
+	teardown()
+
+	require.Equal(t,
+		"level=INFO msg=\"request sent\" iteration=3 vuid=1\n"+
+			"level=ERROR msg=\"unexpected response\" iteration=3 vuid=1\n",
+		buf.String())
+}
+
+func TestIterationLogsAreWrittenWhenPassedWithFlushAll(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	newT, teardown := f1testing.NewTWithOptions("test",
+		f1testing.WithLogger(log.NewTestLogger(&buf)),
+		f1testing.WithIteration("2"),
+		f1testing.WithVUID(1),
+		withOptions(toptions.Options{FailedIterationLogs: log.NewFlushAll()}),
+	)
+
+	newT.Log("request sent")
+	require.Empty(t, buf.String())
+
+	teardown()
+
+	require.Equal(t, "level=INFO msg=\"request sent\" iteration=2 vuid=1\n", buf.String())
+}
+
+func TestFailedIterationLogsAreLimited(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	limit := log.NewFlushLimit(1)
+	for i := range 3 {
+		newT, teardown := f1testing.NewTWithOptions("test",
+			f1testing.WithLogger(log.NewTestLogger(&buf)),
+			f1testing.WithIteration(strconv.Itoa(i)),
+			withOptions(toptions.Options{FailedIterationLogs: limit}),
+		)
+		newT.Errorf("failed")
+		teardown()
+	}
+
+	require.Equal(t,
+		"level=ERROR msg=failed iteration=0 vuid=0\n"+
+			"level=WARN msg=\"failed iteration log limit reached, logs of further failed iterations are discarded\"\n",
+		buf.String())
+}
+
+func TestStageDurationsAreRecordedWhenEnabled(t *testing.T) {
+	t.Parallel()
+
+	newT, teardown := f1testing.NewTWithOptions("test",
+		f1testing.WithLogger(log.NewDiscardLogger()),
+		f1testing.WithStageDurations(),
+	)
+	defer teardown()
+
+	newT.Time("first", func() {})
+	newT.Time("second", func() {})
+
+	stages := newT.StageDurations()
+	require.Len(t, stages, 2)
+	require.Equal(t, "first", stages[0].Name)
+	require.Equal(t, "second", stages[1].Name)
+
+	newT.Reset("1")
+	require.Empty(t, newT.StageDurations())
+}
+
+func TestTimeRecordsOnTheRunMetrics(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.New(true, nil)
+
+	newT, teardown := f1testing.NewTWithOptions("test",
+		f1testing.WithLogger(log.NewDiscardLogger()),
+		withOptions(toptions.Options{Metrics: m}),
+	)
+	defer teardown()
+
+	newT.Time("call", func() {})
+	newT.Time("call", func() {})
+
+	families, err := m.Registry.Gather()
+	require.NoError(t, err)
+
+	var count uint64
+	for _, family := range families {
+		if family.GetName() == "form3_loadtest_iteration" {
+			for _, metric := range family.GetMetric() {
+				count += metric.GetSummary().GetSampleCount()
+			}
+		}
+	}
+	require.Equal(t, uint64(2), count)
+}
+
+func TestRecordStage(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.New(true, nil)
+
+	newT, teardown := f1testing.NewTWithOptions("test",
+		f1testing.WithLogger(log.NewDiscardLogger()),
+		withOptions(toptions.Options{Metrics: m}),
+		f1testing.WithStageDurations(),
+	)
+	defer teardown()
+
+	newT.RecordStage("measured", 5*time.Millisecond)
+
+	require.Equal(t, []f1testing.StageDuration{{Name: "measured", Duration: 5 * time.Millisecond}},
+		newT.StageDurations())
+
+	families, err := m.Registry.Gather()
+	require.NoError(t, err)
+
+	var sum float64
+	for _, family := range families {
+		if family.GetName() == "form3_loadtest_iteration" {
+			for _, metric := range family.GetMetric() {
+				sum += metric.GetSummary().GetSampleSum()
+			}
+		}
+	}
+	require.InDelta(t, float64(5*time.Millisecond), sum, 0)
+}
+
+func TestTagAddsTagsToTheLogsOfTheIteration(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+
+	newT, teardown := f1testing.NewTWithOptions("test",
+		f1testing.WithLogger(logger),
+		f1testing.WithLogrusLogger(log.NewSlogLogrusLogger(logger)),
+	)
+	defer teardown()
+
+	newT.Tag("tenant", "acme")
+	newT.Tag("scheme", "sepa")
+	newT.Log("tagged")
+	newT.Logger().Info("tagged with logrus")
+	require.Equal(t, map[string]string{"tenant": "acme", "scheme": "sepa"}, newT.Tags())
+	require.Contains(t, buf.String(), "msg=tagged tags.scheme=sepa tags.tenant=acme")
+	require.Contains(t, buf.String(), "msg=\"tagged with logrus\" tags.scheme=sepa tags.tenant=acme")
+
+	newT.Reset("2")
+	buf.Reset()
+	newT.Log("untagged")
+	require.Empty(t, newT.Tags())
+	require.NotContains(t, buf.String(), "tags.")
+}
+
+func TestTagCanBeCalledWhileStagesAreRecordedByOtherGoroutines(t *testing.T) {
+	t.Parallel()
+
+	newT, teardown := f1testing.NewTWithOptions("test",
+		f1testing.WithLogger(log.NewDiscardLogger()),
+		withOptions(toptions.Options{Metrics: metrics.New(true, nil)}),
+	)
+	defer teardown()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range 100 {
+			newT.RecordStage("call", time.Millisecond)
+		}
+	}()
+	for i := range 100 {
+		newT.Tag("attempt", strconv.Itoa(i))
+	}
+	<-done
+
+	require.Equal(t, map[string]string{"attempt": "99"}, newT.Tags())
+}
+
+func TestParamReturnsTheParameterValue(t *testing.T) {
+	t.Parallel()
+
+	p, err := params.New([]params.Declared{{Name: "region", Default: "eu"}, {Name: "size", Default: "10"}},
+		map[string]string{"size": "20"})
+	require.NoError(t, err)
+
+	newT, teardown := f1testing.NewTWithOptions("test",
+		f1testing.WithLogger(log.NewDiscardLogger()),
+		withOptions(toptions.Options{Params: p}),
+	)
+	defer teardown()
+
+	require.Equal(t, "eu", newT.Param("region"))
+	require.Equal(t, 20, newT.ParamInt("size"))
+	require.Empty(t, newT.Param("missing"))
+}
+
+func TestVUReturnsTheVirtualUserState(t *testing.T) {
+	t.Parallel()
+
+	withVU, teardown := f1testing.NewTWithOptions("test",
+		f1testing.WithLogger(log.NewDiscardLogger()),
+		f1testing.WithVU("session"),
+	)
+	defer teardown()
+
+	withoutVU, teardown := f1testing.NewTWithOptions("test",
+		f1testing.WithLogger(log.NewDiscardLogger()),
+	)
+	defer teardown()
+
+	require.Equal(t, "session", withVU.VU())
+	require.Nil(t, withoutVU.VU())
+}
+
+func TestDataReturnsTheRowOfTheIteration(t *testing.T) {
+	t.Parallel()
+
+	feeder, err := data.Load("../../../internal/testdata/accounts.csv", data.Sequential, false, 1, 0)
+	require.NoError(t, err)
+
+	newT, teardown := f1testing.NewTWithOptions("test",
+		f1testing.WithLogger(log.NewDiscardLogger()),
+		f1testing.WithIteration("2"),
+		withOptions(toptions.Options{Feeder: feeder}),
+	)
+	defer teardown()
+
+	require.Equal(t, "acc-2", newT.Data()["account_id"])
+	newT.Reset("3")
+	require.Equal(t, "acc-3", newT.Data()["account_id"])
+}
+
+func TestDataFailsWithoutADataFile(t *testing.T) {
+	t.Parallel()
+
+	newT, teardown := f1testing.NewTWithOptions("test",
+		f1testing.WithLogger(log.NewDiscardLogger()),
+		f1testing.WithIteration("1"),
+	)
+	defer teardown()
+
+	done := make(chan struct{})
+	go func() {
+		defer catchPanics(done)
+		newT.Data()
+	}()
+	<-done
+
+	require.True(t, newT.Failed())
+	require.Contains(t, newT.FailureReason(), "the scenario doesn't declare a data file")
+}
+
+func TestParamIntFailsForNonIntegerValues(t *testing.T) {
+	t.Parallel()
+
+	p, err := params.New([]params.Declared{{Name: "size", Default: "large"}}, nil)
+	require.NoError(t, err)
+
+	newT, teardown := f1testing.NewTWithOptions("test",
+		f1testing.WithLogger(log.NewDiscardLogger()),
+		withOptions(toptions.Options{Params: p}),
+	)
+	defer teardown()
+
+	done := make(chan struct{})
+	go func() {
+		defer catchPanics(done)
+		newT.ParamInt("size")
+	}()
+	<-done
+
+	require.True(t, newT.Failed())
+	require.Contains(t, newT.FailureReason(), "parameter 'size' is not an integer")
+}
+
+func TestEventuallyRecordsTheTimeUntilTheCondition(t *testing.T) {
+	t.Parallel()
+
+	newT, teardown := f1testing.NewTWithOptions("test",
+		f1testing.WithLogger(log.NewDiscardLogger()),
+		f1testing.WithStageDurations(),
+	)
+	defer teardown()
+
+	calls := 0
+	attempts := newT.Eventually("settled", func() bool {
+		calls++
+		return calls == 3
+	}, time.Second, time.Millisecond)
+
+	require.Equal(t, 3, attempts)
+	require.False(t, newT.Failed())
+	stages := newT.StageDurations()
+	require.Len(t, stages, 1)
+	require.Equal(t, "settled", stages[0].Name)
+	// waits of 1ms then 2ms between the attempts
+	require.GreaterOrEqual(t, stages[0].Duration, 3*time.Millisecond)
+}
+
+func TestEventuallyRecordsTheAttempts(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.New(true, nil)
+	newT, teardown := f1testing.NewTWithOptions("test",
+		f1testing.WithLogger(log.NewDiscardLogger()),
+		withOptions(toptions.Options{Metrics: m}),
+	)
+	defer teardown()
+
+	calls := 0
+	newT.Eventually("settled", func() bool {
+		calls++
+		return calls == 2
+	}, time.Second, time.Millisecond)
+
+	families, err := m.Registry.Gather()
+	require.NoError(t, err)
+	recorded := false
+	for _, family := range families {
+		if family.GetName() != "form3_loadtest_retry_attempts" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "result" {
+					require.Equal(t, "met", label.GetValue())
+				}
+			}
+			require.InDelta(t, 2, metric.GetSummary().GetSampleSum(), 0)
+			recorded = true
+		}
+	}
+	require.True(t, recorded)
+}
+
+func TestEventuallyFailsWhenTheConditionIsNotMet(t *testing.T) {
+	t.Parallel()
+
+	newT, teardown := f1testing.NewTWithOptions("test",
+		f1testing.WithLogger(log.NewDiscardLogger()),
+		f1testing.WithStageDurations(),
+	)
+	defer teardown()
+
+	done := make(chan struct{})
+	go func() {
+		defer catchPanics(done)
+		newT.Eventually("settled", func() bool { return false }, 30*time.Millisecond, time.Millisecond)
+	}()
+	<-done
+
+	require.True(t, newT.Failed())
+	require.Regexp(t, `^settled: condition not met after \d+ attempts in 30ms$`, newT.FailureReason())
+	require.Len(t, newT.StageDurations(), 1)
+}
+
+func TestEventuallyStopsWhenTheRunIsInterrupted(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	newT, teardown := f1testing.NewTWithOptions("test",
+		f1testing.WithLogger(log.NewDiscardLogger()),
+		f1testing.WithContext(ctx),
+	)
+	defer teardown()
+
+	done := make(chan struct{})
+	go func() {
+		defer catchPanics(done)
+		newT.Eventually("settled", func() bool {
+			cancel()
+			return false
+		}, time.Hour, time.Minute)
+	}()
+	<-done
+
+	require.True(t, newT.Failed())
+	require.Equal(t, "settled: condition not met after 1 attempts, the run was interrupted", newT.FailureReason())
+}
+
+func TestRetryRecordsEachAttempt(t *testing.T) {
+	t.Parallel()
+
+	m := metrics.New(true, nil)
+	newT, teardown := f1testing.NewTWithOptions("test",
+		f1testing.WithLogger(log.NewDiscardLogger()),
+		withOptions(toptions.Options{Metrics: m}),
+		f1testing.WithStageDurations(),
+	)
+	defer teardown()
+
+	calls := 0
+	attempts := newT.Retry("call", f1testing.RetryPolicy{InitialBackoff: time.Millisecond, Jitter: 0.5}, func() error {
+		calls++
+		if calls < 3 {
+			return errors.New("unavailable")
+		}
+		return nil
+	})
+
+	require.Equal(t, 3, attempts)
+	require.False(t, newT.Failed())
+	require.Len(t, newT.StageDurations(), 3)
+
+	families, err := m.Registry.Gather()
+	require.NoError(t, err)
+	results := map[string]uint64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			switch family.GetName() {
+			case "form3_loadtest_iteration":
+				results[labels["result"]] += metric.GetSummary().GetSampleCount()
+			case "form3_loadtest_retry_attempts":
+				require.Equal(t, "success", labels["result"])
+				require.InDelta(t, 3, metric.GetSummary().GetSampleSum(), 0)
+			}
+		}
+	}
+	require.Equal(t, map[string]uint64{"fail": 2, "success": 1}, results)
+}
+
+func TestRetryFailsWhenTheAttemptsAreExhausted(t *testing.T) {
+	t.Parallel()
+
+	newT, teardown := f1testing.NewTWithOptions("test", f1testing.WithLogger(log.NewDiscardLogger()))
+	defer teardown()
+
+	calls := 0
+	done := make(chan struct{})
+	go func() {
+		defer catchPanics(done)
+		newT.Retry("call", f1testing.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}, func() error {
+			calls++
+			return errors.New("unavailable")
+		})
+	}()
+	<-done
+
+	require.Equal(t, 2, calls)
+	require.True(t, newT.Failed())
+	require.Equal(t, "call: all 2 attempts failed, the last with: unavailable", newT.FailureReason())
+}
+
+func TestRetryStopsOnErrorsWhichAreNotRetryable(t *testing.T) {
+	t.Parallel()
+
+	newT, teardown := f1testing.NewTWithOptions("test", f1testing.WithLogger(log.NewDiscardLogger()))
+	defer teardown()
+
+	errInvalid := errors.New("invalid request")
+	calls := 0
+	done := make(chan struct{})
+	go func() {
+		defer catchPanics(done)
+		newT.Retry("call", f1testing.RetryPolicy{
+			Retryable: func(err error) bool { return !errors.Is(err, errInvalid) },
+		}, func() error {
+			calls++
+			return errInvalid
+		})
+	}()
+	<-done
+
+	require.Equal(t, 1, calls)
+	require.Equal(t, "call: attempt 1 of 3 failed with an error which isn't retryable: invalid request",
+		newT.FailureReason())
+}
+
+func TestRetryStopsWhenTheRunIsInterrupted(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithCancel(t.Context())
+	newT, teardown := f1testing.NewTWithOptions("test",
+		f1testing.WithLogger(log.NewDiscardLogger()),
+		f1testing.WithContext(ctx),
+	)
+	defer teardown()
+
+	calls := 0
+	done := make(chan struct{})
+	go func() {
+		defer catchPanics(done)
+		newT.Retry("call", f1testing.RetryPolicy{InitialBackoff: time.Hour}, func() error {
+			calls++
+			time.AfterFunc(10*time.Millisecond, cancel)
+			return errors.New("unavailable")
+		})
+	}()
+	<-done
+
+	require.Equal(t, 1, calls)
+	require.Equal(t, "call: attempt 1 of 3 failed, the run was interrupted: unavailable", newT.FailureReason())
+}
+
+func TestRetryRetriesCanceledErrorsWhileTheRunGoesOn(t *testing.T) {
+	t.Parallel()
+
+	newT, teardown := f1testing.NewTWithOptions("test", f1testing.WithLogger(log.NewDiscardLogger()))
+	defer teardown()
+
+	calls := 0
+	done := make(chan struct{})
+	go func() {
+		defer catchPanics(done)
+		newT.Retry("call", f1testing.RetryPolicy{MaxAttempts: 2, InitialBackoff: time.Millisecond}, func() error {
+			calls++
+			return context.Canceled
+		})
+	}()
+	<-done
+
+	require.Equal(t, 2, calls)
+	require.Equal(t, "call: all 2 attempts failed, the last with: context canceled", newT.FailureReason())
+}
+
+func TestRetryDrawsTheJitterFromRand(t *testing.T) {
+	t.Parallel()
+
+	newT := func() *f1testing.T {
+		newT, teardown := f1testing.NewTWithOptions("test",
+			f1testing.WithLogger(log.NewDiscardLogger()),
+			f1testing.WithSeed(42),
+			f1testing.WithIteration("1"),
+		)
+		t.Cleanup(teardown)
+		return newT
+	}
+
+	retried := newT()
+	calls := 0
+	retried.Retry("call", f1testing.RetryPolicy{InitialBackoff: time.Millisecond, Jitter: 1}, func() error {
+		calls++
+		if calls < 2 {
+			return errors.New("unavailable")
+		}
+		return nil
+	})
+
+	// the jitter of the single backoff is the first draw, so the next draw is the second one
+	expected := newT()
+	expected.Rand().Float64()
+	require.Equal(t, expected.Rand().Uint64(), retried.Rand().Uint64())
+}
+
+func TestRandIsDerivedFromTheSeedIterationAndVUID(t *testing.T) {
+	t.Parallel()
+
+	draws := func(seed uint64, iteration string, vuid int) []uint64 {
+		newT, teardown := f1testing.NewTWithOptions("test",
+			f1testing.WithLogger(log.NewDiscardLogger()),
+			f1testing.WithSeed(seed),
+			f1testing.WithIteration(iteration),
+			f1testing.WithVUID(vuid),
+		)
+		defer teardown()
+
+		return []uint64{newT.Rand().Uint64(), newT.Rand().Uint64()}
+	}
+
+	require.Equal(t, draws(42, "1", 0), draws(42, "1", 0))
+	require.NotEqual(t, draws(42, "1", 0), draws(43, "1", 0))
+	require.NotEqual(t, draws(42, "1", 0), draws(42, "2", 0))
+	require.NotEqual(t, draws(42, "1", 0), draws(42, "1", 1))
+}
+
+func TestRandIsDerivedAgainAfterReset(t *testing.T) {
+	t.Parallel()
+
+	newT, teardown := f1testing.NewTWithOptions("test",
+		f1testing.WithLogger(log.NewDiscardLogger()),
+		f1testing.WithSeed(42),
+	)
+	defer teardown()
+
+	newT.Reset("1")
+	first := newT.Rand().Uint64()
+	newT.Reset("2")
+	second := newT.Rand().Uint64()
+	newT.Reset("1")
+
+	require.NotEqual(t, first, second)
+	require.Equal(t, first, newT.Rand().Uint64())
+}
+
 func catchPanics(done chan<- struct{}) {
 	_ = recover()
 	close(done)
@@ -212,3 +890,7 @@ func newT() (*f1testing.T, func()) {
 		f1testing.WithLogrusLogger(logrus),
 	)
 }
+
+func withOptions(options toptions.Options) f1testing.TOption {
+	return toptions.New(options).(f1testing.TOption) //nolint:forcetypeassert // always a f1testing.TOption
+}
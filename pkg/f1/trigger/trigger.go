@@ -0,0 +1,164 @@
+// Package trigger is the API for custom trigger modes, which are registered with F1.WithTrigger.
+//
+// A trigger mode decides how many iterations to start over time. Once registered, it is available as
+// a subcommand of `f1 run` and `f1 chart`, with the flags of its Builder:
+//
+//	f1.New().
+//		WithTrigger(trigger.Builder{
+//			Name:        "calendar",
+//			Description: "triggers iterations following business hours",
+//			Flags:       calendarFlags,
+//			New:         newCalendarTrigger,
+//		}).
+//		Add("myScenario", myScenario).
+//		Execute()
+package trigger
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/rate"
+	"github.com/form3tech-oss/f1/v2/internal/triggerflags"
+)
+
+const (
+	// FlagJitter is the name of the flag added by JitterFlag.
+	FlagJitter = triggerflags.FlagJitter
+	// FlagDistribution is the name of the flag added by DistributionFlag.
+	FlagDistribution = triggerflags.FlagDistribution
+	// FlagSeed is the name of the --seed flag of `f1 run`, read by Rand.
+	FlagSeed = triggerflags.FlagSeed
+)
+
+// RateFunction returns the number of iterations to start at a point in time.
+type RateFunction func(time.Time) int
+
+// Distribution is how iterations are spread within each iteration duration.
+type Distribution string
+
+const (
+	// NoneDistribution starts all iterations at the start of the iteration duration.
+	NoneDistribution = Distribution(api.NoneDistribution)
+	// RegularDistribution spreads iterations evenly over steps of 100ms.
+	RegularDistribution = Distribution(api.RegularDistribution)
+	// RandomDistribution spreads iterations randomly over steps of 100ms.
+	RandomDistribution = Distribution(api.RandomDistribution)
+)
+
+// Builder describes a custom trigger mode.
+type Builder struct {
+	// New creates the trigger from the parsed Flags.
+	New func(flags *pflag.FlagSet) (*Trigger, error)
+	// Flags adds the flags of the trigger mode, in addition to the common flags of `f1 run`.
+	Flags func(flags *pflag.FlagSet)
+	// Name is the name of the subcommand, such as "constant".
+	Name string
+	// Description is the short help of the subcommand.
+	Description string
+}
+
+// Trigger starts Rate(t) iterations at every IterationDuration.
+type Trigger struct {
+	Rate RateFunction
+	// Description is displayed at the start of the run.
+	Description string
+	// IterationDuration is the interval between calls of Rate.
+	IterationDuration time.Duration
+	// Duration limits the run, if it is shorter than --max-duration. Zero is unlimited.
+	Duration time.Duration
+}
+
+// JitterFlag adds the --jitter flag used by ApplyFlags.
+func JitterFlag(flags *pflag.FlagSet) {
+	triggerflags.JitterFlag(flags)
+}
+
+// DistributionFlag adds the --distribution flag used by ApplyFlags.
+func DistributionFlag(flags *pflag.FlagSet) {
+	triggerflags.DistributionFlag(flags)
+}
+
+// Rand returns a random number generator seeded with --seed, so that trigger modes making random
+// choices repeat them in runs with the same seed. It is safe for concurrent use.
+func Rand(flags *pflag.FlagSet) (*rand.Rand, error) {
+	random, err := triggerflags.Rand(flags)
+	if err != nil {
+		return nil, fmt.Errorf("seeding trigger: %w", err)
+	}
+
+	return random, nil
+}
+
+// WithJitter varies the rate randomly by up to jitter percent. Unlike ApplyFlags, it isn't seeded
+// with --seed.
+func WithJitter(rate RateFunction, jitter float64) RateFunction {
+	//nolint:gosec // G404: Use of weak random number generator - doesn't need to be secure
+	return RateFunction(api.WithJitter(api.RateFunction(rate), jitter, api.NewRand(rand.Uint64())))
+}
+
+// WithDistribution spreads the iterations started at each iterationDuration, and returns the new
+// iteration duration and rate.
+func WithDistribution(
+	distribution Distribution,
+	iterationDuration time.Duration,
+	rate RateFunction,
+) (time.Duration, RateFunction, error) {
+	distributedDuration, distributedRate, err := api.NewDistribution(
+		api.DistributionType(distribution), iterationDuration, api.RateFunction(rate), nil,
+	)
+	if err != nil {
+		return 0, nil, fmt.Errorf("new distribution: %w", err)
+	}
+
+	return distributedDuration, RateFunction(distributedRate), nil
+}
+
+// ApplyFlags applies the jitter and distribution set with the flags added by JitterFlag and
+// DistributionFlag, seeded with --seed, as the built-in trigger modes do.
+func ApplyFlags(
+	flags *pflag.FlagSet,
+	iterationDuration time.Duration,
+	rate RateFunction,
+) (time.Duration, RateFunction, error) {
+	jitter, err := flags.GetFloat64(FlagJitter)
+	if err != nil {
+		return 0, nil, fmt.Errorf("getting flag: %w", err)
+	}
+	distribution, err := flags.GetString(FlagDistribution)
+	if err != nil {
+		return 0, nil, fmt.Errorf("getting flag: %w", err)
+	}
+
+	random, err := Rand(flags)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	distributedDuration, distributedRate, err := api.NewDistribution(
+		api.DistributionType(distribution),
+		iterationDuration,
+		api.WithJitter(api.RateFunction(rate), jitter, random),
+		random.IntN,
+	)
+	if err != nil {
+		return 0, nil, fmt.Errorf("new distribution: %w", err)
+	}
+
+	return distributedDuration, RateFunction(distributedRate), nil
+}
+
+// ParseRate parses a rate such as "10/s" or "1/500ms" into the number of iterations and the
+// interval they are started at.
+func ParseRate(value string) (int, time.Duration, error) {
+	iterations, interval, err := rate.ParseRate(value)
+	if err != nil {
+		return 0, 0, fmt.Errorf("parsing rate: %w", err)
+	}
+
+	return iterations, interval, nil
+}
@@ -0,0 +1,80 @@
+package trigger_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/form3tech-oss/f1/v2/pkg/f1/trigger"
+)
+
+func TestApplyFlags_DistributesTheRate(t *testing.T) {
+	t.Parallel()
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	trigger.JitterFlag(flags)
+	trigger.DistributionFlag(flags)
+	require.NoError(t, flags.Parse([]string{"--distribution=regular"}))
+
+	iterationDuration, rate, err := trigger.ApplyFlags(flags, time.Second, func(time.Time) int { return 10 })
+	require.NoError(t, err)
+
+	assert.Equal(t, 100*time.Millisecond, iterationDuration)
+	total := 0
+	for range 10 {
+		total += rate(time.Now())
+	}
+	assert.Equal(t, 10, total)
+}
+
+func TestApplyFlags_FailsForUnknownDistributions(t *testing.T) {
+	t.Parallel()
+
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	trigger.JitterFlag(flags)
+	trigger.DistributionFlag(flags)
+	require.NoError(t, flags.Parse([]string{"--distribution=unknown"}))
+
+	_, _, err := trigger.ApplyFlags(flags, time.Second, func(time.Time) int { return 10 })
+	require.ErrorContains(t, err, "unable to parse distribution unknown")
+}
+
+func TestApplyFlags_RepeatsTheRandomChoicesOfASeed(t *testing.T) {
+	t.Parallel()
+
+	rates := func(seed string) []int {
+		flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		trigger.JitterFlag(flags)
+		trigger.DistributionFlag(flags)
+		// --seed is a flag of `f1 run`
+		flags.Uint64(trigger.FlagSeed, 0, "")
+		require.NoError(t, flags.Parse([]string{"--jitter=50", "--distribution=random", "--seed=" + seed}))
+
+		_, rate, err := trigger.ApplyFlags(flags, time.Second, func(time.Time) int { return 100 })
+		require.NoError(t, err)
+
+		rates := make([]int, 50)
+		for i := range rates {
+			rates[i] = rate(time.Now())
+		}
+		return rates
+	}
+
+	assert.Equal(t, rates("42"), rates("42"))
+	assert.NotEqual(t, rates("42"), rates("43"))
+}
+
+func TestParseRate(t *testing.T) {
+	t.Parallel()
+
+	iterations, interval, err := trigger.ParseRate("5/500ms")
+	require.NoError(t, err)
+	assert.Equal(t, 5, iterations)
+	assert.Equal(t, 500*time.Millisecond, interval)
+
+	_, _, err = trigger.ParseRate("five/s")
+	require.Error(t, err)
+}
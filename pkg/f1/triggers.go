@@ -0,0 +1,181 @@
+package f1
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/pflag"
+
+	"github.com/form3tech-oss/f1/v2/internal/trigger/api"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/constant"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/file"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/gaussian"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/ramp"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/staged"
+	"github.com/form3tech-oss/f1/v2/internal/trigger/users"
+	"github.com/form3tech-oss/f1/v2/internal/triggerflags"
+	"github.com/form3tech-oss/f1/v2/internal/ui"
+	f1trigger "github.com/form3tech-oss/f1/v2/pkg/f1/trigger"
+)
+
+// Trigger configures how iterations are started by Run. Triggers are created with the functions
+// named after the trigger modes of the run command, such as Constant.
+type Trigger struct {
+	builder func(output *ui.Output) api.Builder
+	// args are parsed by the flags of the trigger, as they would be on the command line
+	args []string
+}
+
+// TriggerOption sets an optional flag of a trigger.
+type TriggerOption func(*Trigger)
+
+// WithDistribution sets how the rate is distributed over steps of 100ms: none, regular or random.
+func WithDistribution(distribution string) TriggerOption {
+	return WithTriggerFlag(triggerflags.FlagDistribution, distribution)
+}
+
+// WithJitter varies the rate randomly by up to percent.
+func WithJitter(percent float64) TriggerOption {
+	return WithTriggerFlag(triggerflags.FlagJitter, strconv.FormatFloat(percent, 'f', -1, 64))
+}
+
+// WithThinkTime sets the time each user of the Users trigger waits after an iteration: a duration
+// such as "2s", a uniform range such as "1s-3s" or an exponential distribution such as "exp:2s".
+func WithThinkTime(thinkTime string) TriggerOption {
+	return WithTriggerFlag(users.FlagThinkTime, thinkTime)
+}
+
+// WithPacing sets the minimum time between the start of consecutive iterations of each user of the
+// Users trigger.
+func WithPacing(pacing time.Duration) TriggerOption {
+	return WithTriggerFlag(users.FlagPacing, pacing.String())
+}
+
+// WithTriggerFlag sets a flag of the trigger by its command line name, such as "iteration-frequency".
+func WithTriggerFlag(name, value string) TriggerOption {
+	return func(t *Trigger) {
+		t.args = append(t.args, fmt.Sprintf("--%s=%s", name, value))
+	}
+}
+
+// Constant starts iterations at a constant rate, such as "100/s".
+func Constant(rate string, options ...TriggerOption) Trigger {
+	return newTrigger(withoutOutput(constant.Rate), []string{"--rate=" + rate}, options)
+}
+
+// Staged starts iterations at rates which change over stages, such as "0s:1, 10s:100".
+func Staged(stages string, iterationFrequency time.Duration, options ...TriggerOption) Trigger {
+	return newTrigger(withoutOutput(staged.Rate), []string{
+		"--stages=" + stages,
+		"--iterationFrequency=" + iterationFrequency.String(),
+	}, options)
+}
+
+// Ramp changes the rate from startRate to endRate over rampDuration. A zero rampDuration uses
+// the maximum duration of the run.
+func Ramp(startRate, endRate string, rampDuration time.Duration, options ...TriggerOption) Trigger {
+	return newTrigger(withoutOutput(ramp.Rate), []string{
+		"--start-rate=" + startRate,
+		"--end-rate=" + endRate,
+		"--ramp-duration=" + rampDuration.String(),
+	}, options)
+}
+
+// Gaussian starts iterations following a normal distribution, which peaks at peakRate after peak
+// with the given standard deviation.
+func Gaussian(peakRate string, peak, standardDeviation time.Duration, options ...TriggerOption) Trigger {
+	return newTrigger(gaussian.Rate, []string{
+		"--peak-rate=" + peakRate,
+		"--peak=" + peak.String(),
+		"--standard-deviation=" + standardDeviation.String(),
+	}, options)
+}
+
+// Users runs iterations back to back from RunConfig.Concurrency users.
+func Users(options ...TriggerOption) Trigger {
+	return newTrigger(withoutOutput(users.Rate), nil, options)
+}
+
+// Custom starts iterations with a custom trigger mode. Its flags are set with WithTriggerFlag.
+func Custom(builder f1trigger.Builder, options ...TriggerOption) Trigger {
+	return newTrigger(func(*ui.Output) api.Builder {
+		return customBuilder(builder)
+	}, nil, options)
+}
+
+// File starts iterations as configured in a yaml file, see config-file-example.yaml. The file
+// also sets the scenario and the limits of the run, so they are ignored in RunConfig.
+func File(path string) Trigger {
+	return newTrigger(file.Rate, []string{path}, nil)
+}
+
+func newTrigger(builder func(*ui.Output) api.Builder, args []string, options []TriggerOption) Trigger {
+	t := Trigger{builder: builder, args: args}
+	for _, option := range options {
+		option(&t)
+	}
+
+	return t
+}
+
+func withoutOutput(builder func() api.Builder) func(*ui.Output) api.Builder {
+	return func(*ui.Output) api.Builder {
+		return builder()
+	}
+}
+
+// withCustomTriggers adds the custom trigger modes to the built-in ones.
+func withCustomTriggers(builders []api.Builder, custom []f1trigger.Builder) ([]api.Builder, error) {
+	names := make(map[string]struct{}, len(builders)+len(custom))
+	for _, builder := range builders {
+		names[strings.Fields(builder.Name)[0]] = struct{}{}
+	}
+
+	for _, builder := range custom {
+		if builder.Name == "" || builder.New == nil {
+			return nil, errors.New("custom trigger modes need a name and a New function")
+		}
+		if _, ok := names[builder.Name]; ok {
+			return nil, fmt.Errorf("trigger mode %s is already defined", builder.Name)
+		}
+		names[builder.Name] = struct{}{}
+
+		builders = append(builders, customBuilder(builder))
+	}
+
+	return builders, nil
+}
+
+func customBuilder(builder f1trigger.Builder) api.Builder {
+	flags := pflag.NewFlagSet(builder.Name, pflag.ContinueOnError)
+	if builder.Flags != nil {
+		builder.Flags(flags)
+	}
+
+	return api.Builder{
+		Name:        builder.Name + " <scenario>",
+		Description: builder.Description,
+		Flags:       flags,
+		New: func(flags *pflag.FlagSet) (*api.Trigger, error) {
+			t, err := builder.New(flags)
+			if err != nil {
+				return nil, fmt.Errorf("creating %s trigger: %w", builder.Name, err)
+			}
+			if t.Rate == nil || t.IterationDuration <= 0 {
+				return nil, fmt.Errorf("%s trigger needs a rate and an iteration duration", builder.Name)
+			}
+
+			rateFn := api.RateFunction(t.Rate)
+
+			return &api.Trigger{
+				Trigger:     api.NewIterationWorker(t.IterationDuration, rateFn),
+				DryRun:      rateFn,
+				Description: t.Description,
+				Duration:    t.Duration,
+			}, nil
+		},
+	}
+}